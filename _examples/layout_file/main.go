@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/layout"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+const path = "dashboard.yaml"
+
+func main() {
+	doc, err := layout.LoadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	app := ui.NewApp()
+	app.SetRoot(root(doc), true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go layout.Watch(ctx, path, 500*time.Millisecond, func(doc *layout.Document, err error) {
+		if err != nil {
+			p := widgets.NewParagraph()
+			p.Title = "Layout error"
+			p.Text = err.Error()
+			app.SetRoot(p, true)
+		} else {
+			app.SetRoot(root(doc), true)
+		}
+		app.Redraw()
+	})
+
+	if err := app.RunWithContext(ctx); err != nil {
+		log.Fatalf("App run failed: %v", err)
+	}
+}
+
+// root wraps the document root so that the application can focus it.
+func root(doc *layout.Document) ui.Widget {
+	if w, ok := doc.Root.(ui.Widget); ok {
+		return w
+	}
+	g := ui.NewGrid()
+	g.Set(ui.NewRow(1, doc.Root))
+	return g
+}
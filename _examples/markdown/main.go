@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+const doc = `# gotui
+
+A **terminal UI** library for Go, with *charts*, ~~few~~ many widgets and
+` + "`Markdown`" + ` rendering. See the [repository](https://github.com/metaspartan/gotui).
+
+## Lists
+
+- Widgets
+  - Charts
+  - Inputs
+- Layouts
+- Themes
+
+1. Build a tree of widgets
+2. Hand it to an Application
+3. Call Run
+
+## Code
+
+` + "```go" + `
+app := ui.NewApp()
+app.SetRoot(md, true)
+` + "```" + `
+
+> Block quotes keep their bar
+> when they wrap across several lines of the terminal.
+
+---
+
+| Widget   | Scrolls | Themed |
+|----------|---------|--------|
+| Markdown | yes     | yes    |
+| Table    | yes     | yes    |
+| Plot     | no      | yes    |
+`
+
+func main() {
+	app := ui.NewApp()
+
+	md := widgets.NewMarkdown()
+	md.Title = "Markdown (wheel, arrows, PgUp/PgDn, Home/End)"
+	md.Text = doc
+
+	app.SetRoot(md, true)
+	if err := app.Run(); err != nil {
+		log.Fatalf("App run failed: %v", err)
+	}
+}
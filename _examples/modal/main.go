@@ -46,6 +46,9 @@ func NewApp() *App {
 	modal.TextStyle.Bg = ui.ColorBlack
 	modal.TextStyle.Fg = ui.ColorWhite
 	modal.ActiveButtonIndex = 0
+	shade := ui.WithAlpha(ui.ColorBlack, 0.5)
+	modal.Backdrop = ui.NewStyle(shade, shade)
+	modal.Shadow = ui.NewStyle(shade, shade)
 
 	app := &App{
 		p:     p,
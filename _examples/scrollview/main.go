@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func main() {
+	app := ui.NewApp()
+
+	stack := widgets.NewFlex()
+	stack.Border = false
+	for i := range 8 {
+		plot := widgets.NewPlot()
+		plot.Title = fmt.Sprintf("Chart %d", i+1)
+		data := make([]float64, 60)
+		for j := range data {
+			data[j] = math.Sin(float64(j+i*7) / 5)
+		}
+		plot.Data = [][]float64{data}
+		stack.AddItem(plot, 10, 0, false)
+	}
+
+	sv := widgets.NewScrollView(stack)
+	sv.Title = "ScrollView (wheel, arrows, PgUp/PgDn, Home/End)"
+	sv.ContentHeight = 82
+	sv.ContentWidth = 120
+
+	app.SetRoot(sv, true)
+	if err := app.Run(); err != nil {
+		log.Fatalf("App run failed: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func main() {
+	app := ui.NewApp()
+
+	files := widgets.NewList()
+	files.Title = "Files"
+	files.Rows = []string{"main.go", "go.mod", "README.md"}
+
+	editor := widgets.NewParagraph()
+	editor.Title = "Editor"
+	editor.Text = "Drag the dividers with the mouse.\n[ and ] move the selected divider, \\ selects the next one.\nc collapses the file list."
+
+	logs := widgets.NewParagraph()
+	logs.Title = "Log"
+
+	right := widgets.NewSplitPane(ui.LayoutVertical, editor, logs)
+	right.Border = false
+	right.Panes[1].MinSize = 3
+
+	root := widgets.NewSplitPane(ui.LayoutHorizontal, files, right)
+	root.Border = false
+	root.Panes[0].Ratio = 0.25
+	root.Panes[1].Ratio = 0.75
+	root.Panes[0].MinSize = 12
+	root.SetFocusedPane(1)
+	root.OnResize = func(st widgets.SplitPaneState) {
+		logs.Text = fmt.Sprintf("ratios saved: %.2f", st.Ratios)
+	}
+
+	app.SetRoot(&collapser{SplitPane: root}, true)
+	if err := app.Run(); err != nil {
+		log.Fatalf("App run failed: %v", err)
+	}
+}
+
+type collapser struct {
+	*widgets.SplitPane
+}
+
+func (c *collapser) HandleEvent(e ui.Event) bool {
+	if e.ID == "c" {
+		c.ToggleCollapse(0)
+		return true
+	}
+	return c.SplitPane.HandleEvent(e)
+}
@@ -1,105 +1,20 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"math"
 	"math/rand"
-	"sync"
 	"time"
 
-	"github.com/gliderlabs/ssh"
-
 	ui "github.com/metaspartan/gotui/v5"
+	uissh "github.com/metaspartan/gotui/v5/ssh"
 	"github.com/metaspartan/gotui/v5/widgets"
 )
 
-// ---- SSH -> io.ReadWriter adapter ----
-
-type sessionTTY struct {
-	sess ssh.Session
-
-	mu       sync.RWMutex
-	w, h     int
-	resizeCh chan<- bool
-
-	winCh  <-chan ssh.Window
-	closed chan struct{}
-}
-
-func newSessionTTY(sess ssh.Session) (*sessionTTY, error) {
-	pty, winCh, ok := sess.Pty()
-	if !ok {
-		return nil, fmt.Errorf("no PTY requested (try: ssh -tt host -p 2222)")
-	}
-
-	t := &sessionTTY{
-		sess:   sess,
-		w:      pty.Window.Width,
-		h:      pty.Window.Height,
-		winCh:  winCh,
-		closed: make(chan struct{}),
-	}
-
-	go func() {
-		for {
-			select {
-			case <-t.closed:
-				return
-			case win, ok := <-t.winCh:
-				if !ok {
-					return
-				}
-				t.mu.Lock()
-				t.w, t.h = win.Width, win.Height
-				ch := t.resizeCh
-				t.mu.Unlock()
-				if ch != nil {
-					select {
-					case ch <- true:
-					default:
-					}
-				}
-			}
-		}
-	}()
-
-	return t, nil
-}
-
-func (t *sessionTTY) Size() (int, int) {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-	return t.w, t.h
-}
-
-// NotifyResize implements the tcell v3 Tty interface
-func (t *sessionTTY) NotifyResize(ch chan<- bool) {
-	t.mu.Lock()
-	defer t.mu.Unlock()
-	t.resizeCh = ch
-}
-
-func (t *sessionTTY) WindowSize() (int, int, error) {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-	return t.w, t.h, nil
-}
-
-func (t *sessionTTY) Read(p []byte) (int, error)  { return t.sess.Read(p) }
-func (t *sessionTTY) Write(p []byte) (int, error) { return t.sess.Write(p) }
-
-func (t *sessionTTY) Close() error {
-	select {
-	case <-t.closed:
-	default:
-		close(t.closed)
-	}
-	return t.sess.Close()
-}
-
 type dashboard struct {
-	app  *ui.Backend
+	ctx  *ui.Context
 	grid *ui.Grid
 
 	// widgets we mutate over time / events
@@ -113,11 +28,11 @@ type dashboard struct {
 	tickCount int
 }
 
-func newDashboard(app *ui.Backend) *dashboard {
-	d := &dashboard{app: app}
+func newDashboard(ctx *ui.Context) *dashboard {
+	d := &dashboard{ctx: ctx}
 
 	// Header
-	p := widgets.NewParagraph()
+	p := widgets.NewParagraphWithContext(ctx)
 	p.Title = "gotui Dashboard"
 	p.Text = "PRESS q TO QUIT | Multi-User SSH Demo"
 	p.TextStyle.Fg = ui.ColorWhite
@@ -129,19 +44,19 @@ func newDashboard(app *ui.Backend) *dashboard {
 
 	// Sparklines
 	slData := make([]float64, 200)
-	d.sl = widgets.NewSparkline()
+	d.sl = widgets.NewSparklineWithContext(ctx)
 	d.sl.Data = slData
 	d.sl.LineColor = ui.ColorGreen
 	d.sl.TitleStyle.Fg = ui.ColorWhite
 	d.sl.MaxVal = 100
 
-	d.sl2 = widgets.NewSparkline()
+	d.sl2 = widgets.NewSparklineWithContext(ctx)
 	d.sl2.Data = slData
 	d.sl2.LineColor = ui.ColorMagenta
 	d.sl2.TitleStyle.Fg = ui.ColorWhite
 	d.sl2.MaxVal = 100
 
-	slg := widgets.NewSparklineGroup(d.sl, d.sl2)
+	slg := widgets.NewSparklineGroupWithContext(ctx, d.sl, d.sl2)
 	slg.Title = "CPU Usage"
 	slg.TitleStyle.Fg = ui.ColorGreen
 	slg.BorderStyle.Fg = ui.ColorGreen
@@ -149,7 +64,7 @@ func newDashboard(app *ui.Backend) *dashboard {
 	slg.BorderRounded = true
 
 	// Line gauges
-	d.lg1 = widgets.NewLineGauge()
+	d.lg1 = widgets.NewLineGaugeWithContext(ctx)
 	d.lg1.Title = "Memory"
 	d.lg1.Percent = 45
 	d.lg1.BarRune = '■'
@@ -157,7 +72,7 @@ func newDashboard(app *ui.Backend) *dashboard {
 	d.lg1.TitleStyle.Fg = ui.ColorYellow
 	d.lg1.BorderRounded = true
 
-	d.lg2 = widgets.NewLineGauge()
+	d.lg2 = widgets.NewLineGaugeWithContext(ctx)
 	d.lg2.Title = "Load"
 	d.lg2.Percent = 60
 	d.lg2.BarRune = '▰'
@@ -167,7 +82,7 @@ func newDashboard(app *ui.Backend) *dashboard {
 	d.lg2.BorderRounded = true
 
 	// Bar chart
-	d.bc = widgets.NewBarChart()
+	d.bc = widgets.NewBarChartWithContext(ctx)
 	d.bc.Title = "Network Traffic"
 	d.bc.TitleBottom = "MB/s"
 	d.bc.TitleBottomAlignment = ui.AlignRight
@@ -183,7 +98,7 @@ func newDashboard(app *ui.Backend) *dashboard {
 	d.bc.MaxVal = 10
 
 	// Pie chart
-	pc := widgets.NewPieChart()
+	pc := widgets.NewPieChartWithContext(ctx)
 	pc.Title = "Disk Usage"
 	pc.Data = []float64{10, 20, 30, 40}
 	pc.Colors = []ui.Color{ui.ColorRed, ui.ColorYellow, ui.ColorGreen, ui.ColorBlue}
@@ -197,7 +112,7 @@ func newDashboard(app *ui.Backend) *dashboard {
 	d.plotData[0] = make([]float64, 50)
 	d.plotData[1] = make([]float64, 50)
 
-	d.plot = widgets.NewPlot()
+	d.plot = widgets.NewPlotWithContext(ctx)
 	d.plot.Title = "Response Time"
 	d.plot.TitleBottom = "(ms)"
 	d.plot.Data = d.plotData
@@ -210,7 +125,7 @@ func newDashboard(app *ui.Backend) *dashboard {
 	d.plot.BorderRounded = true
 
 	// Logs list
-	d.logs = widgets.NewList()
+	d.logs = widgets.NewListWithContext(ctx)
 	d.logs.Title = "System Logs"
 	d.logs.Rows = []string{
 		"[INFO] System started",
@@ -231,7 +146,7 @@ func newDashboard(app *ui.Backend) *dashboard {
 	d.logs.BorderRounded = true
 
 	// Gauge
-	d.g = widgets.NewGauge()
+	d.g = widgets.NewGaugeWithContext(ctx)
 	d.g.Title = "Download"
 	d.g.Percent = 50
 	d.g.BarColor = ui.ColorGreen
@@ -240,7 +155,7 @@ func newDashboard(app *ui.Backend) *dashboard {
 	d.g.BorderRounded = true
 
 	// Grid
-	d.grid = ui.NewGrid()
+	d.grid = ui.NewGridWithContext(ctx)
 	d.grid.Set(
 		ui.NewRow(1.0/10,
 			ui.NewCol(1.0, p),
@@ -266,12 +181,6 @@ func newDashboard(app *ui.Backend) *dashboard {
 	return d
 }
 
-func (d *dashboard) onResize(w, h int) {
-	d.grid.SetRect(0, 0, w, h)
-	d.app.Clear()
-	d.app.Render(d.grid)
-}
-
 func (d *dashboard) onTick() (dirty bool) {
 	d.tickCount++
 
@@ -304,75 +213,64 @@ func (d *dashboard) onTick() (dirty bool) {
 	return true
 }
 
-// ---- SSH session runner ----
-
-func runDashboardOverSSH(sess ssh.Session) {
-	tty, err := newSessionTTY(sess)
-	if err != nil {
-		fmt.Fprintln(sess.Stderr(), err)
-		return
-	}
-	defer tty.Close()
-
-	// Use NewBackend - creates isolated state per session!
-	app, err := ui.NewBackend(&ui.InitConfig{
-		CustomTTY: tty,
-	})
-	if err != nil {
-		fmt.Fprintln(sess.Stderr(), "init:", err)
-		return
-	}
-	defer app.Close()
-
-	d := newDashboard(app)
-	w, h := tty.Size()
-	if w <= 0 {
-		w = 80
-	}
-	if h <= 0 {
-		h = 24
-	}
-	d.onResize(w, h)
-
-	events := app.PollEventsWithContext(sess.Context())
+func (d *dashboard) run(ctx context.Context) {
 	ticker := time.NewTicker(150 * time.Millisecond) // ~6-7 FPS feels nicer over SSH
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-sess.Context().Done():
+		case <-ctx.Done():
 			return
-
-		case e, ok := <-events:
-			if !ok {
-				return // channel closed
+		case <-ticker.C:
+			d.grid.Lock()
+			dirty := d.onTick()
+			d.grid.Unlock()
+			if dirty {
+				d.ctx.Render(d.grid)
 			}
-			switch e.ID {
-			case "q", "<C-c>", "<Escape>":
-				return
+		}
+	}
+}
 
-			case "<Resize>":
-				r := e.Payload.(ui.Resize)
-				d.onResize(r.Width, r.Height)
+// dashboardRoot routes mouse wheel events to the log list.
+type dashboardRoot struct {
+	*ui.Grid
+	logs *widgets.List
+}
 
-			case "<MouseWheelUp>":
-				d.logs.ScrollUp()
-				d.app.Render(d.grid)
+func (r *dashboardRoot) HandleEvent(e ui.Event) bool {
+	switch e.ID {
+	case "<MouseWheelUp>":
+		r.logs.ScrollUp()
+		return true
+	case "<MouseWheelDown>":
+		r.logs.ScrollDown()
+		return true
+	}
+	return false
+}
 
-			case "<MouseWheelDown>":
-				d.logs.ScrollDown()
-				d.app.Render(d.grid)
-			}
+// ---- SSH session factory ----
 
-		case <-ticker.C:
-			if d.onTick() {
-				d.app.Render(d.grid)
-			}
-		}
-	}
+func newDashboardApp(sess *uissh.Session) *ui.Application {
+	// sess.UI is isolated per session!
+	d := newDashboard(sess.UI)
+	go d.run(sess.Context())
+
+	app := ui.NewAppWithContext(sess.UI)
+	app.SetRoot(&dashboardRoot{Grid: d.grid, logs: d.logs}, true)
+	return app
 }
 
 func main() {
-	ssh.Handle(runDashboardOverSSH)
-	log.Fatal(ssh.ListenAndServe(":2222", nil, ssh.HostKeyFile("hostkey")))
+	srv := uissh.NewServer(":2222", newDashboardApp)
+	srv.IdleTimeout = 10 * time.Minute
+	srv.MaxSessions = 32
+
+	key, err := uissh.LoadHostKey("hostkey")
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv.AddHostKey(key)
+	log.Fatal(srv.ListenAndServe())
 }
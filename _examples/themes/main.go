@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+// Press t to switch between the built-in themes and s to save the current
+// one to theme.toml. Pass a theme file to start with it.
+func main() {
+	app := ui.NewApp()
+	names := ui.BuiltinThemeNames()
+	current := 0
+
+	help := widgets.NewParagraph()
+	help.Title = "Themes"
+
+	bars := widgets.NewBarChart()
+	bars.Title = "Requests"
+	bars.Data = []float64{3, 5, 2, 7, 4}
+	bars.Labels = []string{"Mon", "Tue", "Wed", "Thu", "Fri"}
+
+	cpu := widgets.NewGauge()
+	cpu.Title = "CPU"
+	cpu.Percent = 42
+
+	hosts := widgets.NewList()
+	hosts.Title = "Hosts"
+	hosts.Rows = []string{"alpha", "bravo", "charlie"}
+
+	grid := ui.NewGrid()
+	grid.Set(
+		ui.NewRow(0.2, help),
+		ui.NewRow(0.5, ui.NewCol(0.6, bars), ui.NewCol(0.4, hosts)),
+		ui.NewRow(0.3, cpu),
+	)
+
+	status := func(msg string) {
+		help.Text = fmt.Sprintf("theme: %s. t switches theme, s saves it. %s", names[current], msg)
+	}
+	if len(os.Args) > 1 {
+		t, err := ui.LoadTheme(os.Args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		app.SetTheme(t)
+		names[current] = os.Args[1]
+	}
+	status("")
+
+	app.SetRoot(&themeKeys{Grid: grid, handle: func(id string) bool {
+		switch id {
+		case "t":
+			names = ui.BuiltinThemeNames()
+			current = (current + 1) % len(names)
+			t, _ := ui.BuiltinTheme(names[current])
+			app.SetTheme(t)
+			status("")
+		case "s":
+			if err := ui.SaveTheme("theme.toml", *app.Context.CurrentTheme()); err != nil {
+				status(err.Error())
+			} else {
+				status("saved to theme.toml")
+			}
+		default:
+			return false
+		}
+		return true
+	}}, true)
+	if err := app.Run(); err != nil {
+		log.Fatalf("App run failed: %v", err)
+	}
+}
+
+type themeKeys struct {
+	*ui.Grid
+	handle func(string) bool
+}
+
+func (k *themeKeys) HandleEvent(e ui.Event) bool {
+	return k.handle(e.ID)
+}
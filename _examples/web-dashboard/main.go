@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/web"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func newDashboardApp(sess *web.Session) *ui.Application {
+	ctx := sess.UI
+
+	header := widgets.NewParagraphWithContext(ctx)
+	header.Title = "gotui in the browser"
+	header.Text = fmt.Sprintf("Mode: [%s](fg:cyan)  Remote: %s  Press q to quit.", sess.Mode, sess.RemoteAddr)
+
+	gauge := widgets.NewGaugeWithContext(ctx)
+	gauge.Title = "Progress"
+	gauge.BarColor = ui.ColorGreen
+
+	plot := widgets.NewPlotWithContext(ctx)
+	plot.Title = "Sine"
+	plot.Data = [][]float64{make([]float64, 100)}
+	plot.LineColors = []ui.Color{ui.ColorYellow}
+
+	grid := ui.NewGridWithContext(ctx)
+	grid.Set(
+		ui.NewRow(1.0/5, ui.NewCol(1.0, header)),
+		ui.NewRow(1.0/5, ui.NewCol(1.0, gauge)),
+		ui.NewRow(3.0/5, ui.NewCol(1.0, plot)),
+	)
+
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for tick := 0; ; tick++ {
+			select {
+			case <-sess.Context().Done():
+				return
+			case <-ticker.C:
+				grid.Lock()
+				gauge.Percent = tick % 101
+				for i := range plot.Data[0] {
+					plot.Data[0][i] = math.Sin(float64(i+tick) / 8)
+				}
+				grid.Unlock()
+				ctx.Render(grid)
+			}
+		}
+	}()
+
+	app := ui.NewAppWithContext(ctx)
+	app.SetRoot(grid, true)
+	return app
+}
+
+func main() {
+	srv := web.NewServer(newDashboardApp)
+	srv.MaxSessions = 32
+
+	log.Println("open http://localhost:8080 (add ?mode=cells for the built-in renderer)")
+	log.Fatal(http.ListenAndServe(":8080", srv))
+}
@@ -0,0 +1,81 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func TestPlace(t *testing.T) {
+	area := image.Rect(10, 5, 30, 15)
+	cases := []struct {
+		anchor ui.Anchor
+		w, h   int
+		dx, dy int
+		want   image.Rectangle
+	}{
+		{ui.AnchorTopLeft, 4, 2, 0, 0, image.Rect(10, 5, 14, 7)},
+		{ui.AnchorTopRight, 4, 2, -1, 1, image.Rect(25, 6, 29, 8)},
+		{ui.AnchorCenter, 4, 2, 0, 0, image.Rect(18, 9, 22, 11)},
+		{ui.AnchorBottom, 4, 2, 0, 0, image.Rect(18, 13, 22, 15)},
+		{ui.AnchorLeft, 0, 2, 0, 0, image.Rect(10, 9, 10, 11)},
+		{ui.AnchorLeft, ui.SizeFill, 2, 0, 0, image.Rect(10, 9, 30, 11)},
+		{ui.AnchorBottomRight, 50, 50, 0, 0, area},
+	}
+	for _, c := range cases {
+		if got := ui.Place(area, c.anchor, c.w, c.h, c.dx, c.dy); got != c.want {
+			t.Errorf("Place(%d, %d, %d, %d, %d) = %v, want %v", c.anchor, c.w, c.h, c.dx, c.dy, got, c.want)
+		}
+	}
+}
+
+func TestAbsoluteResize(t *testing.T) {
+	a := widgets.NewAbsolute()
+	badge := ui.NewBlock()
+	a.Add(badge, ui.AnchorTopRight, 6, 3).OffsetX = -1
+	legend := ui.NewBlock()
+	a.Add(legend, ui.AnchorBottomLeft, 10, 4)
+
+	a.SetRect(-1, -1, 41, 21) // Inner is 0,0 - 40,20
+	a.Draw(ui.NewBuffer(a.Rectangle))
+	if badge.Rectangle != image.Rect(33, 0, 39, 3) || legend.Rectangle != image.Rect(0, 16, 10, 20) {
+		t.Fatalf("placed at %v and %v", badge.Rectangle, legend.Rectangle)
+	}
+
+	a.SetRect(-1, -1, 61, 11)
+	a.Draw(ui.NewBuffer(a.Rectangle))
+	if badge.Rectangle != image.Rect(53, 0, 59, 3) || legend.Rectangle != image.Rect(0, 6, 10, 10) {
+		t.Errorf("after resize placed at %v and %v", badge.Rectangle, legend.Rectangle)
+	}
+}
+
+type absoluteProbe struct {
+	ui.Block
+	name string
+	hit  *string
+}
+
+func (p *absoluteProbe) HandleEvent(ui.Event) bool {
+	*p.hit = p.name
+	return true
+}
+
+func TestAbsoluteMouseTopmost(t *testing.T) {
+	a := widgets.NewAbsolute()
+	var hit string
+	a.Add(&absoluteProbe{Block: *ui.NewBlock(), name: "under", hit: &hit}, ui.AnchorCenter, 10, 3)
+	a.Add(&absoluteProbe{Block: *ui.NewBlock(), name: "over", hit: &hit}, ui.AnchorCenter, 6, 3)
+	a.SetRect(-1, -1, 21, 11)
+	a.Draw(ui.NewBuffer(a.Rectangle))
+
+	a.HandleEvent(ui.Event{Type: ui.MouseEvent, ID: "<MouseLeft>", Payload: ui.Mouse{X: 10, Y: 5}})
+	if hit != "over" {
+		t.Errorf("event went to %q, want the top item", hit)
+	}
+	a.HandleEvent(ui.Event{Type: ui.MouseEvent, ID: "<MouseLeft>", Payload: ui.Mouse{X: 6, Y: 5}})
+	if hit != "under" {
+		t.Errorf("event went to %q, want the item below", hit)
+	}
+}
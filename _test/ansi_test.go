@@ -0,0 +1,86 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func TestParseANSIColors(t *testing.T) {
+	cells := ui.ParseANSI("\x1b[31ma\x1b[1;92mb\x1b[38;5;208mc\x1b[48;2;1;2;3md\x1b[38:2::4:5:6me\x1b[0mf")
+	want := []ui.Style{
+		{Fg: tcell.PaletteColor(1), Bg: ui.ColorClear},
+		{Fg: tcell.PaletteColor(10), Bg: ui.ColorClear, Modifier: ui.ModifierBold},
+		{Fg: tcell.PaletteColor(208), Bg: ui.ColorClear, Modifier: ui.ModifierBold},
+		{Fg: tcell.PaletteColor(208), Bg: ui.NewRGBColor(1, 2, 3), Modifier: ui.ModifierBold},
+		{Fg: ui.NewRGBColor(4, 5, 6), Bg: ui.NewRGBColor(1, 2, 3), Modifier: ui.ModifierBold},
+		ui.StyleClear,
+	}
+	if got := ui.CellsToString(cells); got != "abcdef" {
+		t.Fatalf("text = %q", got)
+	}
+	for i, style := range want {
+		if cells[i].Style != style {
+			t.Errorf("cell %d (%q): style = %+v, want %+v", i, cells[i].Text(), cells[i].Style, style)
+		}
+	}
+}
+
+func TestParseANSIResets(t *testing.T) {
+	base := ui.NewStyle(ui.ColorWhite, ui.ColorBlack)
+	cells := ui.ParseANSIWithStyle("\x1b[3;7;41ma\x1b[23;27mb\x1b[49mc\x1b[md", base)
+	if s := cells[0].Style; s.Modifier != ui.ModifierItalic|ui.ModifierReverse || s.Bg != tcell.PaletteColor(1) {
+		t.Errorf("a = %+v", s)
+	}
+	if s := cells[1].Style; s.Modifier != 0 || s.Bg != tcell.PaletteColor(1) {
+		t.Errorf("b = %+v", s)
+	}
+	if s := cells[2].Style; s != base {
+		t.Errorf("c = %+v, want the default background back", s)
+	}
+	if s := cells[3].Style; s != base {
+		t.Errorf("d = %+v, want the default style back", s)
+	}
+}
+
+func TestParseANSIStripsOtherSequences(t *testing.T) {
+	for in, want := range map[string]string{
+		"a\x1b[2J\x1b[1;1Hb\x1b[Kc":                       "abc",
+		"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\a": "link",
+		"\x1b(Bx\x1b7y\x1b8\r\n\tz\a":                     "xy\n\tz",
+		"cut off \x1b[38;5":                               "cut off ",
+		"\u009b32mcsi":                                    "csi",
+	} {
+		if got := ui.CellsToString(ui.ParseANSI(in)); got != want {
+			t.Errorf("%q: got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWidgetANSITextMode(t *testing.T) {
+	p := widgets.NewParagraph()
+	p.TextMode = ui.TextANSI
+	p.Text = "\x1b[32mok\x1b[0m [x](fg:red)"
+	p.SetRect(0, 0, 20, 3)
+	buf := ui.NewBuffer(image.Rect(0, 0, 20, 3))
+	p.Draw(buf)
+	if c := buf.GetCell(image.Pt(1, 1)); c.Rune != 'o' || c.Style.Fg != tcell.PaletteColor(2) {
+		t.Errorf("first cell = %+v", c)
+	}
+	if c := buf.GetCell(image.Pt(4, 1)); c.Rune != '[' {
+		t.Errorf("markup should stay literal in ANSI mode, got %q", c.Rune)
+	}
+
+	tb := widgets.NewTable()
+	tb.TextMode = ui.TextPlain
+	tb.Rows = [][]string{{"[x](fg:red)"}}
+	tb.SetRect(0, 0, 20, 3)
+	buf = ui.NewBuffer(image.Rect(0, 0, 20, 3))
+	tb.Draw(buf)
+	if c := buf.GetCell(image.Pt(1, 1)); c.Rune != '[' {
+		t.Errorf("plain table cell starts with %q", c.Rune)
+	}
+}
@@ -0,0 +1,164 @@
+package gotui_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func bidiCells(s string) []ui.Cell {
+	return ui.StringToStyledCells(s, ui.StyleClear)
+}
+
+func bidiRow(buf *ui.Buffer, y, x0, x1 int) string {
+	var sb strings.Builder
+	for x := x0; x < x1; x++ {
+		sb.WriteRune(buf.GetCell(image.Pt(x, y)).Rune)
+	}
+	return sb.String()
+}
+
+func TestReorderCells(t *testing.T) {
+	tests := []struct {
+		text string
+		dir  ui.Direction
+		want string
+	}{
+		{"abc שלום def", ui.DirectionAuto, "abc םולש def"},
+		{"שלום abc 123 עולם", ui.DirectionAuto, "םלוע abc 123 םולש"},
+		{"123 שלום", ui.DirectionAuto, "םולש 123"},
+		{"שלום, world!", ui.DirectionAuto, "!world ,םולש"},
+		{"(שלום)", ui.DirectionAuto, "(םולש)"},
+		{"مرحبا ١٢٣", ui.DirectionAuto, "١٢٣ ابحرم"},
+		{"a (b) c", ui.DirectionAuto, "a (b) c"},
+		{"abc", ui.DirectionRTL, "abc"},
+		{"abc!", ui.DirectionRTL, "!abc"},
+	}
+	for _, tt := range tests {
+		got := ui.CellsToString(ui.ReorderCells(bidiCells(tt.text), tt.dir))
+		if got != tt.want {
+			t.Errorf("ReorderCells(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestReorderCellsKeepsStyles(t *testing.T) {
+	cells := ui.ParseStyles("[אב](fg:red)ג", ui.StyleClear)
+	got := ui.ReorderCells(cells, ui.DirectionAuto)
+	if ui.CellsToString(got) != "גבא" {
+		t.Fatalf("order = %q", ui.CellsToString(got))
+	}
+	if got[0].Style.Fg == ui.ColorRed || got[1].Style.Fg != ui.ColorRed || got[2].Style.Fg != ui.ColorRed {
+		t.Errorf("styles did not follow their letters")
+	}
+}
+
+func TestMoveCaret(t *testing.T) {
+	// Logical "ab אבג": visual "ab גבא". Moving right visits every caret
+	// position once, left to right on screen.
+	cells := bidiCells("ab אבג")
+	caret := 0
+	var visited []int
+	for range 7 {
+		visited = append(visited, caret)
+		caret = ui.MoveCaret(cells, ui.DirectionLTR, caret, 1)
+	}
+	want := []int{0, 1, 2, 5, 4, 3, 6}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Fatalf("carets = %v, want %v", visited, want)
+		}
+	}
+	if got := ui.MoveCaret(cells, ui.DirectionLTR, 6, 1); got != 6 {
+		t.Errorf("moving right from the end = %d, want 6", got)
+	}
+
+	// In right-to-left text the end of the line is on the left.
+	rtl := bidiCells("אבג")
+	if got := ui.CaretPosition(rtl, ui.DirectionAuto, 3); got != -1 {
+		t.Errorf("end caret = %d, want -1", got)
+	}
+	if got := ui.MoveCaret(rtl, ui.DirectionAuto, 0, -1); got != 1 {
+		t.Errorf("moving left from the start = %d, want 1", got)
+	}
+}
+
+func TestParagraphRightToLeft(t *testing.T) {
+	p := widgets.NewParagraph()
+	p.Text = "שלום abc"
+	p.SetRect(0, 0, 12, 3)
+	buf := ui.NewBuffer(p.GetRect())
+	p.Draw(buf)
+
+	// Right-to-left text is aligned to the right by default.
+	if got := bidiRow(buf, 1, 1, 11); got != "  abc םולש" {
+		t.Errorf("row = %q", got)
+	}
+
+	p.TextAlignment = ui.AlignRight
+	buf = ui.NewBuffer(p.GetRect())
+	p.Draw(buf)
+	if got := bidiRow(buf, 1, 1, 11); got != "abc םולש  " {
+		t.Errorf("mirrored AlignRight row = %q", got)
+	}
+}
+
+func TestTableRightToLeft(t *testing.T) {
+	tb := widgets.NewTable()
+	tb.Rows = [][]string{{"שלום", "abc"}}
+	tb.ColumnWidths = []int{6, 6}
+	tb.SetRect(0, 0, 15, 3)
+	buf := ui.NewBuffer(tb.GetRect())
+	tb.Draw(buf)
+
+	if got := bidiRow(buf, 1, 1, 7); got != "  םולש" {
+		t.Errorf("right-to-left cell = %q", got)
+	}
+	if got := bidiRow(buf, 1, 8, 14); got != "abc   " {
+		t.Errorf("left-to-right cell = %q", got)
+	}
+}
+
+func TestInputRightToLeft(t *testing.T) {
+	in := widgets.NewInput()
+	in.Text = "אבג"
+	in.Cursor = 0
+	in.SetRect(0, 0, 8, 3)
+	buf := ui.NewBuffer(in.GetRect())
+	in.Draw(buf)
+
+	if got := bidiRow(buf, 1, 1, 7); got != "   גבא" {
+		t.Errorf("row = %q", got)
+	}
+	// The cursor is on the first letter, which shows at the right.
+	if got := buf.GetCell(image.Pt(6, 1)).Style; got != in.CursorStyle {
+		t.Errorf("cursor cell style = %v", got)
+	}
+
+	in.MoveCursorLeft()
+	if in.Cursor != 1 {
+		t.Errorf("cursor after moving left = %d, want 1", in.Cursor)
+	}
+	in.MoveCursorRight()
+	in.MoveCursorRight()
+	if in.Cursor != 0 {
+		t.Errorf("cursor after moving right past the start = %d, want 0", in.Cursor)
+	}
+}
+
+func TestTextAreaMixedCursor(t *testing.T) {
+	ta := widgets.NewTextArea()
+	ta.Text = "ab אבג"
+	ta.Cursor = image.Pt(2, 0)
+	ta.MoveCursor(1, 0)
+	if ta.Cursor.X != 5 {
+		t.Errorf("cursor = %d, want 5, the letter shown right of the space", ta.Cursor.X)
+	}
+	ta.MoveCursor(-1, 0)
+	if ta.Cursor.X != 2 {
+		t.Errorf("cursor = %d, want 2", ta.Cursor.X)
+	}
+}
@@ -0,0 +1,163 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func TestColorAlpha(t *testing.T) {
+	red := ui.NewColorRGB(255, 0, 0)
+	if a := ui.ColorAlpha(red); a != 1 {
+		t.Errorf("alpha of an opaque color = %v, want 1", a)
+	}
+	half := ui.WithAlpha(red, 0.5)
+	if a := ui.ColorAlpha(half); a < 0.48 || a > 0.52 {
+		t.Errorf("alpha = %v, want about 0.5", a)
+	}
+	if ui.OpaqueColor(half) != red {
+		t.Errorf("OpaqueColor = %v, want %v", ui.OpaqueColor(half), red)
+	}
+	if ui.WithAlpha(red, 1) != red {
+		t.Error("a fully opaque alpha should leave the color as it is")
+	}
+	if !ui.NewStyle(ui.ColorClear, half).Translucent() || ui.NewStyle(red).Translucent() {
+		t.Error("Translucent should report colors with an alpha")
+	}
+}
+
+func TestBlendColor(t *testing.T) {
+	black, white := ui.NewColorRGB(0, 0, 0), ui.NewColorRGB(255, 255, 255)
+	r, g, b := ui.BlendColor(ui.WithAlpha(black, 0.5), white, ui.ColorClear).RGB()
+	if r < 120 || r > 135 || r != g || g != b {
+		t.Errorf("half black over white = %d,%d,%d, want a mid gray", r, g, b)
+	}
+	if got := ui.BlendColor(ui.WithAlpha(ui.ColorClear, 0.5), white, black); got != white {
+		t.Errorf("translucent clear over white = %v, want white", got)
+	}
+	if got := ui.BlendColor(ui.WithAlpha(white, 0.5), ui.ColorClear, black); ui.ColorAlpha(got) != 1 {
+		t.Errorf("blending over clear left an alpha: %v", got)
+	}
+}
+
+func TestSetCellBlends(t *testing.T) {
+	blue := ui.NewColorRGB(0, 0, 200)
+	buf := ui.NewBuffer(image.Rect(0, 0, 3, 1))
+	buf.Fill(ui.NewCell(' ', ui.NewStyle(ui.ColorClear, blue)), buf.Rectangle)
+	buf.SetCell(ui.NewCell('x', ui.NewStyle(ui.ColorClear, ui.WithAlpha(ui.NewColorRGB(0, 0, 0), 0.5))), image.Pt(1, 0))
+
+	c := buf.GetCell(image.Pt(1, 0))
+	if c.Rune != 'x' {
+		t.Errorf("rune = %q, want 'x'", c.Rune)
+	}
+	if _, _, b := c.Style.Bg.RGB(); b < 90 || b > 110 {
+		t.Errorf("blue = %d, want about 100", b)
+	}
+	if ui.ColorAlpha(c.Style.Bg) != 1 {
+		t.Error("the blended background should be opaque")
+	}
+	if got := buf.GetCell(image.Pt(0, 0)).Style.Bg; got != blue {
+		t.Errorf("neighbour background = %v, want %v", got, blue)
+	}
+}
+
+func TestBufferBlendKeepsText(t *testing.T) {
+	red := ui.NewColorRGB(200, 0, 0)
+	buf := ui.NewBuffer(image.Rect(0, 0, 4, 1))
+	buf.SetString("ab", ui.NewStyle(red, ui.NewColorRGB(0, 0, 0)), image.Pt(0, 0))
+	buf.Blend(ui.NewStyle(ui.ColorClear, ui.WithAlpha(ui.NewColorRGB(0, 0, 255), 0.5)), image.Rect(0, 0, 1, 1))
+
+	a := buf.GetCell(image.Pt(0, 0))
+	if a.Rune != 'a' || a.Style.Fg != red {
+		t.Errorf("tinted cell = %q %v, want 'a' with its own foreground", a.Rune, a.Style.Fg)
+	}
+	if _, _, b := a.Style.Bg.RGB(); b < 120 || b > 135 {
+		t.Errorf("tinted blue = %d, want about 128", b)
+	}
+	if got := buf.GetCell(image.Pt(1, 0)).Style.Bg; got != ui.NewColorRGB(0, 0, 0) {
+		t.Errorf("untinted background = %v", got)
+	}
+}
+
+func TestModalShadowAndBackdrop(t *testing.T) {
+	white := ui.NewColorRGB(255, 255, 255)
+	buf := ui.NewBuffer(image.Rect(0, 0, 20, 10))
+	buf.Fill(ui.NewCell('.', ui.NewStyle(white, white)), buf.Rectangle)
+
+	m := widgets.NewModal("hi")
+	m.SetRect(2, 2, 12, 6)
+	shade := ui.WithAlpha(ui.NewColorRGB(0, 0, 0), 0.5)
+	m.Backdrop = ui.NewStyle(shade, shade)
+	m.Shadow = ui.NewStyle(shade, shade)
+	m.Draw(buf)
+
+	corner := buf.GetCell(image.Pt(19, 9))
+	if corner.Rune != '.' {
+		t.Errorf("backdrop replaced the text: %q", corner.Rune)
+	}
+	r, _, _ := corner.Style.Bg.RGB()
+	if r < 120 || r > 135 {
+		t.Errorf("backdrop red = %d, want about 128", r)
+	}
+	// The shadow falls below and right of the modal and is darker still.
+	sr, _, _ := buf.GetCell(image.Pt(12, 6)).Style.Bg.RGB()
+	if sr >= r {
+		t.Errorf("shadow red = %d, want darker than the backdrop %d", sr, r)
+	}
+	if got := buf.GetCell(image.Pt(1, 6)).Style.Bg; got != corner.Style.Bg {
+		t.Errorf("left of the shadow = %v, want the backdrop %v", got, corner.Style.Bg)
+	}
+}
+
+func TestListTintsSelection(t *testing.T) {
+	red := ui.NewColorRGB(200, 0, 0)
+	l := widgets.NewList()
+	l.Rows = []string{"one", "two"}
+	l.TextStyle = ui.NewStyle(red, ui.NewColorRGB(0, 0, 0))
+	l.SelectedStyle = ui.NewStyle(ui.ColorClear, ui.WithAlpha(ui.NewColorRGB(0, 0, 255), 0.5))
+	l.SetRect(0, 0, 7, 4)
+	buf := ui.NewBuffer(l.GetRect())
+	l.Draw(buf)
+
+	c := buf.GetCell(image.Pt(1, 1))
+	if c.Rune != 'o' || c.Style.Fg != red {
+		t.Errorf("selected cell = %q %v, want 'o' keeping its foreground", c.Rune, c.Style.Fg)
+	}
+	if _, _, b := c.Style.Bg.RGB(); b == 0 {
+		t.Error("selected row was not tinted")
+	}
+	if _, _, b := buf.GetCell(image.Pt(1, 2)).Style.Bg.RGB(); b != 0 {
+		t.Error("unselected row was tinted")
+	}
+}
+
+func TestParseColorAlpha(t *testing.T) {
+	c, err := ui.ParseColor("#ff000080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ui.OpaqueColor(c) != ui.NewColorRGB(255, 0, 0) {
+		t.Errorf("color = %v", ui.OpaqueColor(c))
+	}
+	if a := ui.ColorAlpha(c); a < 0.48 || a > 0.52 {
+		t.Errorf("alpha = %v, want about 0.5", a)
+	}
+	back, err := ui.ParseColor(ui.FormatColor(c))
+	if err != nil || back != c {
+		t.Errorf("FormatColor round trip = %v, %v, want %v", back, err, c)
+	}
+}
+
+func TestModalCenterInKeepsZeroSize(t *testing.T) {
+	m := widgets.NewModal("hi")
+	m.CenterIn(0, 0, 20, 10, 0, 4)
+	if got := m.GetRect(); got != image.Rect(10, 3, 10, 7) {
+		t.Errorf("zero width rect = %v, want an empty column at the center", got)
+	}
+	m.CenterIn(0, 0, 20, 10, 30, 4)
+	if got := m.GetRect(); got != image.Rect(0, 3, 20, 7) {
+		t.Errorf("oversize rect = %v, want it clamped to the area", got)
+	}
+}
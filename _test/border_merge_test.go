@@ -0,0 +1,89 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func TestResolveBorderRune(t *testing.T) {
+	tests := []struct {
+		existing, next, want rune
+	}{
+		{'┐', '┌', '┬'},
+		{'┘', '└', '┴'},
+		{'│', '─', '┼'},
+		{'╗', '╔', '╦'},
+		{'╝', '╚', '╩'},
+		{'║', '═', '╬'},
+		{'┓', '┏', '┳'},
+		{'┃', '━', '╋'},
+		{'╮', '╭', '┬'},
+		{'╯', '╰', '┴'},
+		{' ', '╭', '╭'},
+		{'─', '╭', '┬'},
+		{'┃', '─', '╂'},
+		{'│', '━', '┿'},
+		{'║', '─', '╫'},
+		{'│', '═', '╪'},
+		{'┐', '┏', '┲'},
+		{'║', '━', '╫'},
+		{'─', 'a', 'a'},
+		{'a', '─', '─'},
+	}
+	for _, tt := range tests {
+		if got := ui.ResolveBorderRune(tt.existing, tt.next); got != tt.want {
+			t.Errorf("ResolveBorderRune(%q, %q) = %q, want %q", tt.existing, tt.next, got, tt.want)
+		}
+	}
+}
+
+func TestGridMergeDoubleBorders(t *testing.T) {
+	g := ui.NewGrid()
+	g.SetRect(0, 0, 21, 5)
+	g.MergeBorders = true
+	left := ui.NewBlock()
+	right := ui.NewBlock()
+	left.BorderType = ui.BorderDouble
+	right.BorderType = ui.BorderDouble
+	g.Set(ui.NewCol(0.5, left), ui.NewCol(0.5, right))
+	buf := ui.NewBuffer(g.Rectangle)
+	g.Draw(buf)
+
+	x := right.Min.X
+	if got := buf.GetCell(image.Pt(x, 0)).Rune; got != '╦' {
+		t.Errorf("top junction = %q, want '╦'", got)
+	}
+	if got := buf.GetCell(image.Pt(x, 4)).Rune; got != '╩' {
+		t.Errorf("bottom junction = %q, want '╩'", got)
+	}
+}
+
+func TestFlexMergeBorders(t *testing.T) {
+	f := widgets.NewFlex()
+	f.Border = false
+	f.Direction = widgets.FlexColumn
+	f.MergeBorders = true
+	f.SetRect(-1, -1, 11, 11)
+
+	top := ui.NewBlock()
+	bottom := ui.NewBlock()
+	bottom.BorderRounded = true
+	f.AddItem(top, 0, 1, false)
+	f.AddItem(bottom, 0, 1, false)
+	buf := ui.NewBuffer(image.Rect(0, 0, 10, 10))
+	f.Draw(buf)
+
+	y := bottom.Min.Y
+	if top.Max.Y-1 != y {
+		t.Fatalf("items do not share a border: %v %v", top.Rectangle, bottom.Rectangle)
+	}
+	if got := buf.GetCell(image.Pt(0, y)).Rune; got != '├' {
+		t.Errorf("left junction = %q, want '├'", got)
+	}
+	if got := buf.GetCell(image.Pt(9, y)).Rune; got != '┤' {
+		t.Errorf("right junction = %q, want '┤'", got)
+	}
+}
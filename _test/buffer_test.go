@@ -0,0 +1,86 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func TestBufferClip(t *testing.T) {
+	buf := ui.NewBuffer(image.Rect(0, 0, 10, 3))
+	buf.PushClip(image.Rect(2, 0, 6, 3))
+	buf.PushClip(image.Rect(4, 0, 10, 3))
+	buf.SetString("abcdefghij", ui.StyleClear, image.Pt(0, 0))
+	buf.PopClip()
+	buf.SetString("abcdefghij", ui.StyleClear, image.Pt(0, 1))
+	buf.PopClip()
+	buf.Fill(ui.NewCell('x'), image.Rect(0, 2, 10, 3))
+
+	want := []string{"    ef    ", "  cdef    ", "xxxxxxxxxx"}
+	for y, line := range want {
+		for x, r := range line {
+			if r == ' ' {
+				r = ui.CellClear.Rune
+			}
+			if got := buf.GetCell(image.Pt(x, y)).Rune; got != r {
+				t.Errorf("cell %d,%d = %q, want %q", x, y, got, r)
+			}
+		}
+	}
+}
+
+func TestSubBuffer(t *testing.T) {
+	buf := ui.NewBuffer(image.Rect(-5, -5, 5, 5))
+	sub := buf.SubBuffer(image.Rect(0, 0, 3, 2))
+	if sub.Rectangle != image.Rect(0, 0, 3, 2) {
+		t.Fatalf("sub rectangle = %v", sub.Rectangle)
+	}
+	sub.SetCell(ui.NewCell('a'), image.Pt(0, 0))
+	sub.SetCell(ui.NewCell('b'), image.Pt(3, 0))
+	sub.Fill(ui.NewCell('c'), image.Rect(-1, 1, 10, 5))
+	if got := buf.GetCell(image.Pt(0, 0)).Rune; got != 'a' {
+		t.Errorf("local origin wrote %q", got)
+	}
+	if got := buf.GetCell(image.Pt(3, 0)).Rune; got == 'b' {
+		t.Errorf("write outside the sub buffer reached the parent")
+	}
+	for x := -1; x <= 3; x++ {
+		want := x >= 0 && x < 3
+		if got := buf.GetCell(image.Pt(x, 1)).Rune == 'c'; got != want {
+			t.Errorf("fill at %d,1 = %v, want %v", x, got, want)
+		}
+	}
+
+	buf.PushClip(image.Rect(1, 0, 5, 5))
+	nested := buf.SubBuffer(image.Rect(0, 3, 4, 4)).SubBuffer(image.Rect(0, 0, 2, 1))
+	buf.PopClip()
+	nested.Fill(ui.NewCell('n'), nested.Rectangle)
+	if buf.GetCell(image.Pt(0, 3)).Rune == 'n' || buf.GetCell(image.Pt(1, 3)).Rune != 'n' {
+		t.Errorf("nested sub buffer ignored the parent clip")
+	}
+}
+
+func TestClipStopsOverflow(t *testing.T) {
+	p := widgets.NewParagraph()
+	p.Text = "overflow"
+	p.Border = false
+	p.SetRect(0, 0, 4, 1)
+	p.Inner = image.Rect(0, 0, 10, 1) // pretend the widget miscalculated
+	right := ui.NewBlock()
+	right.SetRect(4, 0, 10, 1)
+
+	buf := ui.NewBuffer(image.Rect(0, 0, 10, 1))
+	for _, d := range []ui.Drawable{right, p} {
+		buf.PushClip(d.GetRect())
+		d.Draw(buf)
+		buf.PopClip()
+	}
+	if got := buf.GetCell(image.Pt(4, 0)).Rune; got == 'f' {
+		t.Errorf("paragraph drew into its neighbour")
+	}
+	if got := buf.GetCell(image.Pt(0, 0)).Rune; got != 'o' {
+		t.Errorf("paragraph did not draw inside its rect: %q", got)
+	}
+}
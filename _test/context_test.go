@@ -0,0 +1,63 @@
+package gotui_test
+
+import (
+	"testing"
+	"time"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestContextThemeIsolation(t *testing.T) {
+	a := ui.NewContext(&ui.Backend{})
+	b := ui.NewContext(&ui.Backend{})
+
+	a.Theme.Paragraph.Text = ui.NewStyle(ui.ColorRed)
+	a.Theme.BarChart.Bars[0] = ui.ColorGreen
+
+	if b.Theme.Paragraph.Text.Fg == ui.ColorRed {
+		t.Error("theme change leaked into another context")
+	}
+	if b.Theme.BarChart.Bars[0] == ui.ColorGreen || ui.Theme.BarChart.Bars[0] == ui.ColorGreen {
+		t.Error("theme slices are shared between contexts")
+	}
+
+	pa := widgets.NewParagraphWithContext(a)
+	pb := widgets.NewParagraphWithContext(b)
+	if pa.TextStyle.Fg != ui.ColorRed {
+		t.Errorf("expected paragraph to use context theme, got %v", pa.TextStyle.Fg)
+	}
+	if pb.TextStyle.Fg == ui.ColorRed {
+		t.Error("paragraph picked up theme of another context")
+	}
+	if pa.Context != a {
+		t.Error("expected widget block to be bound to its context")
+	}
+}
+
+func TestContextClock(t *testing.T) {
+	ctx := ui.NewContext(&ui.Backend{})
+	ctx.Clock = fixedClock(time.Date(2020, time.February, 14, 0, 0, 0, 0, time.UTC))
+
+	c := widgets.NewCalendarWithContext(ctx)
+	if c.Month != time.February || c.Year != 2020 || c.CurrentDay != 14 {
+		t.Errorf("expected calendar to use context clock, got %v %d %d", c.Month, c.Year, c.CurrentDay)
+	}
+}
+
+func TestDefaultContextCompatibility(t *testing.T) {
+	if ui.DefaultContext.Backend != ui.DefaultBackend {
+		t.Error("DefaultContext should use DefaultBackend")
+	}
+	if ui.DefaultContext.Theme != &ui.Theme {
+		t.Error("DefaultContext should use the global Theme")
+	}
+	app := ui.NewApp()
+	if app.Context != ui.DefaultContext || app.Backend != ui.DefaultBackend {
+		t.Error("NewApp should be bound to DefaultContext")
+	}
+}
@@ -0,0 +1,140 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+type flexProbe struct {
+	ui.Block
+	events []string
+}
+
+func newFlexProbe() *flexProbe {
+	return &flexProbe{Block: *ui.NewBlock()}
+}
+
+func (p *flexProbe) HandleEvent(e ui.Event) bool {
+	p.events = append(p.events, e.ID)
+	return true
+}
+
+func newRowFlex(w, h int) *widgets.Flex {
+	f := widgets.NewFlex()
+	f.Border = false
+	f.Direction = widgets.FlexRow
+	f.SetRect(0, 0, w, h)
+	return f
+}
+
+func TestFlexGapAndJustify(t *testing.T) {
+	tests := []struct {
+		justify widgets.FlexJustify
+		want    []int
+	}{
+		{widgets.FlexJustifyStart, []int{0, 6}},
+		{widgets.FlexJustifyCenter, []int{4, 10}},
+		{widgets.FlexJustifyEnd, []int{9, 15}},
+		{widgets.FlexJustifySpaceBetween, []int{0, 15}},
+	}
+	for _, tt := range tests {
+		f := newRowFlex(22, 3)
+		f.Gap = 1
+		f.Justify = tt.justify
+		a, b := ui.NewBlock(), ui.NewBlock()
+		f.AddItem(a, 5, 0, false)
+		f.AddItem(b, 5, 0, false)
+		f.Draw(ui.NewBuffer(f.Rectangle))
+
+		got := []int{a.Min.X - f.Inner.Min.X, b.Min.X - f.Inner.Min.X}
+		if got[0] != tt.want[0] || got[1] != tt.want[1] {
+			t.Errorf("justify %d: got %v, want %v", tt.justify, got, tt.want)
+		}
+	}
+}
+
+func TestFlexAlignItems(t *testing.T) {
+	f := newRowFlex(20, 10)
+	f.AlignItems = widgets.FlexAlignCenter
+	a, b := ui.NewBlock(), ui.NewBlock()
+	f.AddItem(a, 0, 1, false).CrossSize = 4
+	f.AddItem(b, 0, 1, false)
+	f.Draw(ui.NewBuffer(f.Rectangle))
+
+	if a.Dy() != 4 || a.Min.Y-f.Inner.Min.Y != (f.Inner.Dy()-4)/2 {
+		t.Errorf("centered item at %v", a.Rectangle)
+	}
+	if b.Dy() != f.Inner.Dy() {
+		t.Errorf("item without CrossSize should stretch, got %v", b.Rectangle)
+	}
+}
+
+func TestFlexMinMax(t *testing.T) {
+	f := newRowFlex(32, 3)
+	a, b, c := ui.NewBlock(), ui.NewBlock(), ui.NewBlock()
+	f.AddItem(a, 0, 1, false).MaxSize = 4
+	f.AddItem(b, 0, 1, false)
+	f.AddItem(c, 0, 1, false).MinSize = 20
+	f.Draw(ui.NewBuffer(f.Rectangle))
+
+	total := f.Inner.Dx()
+	if a.Dx() != 4 || c.Dx() != 20 || b.Dx() != total-24 {
+		t.Errorf("unexpected widths %d %d %d", a.Dx(), b.Dx(), c.Dx())
+	}
+}
+
+func TestFlexWrap(t *testing.T) {
+	f := newRowFlex(22, 10)
+	f.Wrap = true
+	var items []*ui.Block
+	for range 3 {
+		b := ui.NewBlock()
+		items = append(items, b)
+		f.AddItem(b, 8, 0, false)
+	}
+	f.Draw(ui.NewBuffer(f.Rectangle))
+
+	if items[0].Min.Y != items[1].Min.Y {
+		t.Errorf("first two items should share a line: %v %v", items[0].Rectangle, items[1].Rectangle)
+	}
+	if items[2].Min.Y != items[0].Max.Y || items[2].Min.X != f.Inner.Min.X {
+		t.Errorf("third item should wrap: %v", items[2].Rectangle)
+	}
+}
+
+func TestFlexNestedEvents(t *testing.T) {
+	outer := newRowFlex(40, 10)
+	inner := widgets.NewFlex()
+	inner.Border = false
+	left, right := newFlexProbe(), newFlexProbe()
+	inner.AddItem(left, 0, 1, false)
+	inner.AddItem(right, 0, 1, false)
+	outer.AddItem(ui.NewBlock(), 10, 0, false)
+	outer.AddItem(inner, 0, 1, true)
+	outer.Draw(ui.NewBuffer(outer.Rectangle))
+
+	p := right.Min.Add(image.Pt(1, 1))
+	outer.HandleEvent(ui.Event{Type: ui.MouseEvent, ID: "<MouseLeft>", Payload: ui.Mouse{X: p.X, Y: p.Y}})
+	if len(right.events) != 1 || len(left.events) != 0 {
+		t.Fatalf("mouse event reached left=%v right=%v", left.events, right.events)
+	}
+
+	outer.HandleEvent(ui.Event{Type: ui.KeyboardEvent, ID: "a"})
+	if len(right.events) != 2 {
+		t.Errorf("keyboard event not forwarded to clicked item: %v", right.events)
+	}
+
+	app := ui.NewApp()
+	app.SetFocus(outer)
+	if app.Focused() != right {
+		t.Errorf("Focused() = %v, want innermost item", app.Focused())
+	}
+
+	inner.FocusPrev()
+	if app.Focused() != left {
+		t.Errorf("FocusPrev did not move focus to the left item")
+	}
+}
@@ -0,0 +1,100 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+const (
+	graphemeAccent = "e\u0301"
+	graphemeFlag   = "\U0001F1EF\U0001F1F5"
+	graphemeFamily = "\U0001F468\u200d\U0001F469\u200d\U0001F467"
+)
+
+func TestSetStringGraphemes(t *testing.T) {
+	buf := ui.NewBuffer(image.Rect(0, 0, 10, 1))
+	buf.SetString(graphemeAccent+graphemeFlag+graphemeFamily+"x", ui.StyleClear, image.Pt(0, 0))
+
+	want := []struct {
+		text  string
+		width int
+	}{
+		{graphemeAccent, 1}, {graphemeFlag, 2}, {"", 0}, {graphemeFamily, 2}, {"", 0}, {"x", 1},
+	}
+	for x, w := range want {
+		c := buf.GetCell(image.Pt(x, 0))
+		if w.text == "" {
+			if c.Rune != 0 {
+				t.Errorf("cell %d = %q, want a continuation", x, c.Text())
+			}
+			continue
+		}
+		if c.Text() != w.text || c.ColumnWidth() != w.width {
+			t.Errorf("cell %d = %q width %d, want %q width %d", x, c.Text(), c.ColumnWidth(), w.text, w.width)
+		}
+	}
+}
+
+func TestSetCellOverwritesWideCell(t *testing.T) {
+	buf := ui.NewBuffer(image.Rect(0, 0, 4, 1))
+	buf.SetString("世", ui.StyleClear, image.Pt(0, 0))
+	buf.SetCell(ui.NewCell('a'), image.Pt(1, 0))
+	if r := buf.GetCell(image.Pt(0, 0)).Rune; r != ' ' {
+		t.Errorf("half overwritten wide cell left %q", r)
+	}
+
+	buf.SetString("世", ui.StyleClear, image.Pt(2, 0))
+	buf.SetCell(ui.NewCell('b'), image.Pt(2, 0))
+	if r := buf.GetCell(image.Pt(3, 0)).Rune; r != ' ' {
+		t.Errorf("continuation of overwritten wide cell left %q", r)
+	}
+
+	buf.PushClip(image.Rect(0, 0, 3, 1))
+	buf.SetString("世", ui.StyleClear, image.Pt(2, 0))
+	buf.PopClip()
+	if r := buf.GetCell(image.Pt(2, 0)).Rune; r != ' ' {
+		t.Errorf("wide cell cut by the clip drew %q", r)
+	}
+}
+
+func TestCellHelpersGraphemes(t *testing.T) {
+	cells := ui.ParseStyles("["+graphemeAccent+"b](fg:red)"+graphemeFlag, ui.StyleClear)
+	if len(cells) != 3 || cells[0].Text() != graphemeAccent || cells[0].Style.Fg != ui.ColorRed || cells[2].Text() != graphemeFlag {
+		t.Fatalf("ParseStyles = %q", ui.CellsToString(cells))
+	}
+
+	trimmed := ui.TrimCells(ui.StringToStyledCells(graphemeFlag+graphemeFlag+"ab", ui.StyleClear), 4)
+	if got := ui.CellsToString(trimmed); got != graphemeFlag+"…" {
+		t.Errorf("TrimCells = %q", got)
+	}
+
+	wrapped := ui.WrapCells(ui.StringToStyledCells(graphemeAccent+"a b", ui.StyleClear), 2)
+	if got := ui.CellsToString(wrapped); got != graphemeAccent+"a\nb" {
+		t.Errorf("WrapCells = %q", got)
+	}
+}
+
+func TestRenderGraphemes(t *testing.T) {
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Fini()
+	sim.SetSize(10, 3)
+
+	p := widgets.NewParagraph()
+	p.Text = graphemeFlag + graphemeAccent
+	p.SetRect(0, 0, 10, 3)
+	(&ui.Backend{Screen: sim}).Render(p)
+
+	if s, _, w := sim.Get(1, 1); s != graphemeFlag || w != 2 {
+		t.Errorf("flag rendered as %q width %d", s, w)
+	}
+	if s, _, _ := sim.Get(3, 1); s != graphemeAccent {
+		t.Errorf("combining mark rendered as %q", s)
+	}
+}
@@ -133,3 +133,119 @@ func TestGridNested(t *testing.T) {
 		t.Errorf("b3 max mismatch: %v", r3.Max)
 	}
 }
+
+func TestGridNoGapsAcrossSizes(t *testing.T) {
+	for size := 1; size <= 120; size++ {
+		g := ui.NewGrid()
+		g.SetRect(0, 0, size, size)
+		blocks := []*ui.Block{ui.NewBlock(), ui.NewBlock(), ui.NewBlock()}
+		g.Set(
+			ui.NewCol(0.3, blocks[0]),
+			ui.NewCol(0.45, blocks[1]),
+			ui.NewCol(0.25, blocks[2]),
+		)
+		g.Draw(ui.NewBuffer(g.Rectangle))
+		for i := 1; i < len(blocks); i++ {
+			if blocks[i-1].Max.X != blocks[i].Min.X {
+				t.Fatalf("size %d: gap or overlap between %d and %d", size, i-1, i)
+			}
+		}
+		if blocks[2].Max.X != size {
+			t.Fatalf("size %d: last column ends at %d", size, blocks[2].Max.X)
+		}
+	}
+}
+
+func TestGridFixedRow(t *testing.T) {
+	for height := 5; height <= 50; height++ {
+		g := ui.NewGrid()
+		g.SetRect(0, 0, 80, height)
+		top := ui.NewBlock()
+		bottom := ui.NewBlock()
+		status := ui.NewBlock()
+		g.Set(
+			ui.NewRow(0.5, top),
+			ui.NewRow(0.5, bottom),
+			ui.NewRowWithConstraint(ui.Length(1), status),
+		)
+		g.Draw(ui.NewBuffer(g.Rectangle))
+		if status.Dy() != 1 || status.Max.Y != height {
+			t.Fatalf("height %d: status bar at %v", height, status.Rectangle)
+		}
+		if top.Max.Y != bottom.Min.Y || bottom.Max.Y != status.Min.Y {
+			t.Fatalf("height %d: rows are not adjacent", height)
+		}
+	}
+}
+
+func TestGridSpacing(t *testing.T) {
+	g := ui.NewGrid()
+	g.SetRect(0, 0, 22, 10)
+	g.ColSpacing = 2
+	left := ui.NewBlock()
+	right := ui.NewBlock()
+	g.Set(ui.NewCol(0.5, left), ui.NewCol(0.5, right))
+	g.Draw(ui.NewBuffer(g.Rectangle))
+
+	if left.Rectangle != image.Rect(0, 0, 10, 10) || right.Rectangle != image.Rect(12, 0, 22, 10) {
+		t.Errorf("unexpected rects %v %v", left.Rectangle, right.Rectangle)
+	}
+}
+
+func TestGridMixedChildren(t *testing.T) {
+	g := ui.NewGrid()
+	g.SetRect(0, 0, 20, 10)
+	a, b, c := ui.NewBlock(), ui.NewBlock(), ui.NewBlock()
+	g.Set(ui.NewRow(1, ui.NewCol(0.5, a), ui.NewCol(0.5, b), ui.NewRow(0.5, c)))
+	g.Draw(ui.NewBuffer(g.Rectangle))
+
+	want := []image.Rectangle{image.Rect(0, 0, 10, 5), image.Rect(10, 0, 20, 5), image.Rect(0, 5, 20, 10)}
+	for i, blk := range []*ui.Block{a, b, c} {
+		if blk.Rectangle != want[i] {
+			t.Errorf("block %d = %v, want %v", i, blk.Rectangle, want[i])
+		}
+	}
+	if item := g.Items[2]; item.YRatio != 0.5 || item.HeightRatio != 0.5 || item.WidthRatio != 1 {
+		t.Errorf("ratios of the bottom row = %+v", *item)
+	}
+}
+
+func TestGridSetReplacesItems(t *testing.T) {
+	g := ui.NewGrid()
+	a, b := ui.NewBlock(), ui.NewBlock()
+	g.Set(ui.NewRow(1, a))
+	g.Set(ui.NewRow(1, b))
+	if items := g.Children(); len(items) != 1 || items[0] != b {
+		t.Errorf("Children() = %v, want only the widget of the last Set", items)
+	}
+}
+
+func TestGridMergeBorders(t *testing.T) {
+	g := ui.NewGrid()
+	g.SetRect(0, 0, 21, 11)
+	g.MergeBorders = true
+	blocks := []*ui.Block{ui.NewBlock(), ui.NewBlock(), ui.NewBlock(), ui.NewBlock()}
+	g.Set(
+		ui.NewRow(0.5, ui.NewCol(0.5, blocks[0]), ui.NewCol(0.5, blocks[1])),
+		ui.NewRow(0.5, ui.NewCol(0.5, blocks[2]), ui.NewCol(0.5, blocks[3])),
+	)
+	buf := ui.NewBuffer(g.Rectangle)
+	g.Draw(buf)
+
+	if blocks[0].Max.X-1 != blocks[1].Min.X || blocks[0].Max.Y-1 != blocks[2].Min.Y {
+		t.Fatalf("neighbours do not share a border: %v %v %v", blocks[0].Rectangle, blocks[1].Rectangle, blocks[2].Rectangle)
+	}
+	x, y := blocks[1].Min.X, blocks[2].Min.Y
+	checks := map[image.Point]rune{
+		image.Pt(x, 0):  '┬',
+		image.Pt(0, y):  '├',
+		image.Pt(x, y):  '┼',
+		image.Pt(x, 10): '┴',
+		image.Pt(20, y): '┤',
+	}
+	for p, want := range checks {
+		if got := buf.GetCell(p).Rune; got != want {
+			t.Errorf("cell %v = %q, want %q", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package gotui_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+// kindsOf lexes src and returns the kind of each token text, checking that
+// the tokens cover every line.
+func kindsOf(t *testing.T, lexer ui.Lexer, src string) map[string]ui.TokenKind {
+	t.Helper()
+	kinds := make(map[string]ui.TokenKind)
+	var state ui.LexState
+	for line := range strings.SplitSeq(src, "\n") {
+		var tokens []ui.Token
+		tokens, state = lexer.LexLine(line, state)
+		var sb strings.Builder
+		for _, tok := range tokens {
+			sb.WriteString(tok.Text)
+			kinds[strings.TrimSpace(tok.Text)] = tok.Kind
+		}
+		if sb.String() != line {
+			t.Errorf("tokens of %q join to %q", line, sb.String())
+		}
+	}
+	return kinds
+}
+
+func checkKinds(t *testing.T, got map[string]ui.TokenKind, want map[string]ui.TokenKind) {
+	t.Helper()
+	for text, kind := range want {
+		if k, ok := got[text]; !ok || k != kind {
+			t.Errorf("%q: kind = %v (found %v), want %v", text, k, ok, kind)
+		}
+	}
+}
+
+func TestGoLexer(t *testing.T) {
+	kinds := kindsOf(t, ui.GoLexer, "func f(n int) string {\n\t/* a\n\tb */ s := `x\ny` + \"q\\\"\" // end\n\treturn len(s) + 0x1F\n}")
+	checkKinds(t, kinds, map[string]ui.TokenKind{
+		"func":   ui.TokenKeyword,
+		"int":    ui.TokenType,
+		"string": ui.TokenType,
+		"/* a":   ui.TokenComment,
+		"b */":   ui.TokenComment,
+		"`x":     ui.TokenString,
+		"y`":     ui.TokenString,
+		`"q\""`:  ui.TokenString,
+		"// end": ui.TokenComment,
+		"len":    ui.TokenBuiltin,
+		"0x1F":   ui.TokenNumber,
+		"return": ui.TokenKeyword,
+		"f":      ui.TokenText,
+	})
+}
+
+func TestDataLexers(t *testing.T) {
+	checkKinds(t, kindsOf(t, ui.JSONLexer, `{"name": "gotui", "stars": -1.5e3, "ok": true}`), map[string]ui.TokenKind{
+		`"name"`:  ui.TokenKey,
+		`"gotui"`: ui.TokenString,
+		"-1.5e3":  ui.TokenNumber,
+		"true":    ui.TokenKeyword,
+	})
+	checkKinds(t, kindsOf(t, ui.YAMLLexer, "---\n# config\nserver:\n  - port: 8080 # http\n    name: \"web\"\n    tls: off\nnote: |\n  not: a key\nlast: &anchor x"), map[string]ui.TokenKind{
+		"---":        ui.TokenHeading,
+		"# config":   ui.TokenComment,
+		"server":     ui.TokenKey,
+		"port":       ui.TokenKey,
+		"8080":       ui.TokenNumber,
+		"# http":     ui.TokenComment,
+		`"web"`:      ui.TokenString,
+		"off":        ui.TokenKeyword,
+		"not: a key": ui.TokenString,
+		"last":       ui.TokenKey,
+		"&anchor":    ui.TokenVariable,
+	})
+	checkKinds(t, kindsOf(t, ui.ShellLexer, "# build\nif [ -n \"$HOME\" ]; then\n  NAME=${USER} echo 'multi\nline' $1 42\nfi"), map[string]ui.TokenKind{
+		"# build": ui.TokenComment,
+		"if":      ui.TokenKeyword,
+		`"$HOME"`: ui.TokenString,
+		"NAME":    ui.TokenVariable,
+		"${USER}": ui.TokenVariable,
+		"echo":    ui.TokenBuiltin,
+		"'multi":  ui.TokenString,
+		"line'":   ui.TokenString,
+		"$1":      ui.TokenVariable,
+		"42":      ui.TokenNumber,
+		"fi":      ui.TokenKeyword,
+	})
+	checkKinds(t, kindsOf(t, ui.DiffLexer, "--- a/x.go\n+++ b/x.go\n@@ -1,2 +1,2 @@ func main\n-old\n+new\n same"), map[string]ui.TokenKind{
+		"--- a/x.go":      ui.TokenHeading,
+		"@@ -1,2 +1,2 @@": ui.TokenHeading,
+		"func main":       ui.TokenText,
+		"-old":            ui.TokenDeleted,
+		"+new":            ui.TokenInserted,
+	})
+}
+
+func TestLexerFor(t *testing.T) {
+	for _, name := range []string{"go", "Go", ".yml", "YAML", "bash", "patch", "json"} {
+		if ui.LexerFor(name) == nil {
+			t.Errorf("no lexer for %q", name)
+		}
+	}
+	if ui.LexerFor("cobol") != nil {
+		t.Error("unexpected lexer for cobol")
+	}
+	upper := ui.LexerFunc(func(line string, state ui.LexState) ([]ui.Token, ui.LexState) {
+		return []ui.Token{{Kind: ui.TokenKeyword, Text: line}}, state
+	})
+	ui.RegisterLexer(upper, "shout")
+	if ui.LexerFor("SHOUT") == nil {
+		t.Error("registered lexer not found")
+	}
+}
+
+func TestHighlighterRelexesOnlyChangedLines(t *testing.T) {
+	var lexed []string
+	counting := ui.LexerFunc(func(line string, state ui.LexState) ([]ui.Token, ui.LexState) {
+		lexed = append(lexed, line)
+		return ui.GoLexer.LexLine(line, state)
+	})
+	lines := []string{"a", "b", "c", "d", "e", "f"}
+	h := ui.NewHighlighter(counting)
+	h.SetLines(lines)
+	h.Line(5)
+	if len(lexed) != 6 {
+		t.Fatalf("lexed %q", lexed)
+	}
+
+	lexed = nil
+	lines[2] = "c2"
+	h.SetLines(lines)
+	h.Line(5)
+	if len(lexed) != 1 || lexed[0] != "c2" {
+		t.Errorf("after editing a line, lexed %q", lexed)
+	}
+
+	// Opening a block comment changes the state of the lines after it.
+	lexed = nil
+	lines[1] = "/* b"
+	h.SetLines(lines)
+	if tok := h.Line(4); len(tok) != 1 || tok[0].Kind != ui.TokenComment {
+		t.Errorf("line after an open comment = %+v", tok)
+	}
+	if len(lexed) != 4 {
+		t.Errorf("lexed %q", lexed)
+	}
+
+	// Inserting a line keeps the lines below it when their state is the same.
+	lexed = nil
+	lines[1] = "b"
+	lines = append(lines[:2], append([]string{"new"}, lines[2:]...)...)
+	h.SetLines(lines)
+	h.Line(6)
+	if strings.Join(lexed, ",") != "b,new,c2,d,e,f" {
+		t.Errorf("lexed %q", lexed)
+	}
+	lexed = nil
+	lines = append(lines[:2], lines[3:]...)
+	h.SetLines(lines)
+	h.Line(5)
+	if len(lexed) != 0 {
+		t.Errorf("after deleting a line, lexed %q", lexed)
+	}
+}
+
+func TestHighlightInWidgets(t *testing.T) {
+	p := widgets.NewParagraph()
+	p.Lexer = ui.LexerFor("go")
+	p.Text = "return 1"
+	p.SetRect(0, 0, 20, 3)
+	buf := ui.NewBuffer(image.Rect(0, 0, 20, 3))
+	p.Draw(buf)
+	if s := buf.GetCell(image.Pt(1, 1)).Style; s != p.Syntax.Keyword {
+		t.Errorf("keyword = %+v", s)
+	}
+	if s := buf.GetCell(image.Pt(8, 1)).Style; s != p.Syntax.Number {
+		t.Errorf("number = %+v", s)
+	}
+
+	ta := widgets.NewTextArea()
+	ta.Lexer = ui.DiffLexer
+	ta.Text = "+add\n-del"
+	ta.ShowCursor = false
+	ta.SetRect(0, 0, 20, 4)
+	buf = ui.NewBuffer(image.Rect(0, 0, 20, 4))
+	ta.Draw(buf)
+	if s := buf.GetCell(image.Pt(2, 1)).Style; s != ta.Syntax.Inserted {
+		t.Errorf("inserted = %+v", s)
+	}
+	if s := buf.GetCell(image.Pt(2, 2)).Style; s != ta.Syntax.Deleted {
+		t.Errorf("deleted = %+v", s)
+	}
+
+	ta.Lexer = ui.GoLexer
+	ta.Text = "var x"
+	ta.Draw(buf)
+	if s := buf.GetCell(image.Pt(1, 1)).Style; s != ta.Syntax.Keyword {
+		t.Errorf("after changing the lexer, keyword = %+v", s)
+	}
+}
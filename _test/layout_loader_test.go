@@ -0,0 +1,155 @@
+package gotui_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/layout"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+const dashboardYAML = `
+type: grid
+children:
+  - type: row
+    size: 3
+    children:
+      - type: paragraph
+        id: header
+        title: Status
+        text: all systems go
+        text_style: fg:green,mod:bold
+        border: false
+  - type: row
+    children:
+      - type: col
+        size: 30%
+        children:
+          - {type: list, id: hosts, rows: [alpha, beta], selected_style: "fg:yellow"}
+      - type: col
+        children:
+          - type: flex
+            direction: row
+            gap: 1
+            children:
+              - {type: gauge, id: cpu, percent: 40, size: 10, title: CPU}
+              - {type: plot, id: load, data: [[1, 2, 3]], plot_type: scatter}
+`
+
+func TestLoadYAMLDashboard(t *testing.T) {
+	doc, err := layout.Load([]byte(dashboardYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.Root.(*ui.Grid); !ok {
+		t.Fatalf("root is %T", doc.Root)
+	}
+	header, ok := layout.Get[*widgets.Paragraph](doc, "header")
+	if !ok || header.Title != "Status" || header.Border || header.TextStyle.Fg != ui.ColorGreen || header.TextStyle.Modifier != ui.ModifierBold {
+		t.Errorf("header not configured: %+v", header)
+	}
+	hosts, _ := layout.Get[*widgets.List](doc, "hosts")
+	if len(hosts.Rows) != 2 || hosts.SelectedStyle.Fg != ui.ColorYellow {
+		t.Errorf("hosts not configured: %v", hosts.Rows)
+	}
+	load, _ := layout.Get[*widgets.Plot](doc, "load")
+	if load.PlotType != widgets.ScatterPlot || len(load.Data[0]) != 3 {
+		t.Errorf("plot not configured: %+v", load.Data)
+	}
+
+	doc.Root.SetRect(0, 0, 100, 30)
+	doc.Root.Draw(ui.NewBuffer(doc.Root.GetRect()))
+	if header.Dy() != 3 {
+		t.Errorf("header height = %d", header.Dy())
+	}
+	cpu, _ := layout.Get[*widgets.Gauge](doc, "cpu")
+	if cpu.Percent != 40 || cpu.Dx() != 10 {
+		t.Errorf("gauge percent %d width %d", cpu.Percent, cpu.Dx())
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	doc, err := layout.Load([]byte(`{"type": "flex", "direction": "column", "children": [
+		{"type": "paragraph", "id": "p", "text": "hi", "size": "min:2"}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := layout.Get[*widgets.Paragraph](doc, "p"); !ok || p.Text != "hi" {
+		t.Errorf("paragraph not loaded")
+	}
+}
+
+func TestLoadReportsPositions(t *testing.T) {
+	_, err := layout.Load([]byte(`type: flex
+children:
+  - type: gauge
+    percent: lots
+  - type: sparkles
+  - type: paragraph
+    colour: red
+    text_style: fg:nope
+`))
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{
+		"<layout>:4:14: percent: expected int, got \"lots\"",
+		"<layout>:5:11: unknown widget type \"sparkles\"",
+		"<layout>:7:5: unknown property \"colour\" for paragraph",
+		"<layout>:8:17: text_style: unknown color \"nope\"",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("missing %q in:\n%v", want, err)
+		}
+	}
+	var lerr *layout.Error
+	if !errors.As(err, &lerr) || lerr.Line != 4 {
+		t.Errorf("first error is %+v", lerr)
+	}
+}
+
+func TestLoadCustomRegistry(t *testing.T) {
+	reg := layout.NewRegistry()
+	reg.Register("Status_Box", func(ctx *ui.Context) ui.Drawable { return widgets.NewParagraphWithContext(ctx) })
+	l := &layout.Loader{Context: ui.DefaultContext, Registry: reg}
+	if _, err := l.Load([]byte("type: status-box\ntext: ok\n")); err != nil {
+		t.Errorf("custom type: %v", err)
+	}
+	if _, err := l.Load([]byte("type: gauge\n")); err == nil {
+		t.Errorf("gauge should not be known to an empty registry")
+	}
+}
+
+func TestWatchReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dash.yaml")
+	if err := os.WriteFile(path, []byte("type: paragraph\ntext: one\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	docs := make(chan *layout.Document, 1)
+	go layout.Watch(ctx, path, 10*time.Millisecond, func(d *layout.Document, err error) {
+		if err == nil {
+			docs <- d
+		}
+	})
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("type: paragraph\ntext: second version\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case d := <-docs:
+		if p := d.Root.(*widgets.Paragraph); p.Text != "second version" {
+			t.Errorf("reloaded text %q", p.Text)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no reload")
+	}
+}
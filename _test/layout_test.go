@@ -0,0 +1,117 @@
+package gotui_test
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func TestSolveLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		total int
+		cs    []ui.Constraint
+		want  []int
+	}{
+		{"lengths", 10, []ui.Constraint{ui.Length(3), ui.Length(4)}, []int{3, 4}},
+		{"fill remaining", 20, []ui.Constraint{ui.Length(5), ui.Fill(1)}, []int{5, 15}},
+		{"fill weights", 12, []ui.Constraint{ui.Fill(1), ui.Fill(2)}, []int{4, 8}},
+		{"percentage mixed with length", 100, []ui.Constraint{ui.Length(10), ui.Percentage(50), ui.Fill(1)}, []int{10, 50, 40}},
+		{"ratio thirds", 10, []ui.Constraint{ui.Ratio(1, 3), ui.Ratio(1, 3), ui.Ratio(1, 3)}, []int{3, 4, 3}},
+		{"min grows without fill", 20, []ui.Constraint{ui.Length(5), ui.Min(5)}, []int{5, 15}},
+		{"min keeps minimum with fill", 20, []ui.Constraint{ui.Min(5), ui.Fill(1)}, []int{5, 15}},
+		{"max capped", 20, []ui.Constraint{ui.Max(4), ui.Fill(1)}, []int{4, 16}},
+		{"max shrinks first", 10, []ui.Constraint{ui.Length(8), ui.Max(5)}, []int{8, 2}},
+		{"relative scaled on overflow", 10, []ui.Constraint{ui.Length(4), ui.Percentage(60), ui.Percentage(60)}, []int{4, 3, 3}},
+		{"lengths cut from the end", 10, []ui.Constraint{ui.Length(6), ui.Length(6), ui.Length(6)}, []int{6, 4, 0}},
+		{"unclaimed space", 10, []ui.Constraint{ui.Length(3)}, []int{3}},
+		{"zero total", 0, []ui.Constraint{ui.Fill(1), ui.Length(3)}, []int{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ui.SolveLayout(tt.total, tt.cs...)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("SolveLayout(%d) = %v, want %v", tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolveLayoutNoGaps(t *testing.T) {
+	for total := range 200 {
+		cs := []ui.Constraint{ui.Percentage(33), ui.Ratio(1, 7), ui.Fill(3), ui.Min(1), ui.Fill(2)}
+		sum := 0
+		for _, s := range ui.SolveLayout(total, cs...) {
+			if s < 0 {
+				t.Fatalf("negative size for total %d", total)
+			}
+			sum += s
+		}
+		if sum != total {
+			t.Errorf("sizes for total %d add up to %d", total, sum)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	area := image.Rect(2, 1, 12, 21)
+	rects := ui.Split(area, ui.LayoutVertical, ui.Length(5), ui.Fill(1))
+	if rects[0] != image.Rect(2, 1, 12, 6) || rects[1] != image.Rect(2, 6, 12, 21) {
+		t.Errorf("unexpected vertical split %v", rects)
+	}
+	rects = ui.Split(area, ui.LayoutHorizontal, ui.Percentage(50), ui.Percentage(50))
+	if rects[0] != image.Rect(2, 1, 7, 21) || rects[1] != image.Rect(7, 1, 12, 21) {
+		t.Errorf("unexpected horizontal split %v", rects)
+	}
+}
+
+func TestGridConstraints(t *testing.T) {
+	g := ui.NewGrid()
+	g.SetRect(0, 0, 40, 20)
+
+	header := ui.NewBlock()
+	left := ui.NewBlock()
+	right := ui.NewBlock()
+	g.Set(
+		ui.NewRowWithConstraint(ui.Length(3), header),
+		ui.NewRowWithConstraint(ui.Fill(1),
+			ui.NewColWithConstraint(ui.Length(10), left),
+			ui.NewCol(1.0, right),
+		),
+	)
+	g.Draw(ui.NewBuffer(g.Rectangle))
+
+	if r := header.GetRect(); r != image.Rect(0, 0, 40, 3) {
+		t.Errorf("header rect %v", r)
+	}
+	if r := left.GetRect(); r != image.Rect(0, 3, 10, 20) {
+		t.Errorf("left rect %v", r)
+	}
+	if r := right.GetRect(); r != image.Rect(10, 3, 40, 20) {
+		t.Errorf("right rect %v", r)
+	}
+}
+
+func TestFlexConstraints(t *testing.T) {
+	f := widgets.NewFlex()
+	f.Direction = widgets.FlexRow
+	f.SetRect(0, 0, 32, 5)
+
+	a := ui.NewBlock()
+	b := ui.NewBlock()
+	c := ui.NewBlock()
+	f.AddItem(a, 5, 0, false)
+	f.AddItemWithConstraint(b, ui.Max(10), false)
+	f.AddItem(c, 0, 1, false)
+	f.Draw(ui.NewBuffer(f.Rectangle))
+
+	if a.Dx() != 5 || b.Dx() != 10 || c.Dx() != 15 {
+		t.Errorf("unexpected widths %d %d %d", a.Dx(), b.Dx(), c.Dx())
+	}
+	if a.Max.X != b.Min.X || b.Max.X != c.Min.X || c.Max.X != f.Inner.Max.X {
+		t.Errorf("items are not adjacent: %v %v %v", a.Rectangle, b.Rectangle, c.Rectangle)
+	}
+}
@@ -0,0 +1,145 @@
+package gotui_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+// drawMarkdown draws md into a w x h buffer and returns its rows.
+func drawMarkdown(md *widgets.Markdown, w, h int) (*ui.Buffer, []string) {
+	md.SetRect(0, 0, w, h)
+	buf := ui.NewBuffer(image.Rect(0, 0, w, h))
+	md.Draw(buf)
+	rows := make([]string, h)
+	for y := range h {
+		var sb strings.Builder
+		for x := range w {
+			sb.WriteString(buf.GetCell(image.Pt(x, y)).Text())
+		}
+		rows[y] = strings.TrimRight(sb.String(), " ")
+	}
+	return buf, rows
+}
+
+// findText returns the position of s in rows, or (-1, -1).
+func findText(rows []string, s string) image.Point {
+	for y, row := range rows {
+		if i := strings.Index(row, s); i >= 0 {
+			return image.Pt(len([]rune(row[:i])), y)
+		}
+	}
+	return image.Pt(-1, -1)
+}
+
+func TestMarkdownBlocks(t *testing.T) {
+	md := widgets.NewMarkdown()
+	md.Border = false
+	md.Text = "# Title\n\nText\nwraps.\n\n> quoted\n\n- one\n- two\n  1. inner\n\n```\ncode\n```\n\n***\n"
+	_, rows := drawMarkdown(md, 22, 20)
+	want := []string{
+		"Title",
+		"═════",
+		"",
+		"Text wraps.",
+		"",
+		"│ quoted",
+		"",
+		"• one",
+		"• two",
+		"  1. inner",
+		"",
+		" code",
+		"",
+		strings.Repeat("─", 20),
+	}
+	for i, line := range want {
+		if rows[i+1] != " "+line && !(line == "" && rows[i+1] == "") {
+			t.Errorf("row %d = %q, want %q", i, rows[i+1], line)
+		}
+	}
+}
+
+func TestMarkdownInlineStyles(t *testing.T) {
+	md := widgets.NewMarkdown()
+	md.Text = "a *b* **c** ~~d~~ `e` [f](http://g)"
+	buf, rows := drawMarkdown(md, 40, 3)
+	if !strings.HasPrefix(rows[1], "│a b c d e f (http://g) ") {
+		t.Fatalf("row = %q", rows[1])
+	}
+	at := func(s string) ui.Style {
+		return buf.GetCell(findText(rows, s)).Style
+	}
+	if at("b").Modifier != ui.ModifierItalic || at("c").Modifier != ui.ModifierBold || at("d").Modifier != ui.ModifierStrike {
+		t.Errorf("emphasis = %+v %+v %+v", at("b"), at("c"), at("d"))
+	}
+	if at("e") != md.CodeStyle || at("f") != md.LinkStyle {
+		t.Errorf("code = %+v, link = %+v", at("e"), at("f"))
+	}
+	if at("(http").Modifier&ui.ModifierDim == 0 {
+		t.Errorf("url should be dim, got %+v", at("(http"))
+	}
+
+	md.ShowLinkURLs = false
+	if _, rows = drawMarkdown(md, 40, 3); strings.Contains(rows[1], "http") {
+		t.Errorf("without urls, row = %q", rows[1])
+	}
+}
+
+func TestMarkdownTable(t *testing.T) {
+	md := widgets.NewMarkdown()
+	md.Border = false
+	md.Text = "| Name | Size |\n|:-----|-----:|\n| a \\| b | **2** |\n| long name | 3 |"
+	buf, rows := drawMarkdown(md, 30, 8)
+	p := findText(rows, "Name")
+	if p.Y != 1 || buf.GetCell(p).Style != md.TableHeaderStyle {
+		t.Fatalf("header at %v, rows = %q", p, rows)
+	}
+	if got := findText(rows, "a | b"); got.Y != p.Y+2 {
+		t.Errorf("escaped pipe at %v, rows = %q", got, rows)
+	}
+	if s := buf.GetCell(findText(rows, "2")).Style; s.Modifier&ui.ModifierBold == 0 {
+		t.Errorf("bold cell = %+v", s)
+	}
+	if x := findText(rows, "│").X; x != p.X+len("long name") {
+		t.Errorf("column separator at %d, rows = %q", x, rows)
+	}
+}
+
+func TestMarkdownScroll(t *testing.T) {
+	md := widgets.NewMarkdown()
+	var lines []string
+	for i := range 20 {
+		lines = append(lines, "- item "+string(rune('a'+i)))
+	}
+	md.Text = strings.Join(lines, "\n")
+	buf, rows := drawMarkdown(md, 20, 7)
+	if rows[1] != "│• item a         ▲│" || buf.GetCell(image.Pt(18, 2)).Rune != '█' {
+		t.Fatalf("rows = %q", rows)
+	}
+
+	md.HandleEvent(ui.Event{Type: ui.KeyboardEvent, ID: "<PageDown>"})
+	if md.OffsetY != 5 {
+		t.Errorf("offset after page down = %d", md.OffsetY)
+	}
+	md.ScrollBottom()
+	if _, rows = drawMarkdown(md, 20, 7); !strings.HasPrefix(rows[5], "│• item t") {
+		t.Errorf("rows at the bottom = %q", rows)
+	}
+	md.ScrollBy(-100)
+	if md.OffsetY != 0 {
+		t.Errorf("offset = %d", md.OffsetY)
+	}
+
+	wheel := ui.Event{Type: ui.MouseEvent, ID: "<MouseWheelDown>", Payload: ui.Mouse{X: 50, Y: 2}}
+	if md.HandleEvent(wheel) {
+		t.Error("wheel outside the widget should be ignored")
+	}
+	wheel.Payload = ui.Mouse{X: 5, Y: 2}
+	if !md.HandleEvent(wheel) || md.OffsetY != 1 {
+		t.Errorf("wheel inside, offset = %d", md.OffsetY)
+	}
+}
@@ -0,0 +1,93 @@
+package gotui_test
+
+import (
+	"strings"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+)
+
+// markupRuns describes cells as text runs with their style, so that tests
+// can compare whole lines.
+func markupRuns(cells []ui.Cell) []string {
+	var runs []string
+	var sb strings.Builder
+	for i, c := range cells {
+		if i > 0 && c.Style != cells[i-1].Style {
+			runs = append(runs, sb.String())
+			sb.Reset()
+		}
+		if sb.Len() == 0 {
+			sb.WriteString(ui.FormatStyle(c.Style) + "=")
+		}
+		sb.WriteString(c.Text())
+	}
+	if sb.Len() > 0 {
+		runs = append(runs, sb.String())
+	}
+	return runs
+}
+
+func TestMarkupLegacySyntax(t *testing.T) {
+	cells := ui.ParseStyles("a [b c](fg:red,mod:bold) [1] d", ui.StyleClear)
+	got := strings.Join(markupRuns(cells), "|")
+	want := "=a |fg:red,mod:bold=b c|= [1] d"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	for _, s := range []string{"[unclosed", "[text](fg:red", "x ] y", "[/]"} {
+		if got := ui.CellsToString(ui.ParseStyles(s, ui.StyleClear)); got != s {
+			t.Errorf("%q should stay literal, got %q", s, got)
+		}
+	}
+}
+
+func TestMarkupNestedSpans(t *testing.T) {
+	cells, err := ui.ParseMarkup("[a [b](mod:bold|italic) c](fg:#ff8800)", ui.StyleClear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(markupRuns(cells), "|")
+	want := "fg:#ff8800=a |fg:#ff8800,mod:bold|italic=b|fg:#ff8800= c"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMarkupTagsAndEscapes(t *testing.T) {
+	cells, err := ui.ParseMarkup(`[fg:rgb(0, 128, 0)]ok \[1\][/] [bg:#00f]x`, ui.StyleClear)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(markupRuns(cells), "|")
+	want := "fg:#008000=ok [1]|= |bg:#0000ff=x"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	s := `[a](b) \ c`
+	cells = ui.ParseStyles(ui.EscapeMarkup(s), ui.StyleClear)
+	if ui.CellsToString(cells) != s {
+		t.Errorf("escaped text came back as %q", ui.CellsToString(cells))
+	}
+}
+
+func TestParseMarkupErrors(t *testing.T) {
+	for src, want := range map[string]string{
+		"ab [cd":                "column 4: unclosed [",
+		"[x](fg:red":            "column 4: unclosed (",
+		"[x](fg:nocolor)":       `unknown color "nocolor"`,
+		"[x](size:2)":           `unknown style key "size"`,
+		"[fg:red,mod:loud]x[/]": `unknown modifier "loud"`,
+		"x[/]":                  "column 2: [/] without an open span",
+		"[x](fg:rgb(1,2))":      "rgb(r, g, b)",
+	} {
+		_, err := ui.ParseMarkup(src, ui.StyleClear)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: error %v, want %q", src, err, want)
+		}
+	}
+	if _, err := ui.ParseMarkup("[note: see below] and [1]", ui.StyleClear); err != nil {
+		t.Errorf("plain brackets should not be errors: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package gotui_test
+
+import (
+	"strings"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func TestPagesSwitch(t *testing.T) {
+	p := widgets.NewPages()
+	a, b := widgets.NewParagraph(), widgets.NewParagraph()
+	var log []string
+	pa := p.AddPage("a", a)
+	pa.OnShow = func() { log = append(log, "show a") }
+	pa.OnHide = func() { log = append(log, "hide a") }
+	pb := p.AddPage("b", b)
+	pb.OnShow = func() { log = append(log, "show b") }
+	p.SetRect(0, 0, 10, 5)
+	p.Draw(ui.NewBuffer(p.Rectangle))
+
+	if p.Current() != pa || a.Dx() == 0 || b.Dx() != 0 {
+		t.Fatalf("first page not shown: a=%v b=%v", a.Rectangle, b.Rectangle)
+	}
+	if !p.ShowPage("b") || p.Current().Name != "b" {
+		t.Fatalf("ShowPage(b) did not switch")
+	}
+	if p.ShowPage("missing") {
+		t.Errorf("ShowPage of a missing page reported success")
+	}
+	p.NextPage()
+	if got := strings.Join(log, ","); got != "show a,hide a,show b,show a" {
+		t.Errorf("lifecycle = %s", got)
+	}
+	if p.FocusedChild() != a {
+		t.Errorf("focus did not follow the current page")
+	}
+}
+
+func TestPagesBindTabs(t *testing.T) {
+	p := widgets.NewPages()
+	p.AddPage("one", widgets.NewParagraph())
+	p.AddPage("two", widgets.NewParagraph())
+	tabs := widgets.NewTabPane()
+	p.BindTabs(tabs)
+	if strings.Join(tabs.TabNames, ",") != "one,two" {
+		t.Fatalf("tab names = %v", tabs.TabNames)
+	}
+	p.AddPage("three", widgets.NewParagraph())
+	if len(tabs.TabNames) != 3 {
+		t.Errorf("tab names not updated: %v", tabs.TabNames)
+	}
+
+	p.SetRect(0, 0, 10, 5)
+	tabs.FocusRight()
+	p.Draw(ui.NewBuffer(p.Rectangle))
+	if p.Current().Name != "two" {
+		t.Errorf("tab change not followed, current = %s", p.Current().Name)
+	}
+	p.ShowPage("three")
+	if tabs.ActiveTabIndex != 2 {
+		t.Errorf("ActiveTabIndex = %d", tabs.ActiveTabIndex)
+	}
+	p.RemovePage("three")
+	if p.Current().Name != "two" || len(tabs.TabNames) != 2 || tabs.ActiveTabIndex != 1 {
+		t.Errorf("after remove current = %s, tabs = %v", p.Current().Name, tabs.TabNames)
+	}
+}
+
+func TestPagesRemoveCurrentMiddlePage(t *testing.T) {
+	p := widgets.NewPages()
+	a := widgets.NewParagraph()
+	p.AddPage("a", a)
+	p.AddPage("b", widgets.NewParagraph())
+	p.AddPage("c", widgets.NewParagraph())
+	p.ShowPage("b")
+	p.RemovePage("b")
+	p.SetRect(0, 0, 10, 5)
+	p.Draw(ui.NewBuffer(p.Rectangle))
+	if p.Current().Name != "a" || a.Dx() == 0 {
+		t.Errorf("after removing b current = %s, a = %v", p.Current().Name, a.Rectangle)
+	}
+}
@@ -0,0 +1,100 @@
+package gotui_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v3"
+	ui "github.com/metaspartan/gotui/v5"
+)
+
+func newResponsiveGrid() (*ui.Grid, *ui.Block, *ui.Block) {
+	a, b := ui.NewBlock(), ui.NewBlock()
+	g := ui.NewGrid()
+	g.Set(
+		ui.NewRow(1.0/2, a),
+		ui.NewRow(1.0/2, b),
+	)
+	g.SetBreakpoint(ui.Breakpoint{MinWidth: 120},
+		ui.NewRow(1.0,
+			ui.NewCol(1.0/2, a),
+			ui.NewCol(1.0/2, b),
+		),
+	)
+	return g, a, b
+}
+
+func TestGridBreakpoints(t *testing.T) {
+	g, a, b := newResponsiveGrid()
+
+	g.Respond(80, 24)
+	g.SetRect(0, 0, 80, 24)
+	g.Draw(ui.NewBuffer(g.Rectangle))
+	if a.Min.X != b.Min.X || a.Max.Y != b.Min.Y {
+		t.Errorf("narrow layout should stack: %v %v", a.Rectangle, b.Rectangle)
+	}
+
+	g.Respond(160, 40)
+	g.SetRect(0, 0, 160, 40)
+	g.Draw(ui.NewBuffer(g.Rectangle))
+	if g.Breakpoint().MinWidth != 120 || a.Max.X != b.Min.X || a.Min.Y != b.Min.Y {
+		t.Errorf("wide layout should be side by side: %v %v", a.Rectangle, b.Rectangle)
+	}
+	if len(g.Items) != 2 {
+		t.Errorf("switching layouts left %d items", len(g.Items))
+	}
+}
+
+func TestHiddenBelowMinSize(t *testing.T) {
+	a, b := ui.NewBlock(), ui.NewBlock()
+	b.MinWidth = 20
+	g := ui.NewGrid()
+	g.Set(ui.NewRow(1.0, ui.NewCol(1.0/2, a), ui.NewCol(1.0/2, b)))
+	g.SetRect(0, 0, 30, 5)
+	buf := ui.NewBuffer(g.Rectangle)
+	g.Draw(buf)
+
+	if !b.Hidden() {
+		t.Fatalf("block of width %d should be hidden", b.Dx())
+	}
+	if r := buf.GetCell(b.Min).Rune; r != ' ' {
+		t.Errorf("hidden block drew %q", r)
+	}
+	if r := buf.GetCell(a.Min).Rune; r == ' ' {
+		t.Errorf("visible block was not drawn")
+	}
+}
+
+func TestApplicationSwitchesLayoutOnResize(t *testing.T) {
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	sim.SetSize(80, 24)
+	app := ui.NewAppWithContext(ui.NewContext(&ui.Backend{Screen: sim}))
+	g, _, _ := newResponsiveGrid()
+	app.SetRoot(g, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- app.RunWithContext(ctx) }()
+
+	sim.SetSize(150, 40)
+	sim.EventQ() <- tcell.NewEventResize(150, 40)
+
+	breakpoint := func() ui.Breakpoint {
+		g.Lock()
+		defer g.Unlock()
+		return g.Breakpoint()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for breakpoint().MinWidth != 120 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	if bp := breakpoint(); bp.MinWidth != 120 {
+		t.Errorf("resize did not switch the layout, breakpoint %+v", bp)
+	}
+}
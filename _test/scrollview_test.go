@@ -0,0 +1,108 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+type scrollProbe struct {
+	ui.Block
+	last ui.Mouse
+}
+
+func (p *scrollProbe) HandleEvent(e ui.Event) bool {
+	if m, ok := e.Payload.(ui.Mouse); ok && e.ID == "<MouseLeft>" {
+		p.last = m
+		return true
+	}
+	return false
+}
+
+func newScrollContent() *widgets.Paragraph {
+	p := widgets.NewParagraph()
+	p.Border = false
+	for i := range 40 {
+		p.Text += string(rune('a'+i%26)) + "\n"
+	}
+	return p
+}
+
+func TestScrollViewBlitsVisibleWindow(t *testing.T) {
+	child := newScrollContent()
+	sv := widgets.NewScrollView(child)
+	sv.ContentHeight = 42
+	sv.SetRect(0, 0, 20, 10)
+	sv.OffsetY = 5
+	buf := ui.NewBuffer(sv.Rectangle)
+	sv.Draw(buf)
+
+	view := sv.Viewport()
+	if view.Dx() != sv.Inner.Dx()-1 {
+		t.Fatalf("vertical scrollbar should take one column, viewport %v", view)
+	}
+	// The paragraph starts its text at content row 1 inside its own inset.
+	if got := buf.GetCell(view.Min.Add(image.Pt(1, 0))).Rune; got != 'e' {
+		t.Errorf("first visible row = %q, want 'e'", got)
+	}
+	if got := buf.GetCell(image.Pt(view.Max.X, view.Min.Y)).Rune; got != sv.VScrollbar.BeginRune {
+		t.Errorf("scrollbar not drawn, got %q", got)
+	}
+}
+
+func TestScrollViewScrolling(t *testing.T) {
+	sv := widgets.NewScrollView(newScrollContent())
+	sv.ContentHeight = 42
+	sv.SetRect(0, 0, 20, 10)
+	sv.Draw(ui.NewBuffer(sv.Rectangle))
+
+	sv.HandleEvent(ui.Event{Type: ui.MouseEvent, ID: "<MouseWheelDown>", Payload: ui.Mouse{X: 2, Y: 2}})
+	if sv.OffsetY != 1 {
+		t.Errorf("wheel down: OffsetY = %d", sv.OffsetY)
+	}
+	sv.HandleEvent(ui.Event{Type: ui.KeyboardEvent, ID: "<End>"})
+	if want := 42 - sv.Viewport().Dy(); sv.OffsetY != want {
+		t.Errorf("End: OffsetY = %d, want %d", sv.OffsetY, want)
+	}
+	sv.HandleEvent(ui.Event{Type: ui.KeyboardEvent, ID: "<PageDown>"})
+	if want := 42 - sv.Viewport().Dy(); sv.OffsetY != want {
+		t.Errorf("offset should stay clamped, got %d", sv.OffsetY)
+	}
+	sv.HandleEvent(ui.Event{Type: ui.KeyboardEvent, ID: "<Home>"})
+	if sv.OffsetY != 0 {
+		t.Errorf("Home: OffsetY = %d", sv.OffsetY)
+	}
+}
+
+func TestScrollViewScrollToAndMouse(t *testing.T) {
+	flex := widgets.NewFlex()
+	flex.Border = false
+	var target *scrollProbe
+	for i := range 10 {
+		p := &scrollProbe{Block: *ui.NewBlock()}
+		flex.AddItem(p, 5, 0, false)
+		if i == 7 {
+			target = p
+		}
+	}
+	sv := widgets.NewScrollView(flex)
+	sv.ContentHeight = 52
+	sv.SetRect(0, 0, 30, 12)
+	sv.Draw(ui.NewBuffer(sv.Rectangle))
+
+	sv.ScrollTo(target)
+	view := sv.Viewport()
+	r := target.GetRect()
+	if r.Min.Y < sv.OffsetY || r.Max.Y > sv.OffsetY+view.Dy() {
+		t.Fatalf("target %v not visible at offset %d", r, sv.OffsetY)
+	}
+
+	sv.Draw(ui.NewBuffer(sv.Rectangle))
+	screen := r.Min.Sub(image.Pt(sv.OffsetX, sv.OffsetY)).Add(view.Min).Add(image.Pt(1, 1))
+	sv.HandleEvent(ui.Event{Type: ui.MouseEvent, ID: "<MouseLeft>", Payload: ui.Mouse{X: screen.X, Y: screen.Y}})
+	if want := r.Min.Add(image.Pt(1, 1)); target.last.X != want.X || target.last.Y != want.Y {
+		t.Errorf("child got mouse at %+v, want %v", target.last, want)
+	}
+}
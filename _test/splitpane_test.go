@@ -0,0 +1,140 @@
+package gotui_test
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v3"
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func newTestSplit() (*widgets.SplitPane, *ui.Block, *ui.Block) {
+	a, b := ui.NewBlock(), ui.NewBlock()
+	s := widgets.NewSplitPane(ui.LayoutHorizontal, a, b)
+	s.Border = false
+	s.SetRect(-1, -1, 22, 11) // Inner is 0,0 - 21,10
+	s.Draw(ui.NewBuffer(s.Rectangle))
+	return s, a, b
+}
+
+func splitMouse(id string, x, y int, drag bool) ui.Event {
+	return ui.Event{Type: ui.MouseEvent, ID: id, Payload: ui.Mouse{X: x, Y: y, Drag: drag}}
+}
+
+func TestSplitPaneLayout(t *testing.T) {
+	s, a, b := newTestSplit()
+	if a.Dx() != 10 || b.Dx() != 10 || b.Min.X != 11 {
+		t.Fatalf("unexpected panes %v %v", a.Rectangle, b.Rectangle)
+	}
+	buf := ui.NewBuffer(s.Rectangle)
+	s.Draw(buf)
+	if got := buf.GetCell(image.Pt(a.Max.X, a.Min.Y)).Rune; got != ui.VERTICAL_LINE {
+		t.Errorf("divider rune = %q", got)
+	}
+}
+
+func TestSplitPaneSmallRatio(t *testing.T) {
+	a, b := ui.NewBlock(), ui.NewBlock()
+	s := widgets.NewSplitPane(ui.LayoutHorizontal, a, b)
+	s.Border = false
+	s.Panes[0].Ratio, s.Panes[1].Ratio = 0.0004, 0.9996
+	s.SetRect(-1, -1, 3002, 2) // 3000 cells besides the divider
+	s.Draw(ui.NewBuffer(s.Rectangle))
+	// 0.0004 of 3000 cells is 1.2 cells, which used to round to no weight.
+	if a.Dx() != 1 || b.Dx() != 2999 {
+		t.Errorf("pane widths = %d, %d, want 1, 2999", a.Dx(), b.Dx())
+	}
+}
+
+func TestSplitPaneDrag(t *testing.T) {
+	s, a, b := newTestSplit()
+	var saved widgets.SplitPaneState
+	s.OnResize = func(st widgets.SplitPaneState) { saved = st }
+
+	s.HandleEvent(splitMouse("<MouseLeft>", 10, 3, false))
+	s.HandleEvent(splitMouse("<MouseLeft>", 14, 3, true))
+	s.HandleEvent(splitMouse("<MouseRelease>", 14, 3, false))
+	s.Draw(ui.NewBuffer(s.Rectangle))
+	if a.Dx() != 14 || b.Dx() != 6 {
+		t.Fatalf("after drag got widths %d %d", a.Dx(), b.Dx())
+	}
+	if len(saved.Ratios) != 2 || saved.Ratios[0] != 0.7 {
+		t.Errorf("OnResize state = %+v", saved)
+	}
+
+	s.HandleEvent(splitMouse("<MouseLeft>", 5, 3, true))
+	s.Draw(ui.NewBuffer(s.Rectangle))
+	if a.Dx() != 14 {
+		t.Errorf("drag after release moved the divider")
+	}
+}
+
+func TestSplitPaneMinSizeKeysAndCollapse(t *testing.T) {
+	s, a, b := newTestSplit()
+	s.Panes[1].MinSize = 8
+	s.Step = 5
+	s.HandleEvent(ui.Event{Type: ui.KeyboardEvent, ID: s.GrowKey})
+	s.Draw(ui.NewBuffer(s.Rectangle))
+	if a.Dx() != 12 || b.Dx() != 8 {
+		t.Errorf("MinSize not respected: %d %d", a.Dx(), b.Dx())
+	}
+
+	state := s.State()
+	s.Collapse(0)
+	s.Draw(ui.NewBuffer(s.Rectangle))
+	if b.Dx() != 20 {
+		t.Errorf("collapsed pane should give its space away, b is %d", b.Dx())
+	}
+	s.Expand(0)
+	s.Draw(ui.NewBuffer(s.Rectangle))
+	if a.Dx() != 12 {
+		t.Errorf("expand did not restore the size, a is %d", a.Dx())
+	}
+
+	s2, a2, _ := newTestSplit()
+	s2.SetState(state)
+	s2.Draw(ui.NewBuffer(s2.Rectangle))
+	if a2.Dx() != 12 {
+		t.Errorf("restored state gives width %d", a2.Dx())
+	}
+}
+
+func TestMouseDragEvents(t *testing.T) {
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Fini()
+	events := (&ui.Backend{Screen: sim}).PollEvents()
+
+	sim.InjectMouse(1, 1, tcell.Button1, 0)
+	sim.InjectMouse(2, 1, tcell.Button1, 0)
+	sim.InjectMouse(2, 1, tcell.ButtonNone, 0)
+	sim.InjectMouse(3, 1, tcell.ButtonNone, 0)
+	sim.InjectMouse(4, 1, tcell.Button3, 0)
+	sim.InjectMouse(4, 1, tcell.ButtonNone, 0)
+
+	want := []struct {
+		id   string
+		drag bool
+	}{
+		{"<MouseLeft>", false},
+		{"<MouseLeft>", true},
+		{"<MouseRelease>", false},
+		{"<MouseMove>", false},
+		{"<MouseRight>", false},
+		{"<MouseRelease>", false},
+	}
+	for _, w := range want {
+		select {
+		case e := <-events:
+			if e.ID != w.id || e.Payload.(ui.Mouse).Drag != w.drag {
+				t.Errorf("got %s drag=%v, want %s drag=%v", e.ID, e.Payload.(ui.Mouse).Drag, w.id, w.drag)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", w.id)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package gotui_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	ui "github.com/metaspartan/gotui/v5"
+	uissh "github.com/metaspartan/gotui/v5/ssh"
+	"github.com/metaspartan/gotui/v5/widgets"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Contains(s string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Contains(b.buf.Bytes(), []byte(s))
+}
+
+func startSSHServer(t *testing.T, handler uissh.Handler, configure func(*uissh.Server)) (*uissh.Server, string) {
+	t.Helper()
+	signer, err := uissh.GenerateHostKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := uissh.NewServer("", handler)
+	srv.AddHostKey(signer)
+	if configure != nil {
+		configure(srv)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { srv.Close() })
+	return srv, l.Addr().String()
+}
+
+func dialSSH(t *testing.T, addr string) *gossh.Client {
+	t.Helper()
+	client, err := gossh.Dial("tcp", addr, &gossh.ClientConfig{
+		User:            "tester",
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func openShell(t *testing.T, client *gossh.Client, out *syncBuffer) (*gossh.Session, func(string)) {
+	t.Helper()
+	sess, err := client.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sess.Stdout = out
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.RequestPty("xterm-256color", 24, 80, gossh.TerminalModes{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Shell(); err != nil {
+		t.Fatal(err)
+	}
+	return sess, func(s string) { _, _ = stdin.Write([]byte(s)) }
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func paragraphApp(text string, root **widgets.Paragraph) uissh.Handler {
+	return func(s *uissh.Session) *ui.Application {
+		p := widgets.NewParagraphWithContext(s.UI)
+		p.Text = text
+		if root != nil {
+			*root = p
+		}
+		app := ui.NewAppWithContext(s.UI)
+		app.SetRoot(p, true)
+		return app
+	}
+}
+
+func TestSSHServerSessionLifecycle(t *testing.T) {
+	var p *widgets.Paragraph
+	_, addr := startSSHServer(t, paragraphApp("hello over ssh", &p), nil)
+	client := dialSSH(t, addr)
+
+	out := &syncBuffer{}
+	sess, send := openShell(t, client, out)
+	waitFor(t, "initial frame", func() bool { return out.Contains("hello") })
+
+	if err := sess.WindowChange(30, 100); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "resize", func() bool {
+		p.Lock()
+		defer p.Unlock()
+		return p.GetRect().Dx() == 100 && p.GetRect().Dy() == 30
+	})
+
+	send("q")
+	if err := sess.Wait(); err != nil {
+		t.Errorf("expected clean exit, got %v", err)
+	}
+}
+
+func TestSSHServerMaxSessions(t *testing.T) {
+	_, addr := startSSHServer(t, paragraphApp("busy", nil), func(s *uissh.Server) {
+		s.MaxSessions = 1
+	})
+	client := dialSSH(t, addr)
+
+	out := &syncBuffer{}
+	_, _ = openShell(t, client, out)
+	waitFor(t, "first session", func() bool { return out.Contains("busy") })
+
+	if _, err := client.NewSession(); err == nil {
+		t.Error("expected second session to be rejected")
+	}
+}
+
+func TestSSHServerIdleTimeout(t *testing.T) {
+	_, addr := startSSHServer(t, paragraphApp("idle", nil), func(s *uissh.Server) {
+		s.IdleTimeout = 200 * time.Millisecond
+	})
+	client := dialSSH(t, addr)
+
+	out := &syncBuffer{}
+	sess, _ := openShell(t, client, out)
+	done := make(chan error, 1)
+	go func() { done <- sess.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle session was not closed")
+	}
+}
+
+func TestSSHServerShutdown(t *testing.T) {
+	signer, err := uissh.GenerateHostKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := uissh.NewServer("", paragraphApp("shutdown", nil))
+	srv.AddHostKey(signer)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(l) }()
+
+	client := dialSSH(t, l.Addr().String())
+	out := &syncBuffer{}
+	sess, _ := openShell(t, client, out)
+	waitFor(t, "session start", func() bool { return out.Contains("shutdown") })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := <-served; !errors.Is(err, uissh.ErrServerClosed) {
+		t.Errorf("expected ErrServerClosed, got %v", err)
+	}
+	_ = sess.Wait()
+	if srv.ActiveSessions() != 0 {
+		t.Errorf("expected no active sessions, got %d", srv.ActiveSessions())
+	}
+}
@@ -1,9 +1,11 @@
 package gotui_test
 
 import (
+	"image"
 	"testing"
 
 	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
 )
 
 func TestGenerateGradient(t *testing.T) {
@@ -34,3 +36,139 @@ func TestGenerateGradient(t *testing.T) {
 		t.Errorf("Expected length 1, got %d", len(g))
 	}
 }
+
+func rgbOf(c ui.Color) [3]int32 {
+	r, g, b := c.RGB()
+	return [3]int32{r, g, b}
+}
+
+func TestMixColorsSpaces(t *testing.T) {
+	black, white := ui.NewRGBColor(0, 0, 0), ui.NewRGBColor(255, 255, 255)
+	red, blue := ui.NewRGBColor(255, 0, 0), ui.NewRGBColor(0, 0, 255)
+
+	if got := rgbOf(ui.MixColors(black, white, 0.5, ui.ColorSpaceRGB)); got != [3]int32{127, 127, 127} {
+		t.Errorf("rgb midpoint = %v", got)
+	}
+	// Half way in OKLab lightness is darker than half way in sRGB values.
+	if got := rgbOf(ui.MixColors(black, white, 0.5, ui.ColorSpaceOKLab)); got != [3]int32{99, 99, 99} {
+		t.Errorf("oklab midpoint = %v", got)
+	}
+	if got := rgbOf(ui.MixColors(red, blue, 0.5, ui.ColorSpaceHSL)); got != [3]int32{255, 0, 255} {
+		t.Errorf("hsl midpoint = %v", got)
+	}
+	for _, space := range []ui.ColorSpace{ui.ColorSpaceRGB, ui.ColorSpaceOKLab, ui.ColorSpaceOKLCH, ui.ColorSpaceHSL} {
+		if got := rgbOf(ui.MixColors(red, blue, 0, space)); got != [3]int32{255, 0, 0} {
+			t.Errorf("space %d: start = %v", space, got)
+		}
+		if got := rgbOf(ui.MixColors(red, blue, 1, space)); got != [3]int32{0, 0, 255} {
+			t.Errorf("space %d: end = %v", space, got)
+		}
+	}
+	// OKLCH keeps the chroma up where RGB and OKLab pass through gray.
+	lab, lch := rgbOf(ui.MixColors(red, ui.NewRGBColor(0, 255, 255), 0.5, ui.ColorSpaceOKLab)),
+		rgbOf(ui.MixColors(red, ui.NewRGBColor(0, 255, 255), 0.5, ui.ColorSpaceOKLCH))
+	spread := func(c [3]int32) int32 { return max(c[0], c[1], c[2]) - min(c[0], c[1], c[2]) }
+	if spread(lch) <= spread(lab)+50 {
+		t.Errorf("oklch midpoint %v should be more colorful than oklab %v", lch, lab)
+	}
+}
+
+func TestGradientShapes(t *testing.T) {
+	g := ui.Gradient{Start: ui.NewRGBColor(0, 0, 0), End: ui.NewRGBColor(240, 240, 240)}
+	area := image.Rect(10, 10, 21, 21)
+	gray := func(direction int, x, y int) int32 {
+		g.Direction = direction
+		return rgbOf(g.ColorAt(image.Pt(x, y), area))[0]
+	}
+	for _, tc := range []struct {
+		name       string
+		direction  int
+		x, y, want int
+	}{
+		{"horizontal end", ui.GradientHorizontal, 20, 10, 240},
+		{"vertical middle", ui.GradientVertical, 10, 15, 120},
+		{"diagonal start", ui.GradientDiagonal, 10, 10, 0},
+		{"diagonal end", ui.GradientDiagonal, 20, 20, 240},
+		{"anti-diagonal start", ui.GradientAntiDiagonal, 10, 20, 0},
+		{"anti-diagonal end", ui.GradientAntiDiagonal, 20, 10, 240},
+		{"radial center", ui.GradientRadial, 15, 15, 0},
+		{"radial corner", ui.GradientRadial, 10, 10, 240},
+		{"conic top", ui.GradientConic, 15, 10, 0},
+		{"conic right", ui.GradientConic, 20, 15, 60},
+		{"conic bottom", ui.GradientConic, 15, 20, 120},
+	} {
+		if got := gray(tc.direction, tc.x, tc.y); got != int32(tc.want) {
+			t.Errorf("%s: %d, want %d", tc.name, got, tc.want)
+		}
+	}
+
+	stops := ui.Gradient{Stops: []ui.Color{ui.NewRGBColor(0, 0, 0), ui.NewRGBColor(200, 0, 0), ui.NewRGBColor(200, 200, 0)}}
+	if got := stops.Colors(5); rgbOf(got[2]) != [3]int32{200, 0, 0} || rgbOf(got[3]) != [3]int32{200, 100, 0} {
+		t.Errorf("colors through stops = %v", got)
+	}
+}
+
+func TestGradientFills(t *testing.T) {
+	b := ui.NewBlock()
+	b.BackgroundGradient = ui.Gradient{Enabled: true, Start: ui.NewRGBColor(0, 0, 0), End: ui.NewRGBColor(0, 0, 200)}
+	b.SetRect(0, 0, 5, 3)
+	buf := ui.NewBuffer(image.Rect(0, 0, 5, 3))
+	b.Draw(buf)
+	if bg := rgbOf(buf.GetCell(image.Pt(3, 1)).Style.Bg); bg != [3]int32{0, 0, 150} {
+		t.Errorf("background = %v", bg)
+	}
+	if bg := buf.GetCell(image.Pt(0, 0)).Style.Bg; bg != ui.ColorClear {
+		t.Errorf("border should not be filled, got %v", bg)
+	}
+
+	value := ui.Gradient{Enabled: true, Direction: ui.GradientValue, Start: ui.NewRGBColor(0, 200, 0), End: ui.NewRGBColor(200, 0, 0)}
+	gauge := widgets.NewGauge()
+	gauge.Percent = 50
+	gauge.Gradient = value
+	gauge.SetRect(0, 0, 12, 3)
+	buf = ui.NewBuffer(image.Rect(0, 0, 12, 3))
+	gauge.Draw(buf)
+	if bg := rgbOf(buf.GetCell(image.Pt(1, 1)).Style.Bg); bg != [3]int32{100, 100, 0} {
+		t.Errorf("gauge at 50%% = %v", bg)
+	}
+
+	chart := widgets.NewBarChart()
+	chart.Data = []float64{1, 4}
+	chart.Gradient = value
+	chart.SetRect(0, 0, 10, 8)
+	buf = ui.NewBuffer(image.Rect(0, 0, 10, 8))
+	chart.Draw(buf)
+	if bg := rgbOf(buf.GetCell(image.Pt(5, 5)).Style.Bg); bg != [3]int32{200, 0, 0} {
+		t.Errorf("tallest bar = %v", bg)
+	}
+	if bg := rgbOf(buf.GetCell(image.Pt(1, 5)).Style.Bg); bg != [3]int32{50, 150, 0} {
+		t.Errorf("short bar = %v", bg)
+	}
+}
+
+func TestGradientShapesOnText(t *testing.T) {
+	diagonal := ui.Gradient{Enabled: true, Direction: ui.GradientDiagonal, Start: ui.NewRGBColor(0, 0, 0), End: ui.NewRGBColor(200, 0, 0)}
+
+	p := widgets.NewParagraph()
+	p.Text = "abcde\nfghij\nklmno"
+	p.Gradient = diagonal
+	p.SetRect(0, 0, 7, 5)
+	buf := ui.NewBuffer(p.GetRect())
+	p.Draw(buf)
+	if fg := rgbOf(buf.GetCell(image.Pt(1, 1)).Style.Fg); fg != [3]int32{0, 0, 0} {
+		t.Errorf("paragraph top left = %v", fg)
+	}
+	if fg := rgbOf(buf.GetCell(image.Pt(5, 3)).Style.Fg); fg != [3]int32{200, 0, 0} {
+		t.Errorf("paragraph bottom right = %v", fg)
+	}
+
+	l := widgets.NewList()
+	l.Rows = []string{"abcde", "fghij", "klmno"}
+	l.Gradient = diagonal
+	l.SetRect(0, 0, 7, 5)
+	buf = ui.NewBuffer(l.GetRect())
+	l.Draw(buf)
+	if fg := rgbOf(buf.GetCell(image.Pt(3, 2)).Style.Fg); fg != [3]int32{100, 0, 0} {
+		t.Errorf("list center = %v", fg)
+	}
+}
@@ -0,0 +1,213 @@
+package gotui_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v3"
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func mustStylesheet(t *testing.T, src string) *ui.Stylesheet {
+	t.Helper()
+	sheet, err := ui.ParseStylesheet(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sheet
+}
+
+func TestStylesheetCascade(t *testing.T) {
+	sheet := mustStylesheet(t, `
+/* every widget */
+Block > title { fg: white }
+Table > header { fg: green }
+Table.alert > header { fg: red; bold }
+#main > title, Paragraph#main > title { fg: yellow }
+`)
+	plain := widgets.NewTable()
+	alert := widgets.NewTable()
+	alert.Classes = []string{"alert"}
+	alert.ID = "main"
+	grid := ui.NewGrid()
+	grid.Set(ui.NewRow(1, ui.NewCol(0.5, plain), ui.NewCol(0.5, alert)))
+
+	sheet.Apply(grid, nil)
+
+	if plain.TitleStyle.Fg != ui.ColorWhite {
+		t.Errorf("plain title = %+v", plain.TitleStyle)
+	}
+	if plain.RowStyles[0].Fg != ui.ColorGreen {
+		t.Errorf("plain header = %+v", plain.RowStyles[0])
+	}
+	if got := alert.RowStyles[0]; got.Fg != ui.ColorRed || got.Modifier != ui.ModifierBold {
+		t.Errorf("alert header = %+v", got)
+	}
+	if alert.TitleStyle.Fg != ui.ColorYellow {
+		t.Errorf("id rule should beat type rule, title = %+v", alert.TitleStyle)
+	}
+}
+
+func TestStylesheetSourceOrderAndStates(t *testing.T) {
+	sheet := mustStylesheet(t, `
+Tabs > tab:active { fg: green }
+TabPane > tab:active { fg: blue }
+List > selected { mod: reverse }
+Checkbox:checked { fg: green }
+Button:active > background { bg: red }
+`)
+	tabs := widgets.NewTabPane("a", "b")
+	list := widgets.NewList()
+	box := widgets.NewCheckbox("ok")
+	button := widgets.NewButton("go")
+	flex := widgets.NewFlex()
+	for _, w := range []ui.Drawable{tabs, list, box, button} {
+		flex.AddItem(w, 0, 1, false)
+	}
+
+	before := box.TextStyle
+	sheet.Apply(flex, nil)
+	if tabs.ActiveTabStyle.Fg != ui.ColorBlue {
+		t.Errorf("later rule should win, active tab = %+v", tabs.ActiveTabStyle)
+	}
+	if list.SelectedStyle.Modifier != ui.ModifierReverse {
+		t.Errorf("selected = %+v", list.SelectedStyle)
+	}
+	if box.TextStyle != before || button.BackgroundColor != ui.ColorClear {
+		t.Error("state rules applied without the state")
+	}
+
+	box.Checked = true
+	button.IsActive = true
+	sheet.Apply(flex, nil)
+	if box.TextStyle.Fg != ui.ColorGreen {
+		t.Errorf("checked text = %+v", box.TextStyle)
+	}
+	if button.BackgroundColor != ui.ColorRed {
+		t.Errorf("active background = %v", button.BackgroundColor)
+	}
+
+	box.Checked = false
+	sheet.Apply(flex, nil)
+	if box.TextStyle != before {
+		t.Errorf("text should revert when unchecked, got %+v", box.TextStyle)
+	}
+}
+
+func TestStylesheetCombinators(t *testing.T) {
+	sheet := mustStylesheet(t, `
+Grid Paragraph { fg: red }
+Flex > Paragraph { fg: green }
+.dim { mod: dim }
+`)
+	nested := widgets.NewParagraph()
+	direct := widgets.NewParagraph()
+	direct.AddClass("dim")
+	flex := widgets.NewFlex()
+	flex.AddItem(direct, 0, 1, false)
+	inner := widgets.NewFlex()
+	inner.AddItem(nested, 0, 1, false)
+	split := widgets.NewSplitPane(ui.LayoutHorizontal, flex, inner)
+	grid := ui.NewGrid()
+	grid.Set(ui.NewRow(1, split))
+
+	sheet.Apply(grid, nil)
+	if nested.TextStyle.Fg != ui.ColorGreen {
+		t.Errorf("nested = %+v", nested.TextStyle)
+	}
+	if direct.TextStyle.Fg != ui.ColorGreen || direct.TextStyle.Modifier != ui.ModifierDim {
+		t.Errorf("direct = %+v", direct.TextStyle)
+	}
+
+	outside := widgets.NewParagraph()
+	before := outside.TextStyle
+	sheet.Apply(outside, nil)
+	if outside.TextStyle.Fg != before.Fg {
+		t.Errorf("descendant rule matched a root widget: %+v", outside.TextStyle)
+	}
+}
+
+func TestStylesheetKeepsManualChanges(t *testing.T) {
+	sheet := mustStylesheet(t, `Paragraph.warn { fg: yellow }`)
+	p := widgets.NewParagraph()
+	p.AddClass("warn")
+	sheet.Apply(p, nil)
+	if p.TextStyle.Fg != ui.ColorYellow {
+		t.Fatalf("text = %+v", p.TextStyle)
+	}
+
+	p.TextStyle = ui.NewStyle(ui.ColorRed)
+	sheet.Apply(p, nil)
+	if p.TextStyle.Fg != ui.ColorYellow {
+		t.Errorf("rule should still apply on top, got %+v", p.TextStyle)
+	}
+	p.RemoveClass("warn")
+	sheet.Apply(p, nil)
+	if p.TextStyle.Fg != ui.ColorRed {
+		t.Errorf("should revert to the value set by hand, got %+v", p.TextStyle)
+	}
+}
+
+func TestStylesheetFocusedInApplication(t *testing.T) {
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Fini()
+	sim.SetSize(40, 10)
+	ctx := ui.NewContext(&ui.Backend{Screen: sim})
+	app := ui.NewAppWithContext(ctx)
+
+	a := widgets.NewListWithContext(ctx)
+	b := widgets.NewListWithContext(ctx)
+	flex := widgets.NewFlexWithContext(ctx)
+	flex.AddItem(a, 0, 1, true)
+	flex.AddItem(b, 0, 1, false)
+	app.SetRoot(flex, true)
+	app.SetStylesheet(mustStylesheet(t, `List:focused > border { fg: yellow }`))
+
+	app.Redraw()
+	if a.BorderStyle.Fg != ui.ColorYellow || b.BorderStyle.Fg == ui.ColorYellow {
+		t.Errorf("borders = %+v, %+v", a.BorderStyle, b.BorderStyle)
+	}
+	flex.FocusNext()
+	app.Redraw()
+	if a.BorderStyle.Fg == ui.ColorYellow || b.BorderStyle.Fg != ui.ColorYellow {
+		t.Errorf("after moving focus, borders = %+v, %+v", a.BorderStyle, b.BorderStyle)
+	}
+}
+
+func TestParseStylesheetErrors(t *testing.T) {
+	for src, want := range map[string]string{
+		"List > row { fg: nocolor }": "line 1:14",
+		"List {\n  size: 3\n}":       "line 2:3: unknown property",
+		"List row { fg: red }":       "must come last",
+		"List > { fg: red }":         "ends with >",
+		"List { fg: red":             "unclosed",
+		"List { sparkly }":           "unknown modifier",
+	} {
+		_, err := ui.ParseStylesheet(src)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: error %v, want %q", src, err, want)
+		}
+	}
+}
+
+func TestLoadStylesheet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.css")
+	if err := os.WriteFile(path, []byte("Gauge > bar { color: green }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := ui.LoadStylesheet(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := widgets.NewGauge()
+	sheet.Apply(g, nil)
+	if g.BarColor != ui.ColorGreen {
+		t.Errorf("bar = %v", g.BarColor)
+	}
+}
@@ -0,0 +1,168 @@
+package gotui_test
+
+import (
+	"image"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func TestThemeRoundTrip(t *testing.T) {
+	for _, name := range ui.BuiltinThemeNames() {
+		theme, _ := ui.BuiltinTheme(name)
+		for _, format := range []ui.ThemeFormat{ui.ThemeTOML, ui.ThemeJSON, ui.ThemeYAML} {
+			data, err := ui.MarshalTheme(theme, format)
+			if err != nil {
+				t.Fatalf("%s/%s: %v", name, format, err)
+			}
+			got, err := ui.ParseTheme(data, format)
+			if err != nil {
+				t.Fatalf("%s/%s: %v\n%s", name, format, err, data)
+			}
+			if !reflect.DeepEqual(got, theme) {
+				t.Errorf("%s/%s did not round trip:\n%s", name, format, data)
+			}
+		}
+	}
+}
+
+func TestParseThemeTOML(t *testing.T) {
+	src := `
+# a light theme
+default = "fg:black"
+tab = { active = "fg:red" }
+
+[block]
+border = "fg:#586e75"   # grey border
+title = 'fg:navy,mod:bold|italic'
+
+[bar_chart]
+bars = [
+  "red",
+  "#268bd2", # blue
+]
+
+[paragraph]
+text = """fg:white,\
+  mod:bold"""
+`
+	theme, err := ui.ParseTheme([]byte(src), ui.ThemeTOML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Default != ui.NewStyle(ui.ColorBlack) {
+		t.Errorf("default = %+v", theme.Default)
+	}
+	if theme.Block.Border.Fg != ui.NewRGBColor(0x58, 0x6e, 0x75) {
+		t.Errorf("border = %+v", theme.Block.Border)
+	}
+	if theme.Block.Title != ui.NewStyle(ui.ColorNavy, ui.ColorClear, ui.ModifierBold|ui.ModifierItalic) {
+		t.Errorf("title = %+v", theme.Block.Title)
+	}
+	if len(theme.BarChart.Bars) != 2 || theme.BarChart.Bars[1] != ui.NewRGBColor(0x26, 0x8b, 0xd2) {
+		t.Errorf("bars = %v", theme.BarChart.Bars)
+	}
+	if theme.Paragraph.Text != ui.NewStyle(ui.ColorWhite, ui.ColorClear, ui.ModifierBold) {
+		t.Errorf("paragraph text = %+v", theme.Paragraph.Text)
+	}
+	if theme.Tab.Active != ui.NewStyle(ui.ColorRed) || theme.Tab.Inactive != ui.DefaultTheme().Tab.Inactive {
+		t.Errorf("tab = %+v, want the inline table over the defaults", theme.Tab)
+	}
+	if theme.List != ui.DefaultTheme().List {
+		t.Errorf("missing keys did not keep the defaults")
+	}
+}
+
+func TestParseThemeErrors(t *testing.T) {
+	cases := []struct{ src, want string }{
+		{"[block]\nborders = \"fg:red\"", `unknown theme key "block.borders"`},
+		{"[gauge]\nbar = \"fuchsia-ish\"", `gauge.bar: unknown color`},
+		{"[plot]\nlines = [\"red\", \"nope\"]", `plot.lines[1]`},
+		{"default = fg:red", "line 1"},
+		{"default = 3", "default: expected a string"},
+	}
+	for _, c := range cases {
+		_, err := ui.ParseTheme([]byte(c.src), ui.ThemeTOML)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("ParseTheme(%q) error = %v, want %q", c.src, err, c.want)
+		}
+	}
+}
+
+func TestSaveAndLoadTheme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	if err := ui.SaveTheme(path, ui.SolarizedTheme()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ui.LoadTheme(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, ui.SolarizedTheme()) {
+		t.Errorf("loaded theme differs from the saved one")
+	}
+	if _, err := ui.LoadTheme("theme.ini"); err == nil {
+		t.Errorf("unknown extension was accepted")
+	}
+}
+
+func TestSetThemeRestylesLiveWidgets(t *testing.T) {
+	ctx := ui.NewContext(&ui.Backend{})
+	p := widgets.NewParagraphWithContext(ctx)
+	bc := widgets.NewBarChartWithContext(ctx)
+	custom := ui.NewStyle(ui.ColorOrange)
+	p.BorderStyle = custom
+
+	ctx.SetTheme(ui.LightTheme())
+	buf := ui.NewBuffer(image.Rect(0, 0, 10, 5))
+	for _, d := range []ui.Drawable{p, bc} {
+		d.SetRect(0, 0, 10, 5)
+		d.Draw(buf)
+	}
+
+	light := ui.LightTheme()
+	if p.TextStyle != light.Paragraph.Text || p.TitleStyle != light.Block.Title {
+		t.Errorf("paragraph not restyled: %+v %+v", p.TextStyle, p.TitleStyle)
+	}
+	if p.BorderStyle != custom {
+		t.Errorf("value set by hand was replaced: %+v", p.BorderStyle)
+	}
+	if !reflect.DeepEqual(bc.BarColors, light.BarChart.Bars) {
+		t.Errorf("bar chart colors = %v", bc.BarColors)
+	}
+	if ctx.Theme.Paragraph.Text != light.Paragraph.Text {
+		t.Errorf("context theme not replaced")
+	}
+}
+
+func TestSetThemeWhileConstructing(t *testing.T) {
+	ctx := ui.NewContext(&ui.Backend{})
+	old := ctx.Theme
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			widgets.NewParagraphWithContext(ctx)
+			widgets.NewBarChartWithContext(ctx)
+		}
+	}()
+	for range 100 {
+		ctx.SetTheme(ui.LightTheme())
+		ctx.SetTheme(ui.DarkTheme())
+	}
+	<-done
+
+	// The theme is swapped rather than overwritten.
+	if !reflect.DeepEqual(*old, ui.DefaultTheme()) {
+		t.Errorf("SetTheme changed the theme it replaced")
+	}
+	// Changing the theme in place still styles new widgets.
+	ctx.Theme.Paragraph.Text = ui.NewStyle(ui.ColorGreen)
+	if p := widgets.NewParagraphWithContext(ctx); p.TextStyle != ctx.Theme.Paragraph.Text {
+		t.Errorf("text style = %+v, want the edited theme", p.TextStyle)
+	}
+}
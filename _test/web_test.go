@@ -0,0 +1,324 @@
+package gotui_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/web"
+	"github.com/metaspartan/gotui/v5/widgets"
+	"golang.org/x/net/websocket"
+)
+
+type frameCell struct {
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+	Text string `json:"t"`
+}
+
+type serverMessage struct {
+	Type  string      `json:"type"`
+	Mode  string      `json:"mode"`
+	Cols  int         `json:"cols"`
+	Rows  int         `json:"rows"`
+	Cells []frameCell `json:"cells"`
+	Error string      `json:"error"`
+}
+
+// webClient collects everything a test WebSocket client receives.
+type webClient struct {
+	ws     *websocket.Conn
+	mu     sync.Mutex
+	msgs   []serverMessage
+	output []byte
+	screen map[[2]int]string
+	closed chan struct{}
+}
+
+func waitUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func startWebServer(t *testing.T, handler web.Handler, configure func(*web.Server)) (*web.Server, *httptest.Server) {
+	t.Helper()
+	srv := web.NewServer(handler)
+	if configure != nil {
+		configure(srv)
+	}
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		srv.Close()
+		ts.Close()
+	})
+	return srv, ts
+}
+
+func dialWeb(t *testing.T, ts *httptest.Server, query string) *webClient {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws?" + query
+	ws, err := websocket.Dial(url, "", ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &webClient{ws: ws, screen: make(map[[2]int]string), closed: make(chan struct{})}
+	t.Cleanup(func() { ws.Close() })
+	go c.read()
+	return c
+}
+
+func (c *webClient) read() {
+	defer close(c.closed)
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(c.ws, &data); err != nil {
+			return
+		}
+		c.mu.Lock()
+		var msg serverMessage
+		if json.Unmarshal(data, &msg) == nil && msg.Type != "" {
+			c.msgs = append(c.msgs, msg)
+			for _, cell := range msg.Cells {
+				c.screen[[2]int{cell.X, cell.Y}] = cell.Text
+			}
+		} else {
+			c.output = append(c.output, data...)
+		}
+		c.mu.Unlock()
+	}
+}
+
+func (c *webClient) send(t *testing.T, msg map[string]any) {
+	t.Helper()
+	if err := websocket.JSON.Send(c.ws, msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *webClient) row(y, width int) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var sb strings.Builder
+	for x := range width {
+		sb.WriteString(c.screen[[2]int{x, y}])
+	}
+	return sb.String()
+}
+
+func (c *webClient) screenContains(s string, rows, cols int) bool {
+	for y := range rows {
+		if strings.Contains(c.row(y, cols), s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *webClient) lastFrame() serverMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(c.msgs) - 1; i >= 0; i-- {
+		if c.msgs[i].Type == "frame" {
+			return c.msgs[i]
+		}
+	}
+	return serverMessage{}
+}
+
+func (c *webClient) hasMessage(typ string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range c.msgs {
+		if m.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *webClient) outputContains(s string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return strings.Contains(string(c.output), s)
+}
+
+func (c *webClient) waitClosed(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection was not closed")
+	}
+}
+
+type eventRecorder struct {
+	*widgets.Paragraph
+	mu     sync.Mutex
+	events []ui.Event
+}
+
+func (r *eventRecorder) HandleEvent(e ui.Event) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+	return e.ID != "q"
+}
+
+func (r *eventRecorder) has(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.events {
+		if e.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *eventRecorder) mouse(id string) (ui.Mouse, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.events {
+		if e.ID == id {
+			m, ok := e.Payload.(ui.Mouse)
+			return m, ok
+		}
+	}
+	return ui.Mouse{}, false
+}
+
+func recorderApp(text string, root **eventRecorder) web.Handler {
+	return func(s *web.Session) *ui.Application {
+		r := &eventRecorder{Paragraph: widgets.NewParagraphWithContext(s.UI)}
+		r.Text = text
+		if root != nil {
+			*root = r
+		}
+		app := ui.NewAppWithContext(s.UI)
+		app.SetRoot(r, true)
+		return app
+	}
+}
+
+func TestWebServerIndex(t *testing.T) {
+	_, ts := startWebServer(t, recorderApp("", nil), nil)
+	resp, err := http.Get(ts.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		t.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), "/ws?") {
+		t.Error("index page does not connect to the WebSocket endpoint")
+	}
+}
+
+func TestWebServerCellFrames(t *testing.T) {
+	var root *eventRecorder
+	_, ts := startWebServer(t, recorderApp("hello browser", &root), nil)
+	c := dialWeb(t, ts, "mode=cells&cols=40&rows=10")
+
+	waitUntil(t, "hello", func() bool { return c.hasMessage("hello") })
+	waitUntil(t, "initial frame", func() bool { return c.screenContains("hello browser", 10, 40) })
+	if f := c.lastFrame(); f.Cols != 40 || f.Rows != 10 {
+		t.Errorf("expected 40x10 frame, got %dx%d", f.Cols, f.Rows)
+	}
+
+	c.send(t, map[string]any{"type": "resize", "cols": 60, "rows": 20})
+	waitUntil(t, "resize", func() bool {
+		root.Lock()
+		defer root.Unlock()
+		return root.GetRect().Dx() == 60 && root.GetRect().Dy() == 20
+	})
+	waitUntil(t, "resized frame", func() bool { return c.lastFrame().Cols == 60 })
+
+	c.send(t, map[string]any{"type": "key", "key": "q"})
+	c.waitClosed(t)
+}
+
+func TestWebServerInputTranslation(t *testing.T) {
+	var root *eventRecorder
+	_, ts := startWebServer(t, recorderApp("input", &root), nil)
+	c := dialWeb(t, ts, "mode=cells&cols=40&rows=10")
+	waitUntil(t, "initial frame", func() bool { return c.screenContains("input", 10, 40) })
+
+	c.send(t, map[string]any{"type": "key", "key": "ArrowUp"})
+	c.send(t, map[string]any{"type": "key", "key": "Enter"})
+	c.send(t, map[string]any{"type": "key", "key": "c", "ctrl": true})
+	c.send(t, map[string]any{"type": "key", "key": "x", "alt": true})
+	c.send(t, map[string]any{"type": "key", "key": "Shift"})
+	c.send(t, map[string]any{"type": "mouse", "action": "press", "button": 0, "x": 3, "y": 4})
+	c.send(t, map[string]any{"type": "mouse", "action": "wheel", "delta": -1, "x": 1, "y": 1})
+
+	for _, id := range []string{"<Up>", "<Enter>", "<C-c>", "<M-x>", "<MouseLeft>", "<MouseWheelUp>"} {
+		waitUntil(t, id, func() bool { return root.has(id) })
+	}
+	if m, ok := root.mouse("<MouseLeft>"); !ok || m.X != 3 || m.Y != 4 {
+		t.Errorf("expected mouse press at 3,4, got %+v", m)
+	}
+}
+
+func TestWebServerANSI(t *testing.T) {
+	_, ts := startWebServer(t, recorderApp("ansi output", nil), nil)
+	c := dialWeb(t, ts, "mode=ansi&cols=40&rows=10")
+
+	waitUntil(t, "hello", func() bool { return c.hasMessage("hello") })
+	waitUntil(t, "terminal output", func() bool { return c.outputContains("ansi") })
+
+	c.send(t, map[string]any{"type": "data", "data": "q"})
+	c.waitClosed(t)
+}
+
+func TestWebServerMaxSessions(t *testing.T) {
+	_, ts := startWebServer(t, recorderApp("busy", nil), func(s *web.Server) {
+		s.MaxSessions = 1
+	})
+	first := dialWeb(t, ts, "mode=cells")
+	waitUntil(t, "first session", func() bool { return first.screenContains("busy", 24, 80) })
+
+	second := dialWeb(t, ts, "mode=cells")
+	second.waitClosed(t)
+	if !second.hasMessage("error") {
+		t.Error("expected second session to be rejected with an error")
+	}
+}
+
+func TestWebServerOrigin(t *testing.T) {
+	_, ts := startWebServer(t, recorderApp("", nil), nil)
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	if _, err := websocket.Dial(url, "", "http://evil.example"); err == nil {
+		t.Error("expected cross-origin handshake to be rejected")
+	}
+}
+
+func TestWebServerShutdown(t *testing.T) {
+	srv, ts := startWebServer(t, recorderApp("shutdown", nil), nil)
+	c := dialWeb(t, ts, "mode=cells")
+	waitUntil(t, "session start", func() bool { return c.screenContains("shutdown", 24, 80) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	c.waitClosed(t)
+	if srv.ActiveSessions() != 0 {
+		t.Errorf("expected no active sessions, got %d", srv.ActiveSessions())
+	}
+}
@@ -0,0 +1,126 @@
+package gotui_test
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+func wrapLines(lines [][]ui.Cell) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = ui.CellsToString(line)
+	}
+	return out
+}
+
+func TestBreakLinesWords(t *testing.T) {
+	cells := ui.StringToStyledCells("the quick brown fox", ui.StyleClear)
+	got := wrapLines(ui.BreakLines(cells, 10, ui.WrapOptions{}))
+	want := []string{"the quick", "brown fox"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+}
+
+func TestBreakLinesKeepsStyles(t *testing.T) {
+	// Runs of spaces used to shift every style after them.
+	cells := ui.ParseStyles("a   [bb](fg:red) [cc](fg:blue)", ui.StyleClear)
+	lines := ui.BreakLines(cells, 6, ui.WrapOptions{})
+	if got := wrapLines(lines); len(got) != 2 || got[0] != "a   bb" || got[1] != "cc" {
+		t.Fatalf("lines = %q", got)
+	}
+	for _, c := range lines[0][4:] {
+		if c.Style.Fg != ui.ColorRed {
+			t.Errorf("%q has %v, want red", c.Rune, c.Style.Fg)
+		}
+	}
+	for _, c := range lines[1] {
+		if c.Style.Fg != ui.ColorBlue {
+			t.Errorf("%q has %v, want blue", c.Rune, c.Style.Fg)
+		}
+	}
+}
+
+func TestBreakLinesWideAndIdeographs(t *testing.T) {
+	// Ideographs may break between any two, and each takes two columns.
+	cells := ui.StringToStyledCells("日本語のテキスト", ui.StyleClear)
+	for _, line := range ui.BreakLines(cells, 5, ui.WrapOptions{}) {
+		w := 0
+		for _, c := range line {
+			w += c.ColumnWidth()
+		}
+		if w > 5 {
+			t.Errorf("line %q is %d columns wide", ui.CellsToString(line), w)
+		}
+	}
+	if got := wrapLines(ui.BreakLines(cells, 5, ui.WrapOptions{})); got[0] != "日本" {
+		t.Errorf("first line = %q, want 日本", got[0])
+	}
+}
+
+func TestBreakLinesLongWords(t *testing.T) {
+	cells := ui.StringToStyledCells("go abcdefghij", ui.StyleClear)
+	if got := wrapLines(ui.BreakLines(cells, 4, ui.WrapOptions{})); strings.Join(got, "|") != "go|abcdefghij" {
+		t.Errorf("without BreakLongWords = %q", got)
+	}
+	got := wrapLines(ui.BreakLines(cells, 4, ui.WrapOptions{BreakLongWords: true}))
+	if strings.Join(got, "|") != "go|abcd|efgh|ij" {
+		t.Errorf("BreakLongWords = %q", got)
+	}
+	got = wrapLines(ui.BreakLines(cells, 4, ui.WrapOptions{BreakLongWords: true, Hyphen: true}))
+	if strings.Join(got, "|") != "go|abc-|def-|ghij" {
+		t.Errorf("Hyphen = %q", got)
+	}
+	// A wide cell forced onto a one column line leaves only the space after
+	// it, which must not become a line of its own.
+	cells = ui.StringToStyledCells("キスト abc", ui.StyleClear)
+	got = wrapLines(ui.BreakLines(cells, 1, ui.WrapOptions{BreakLongWords: true}))
+	if strings.Join(got, "|") != "キ|ス|ト|a|b|c" {
+		t.Errorf("wide cells on a narrow line = %q", got)
+	}
+}
+
+func TestBreakLinesHardBreaksAndEllipsis(t *testing.T) {
+	cells := ui.StringToStyledCells("one\n\ntwo three four\n", ui.StyleClear)
+	got := wrapLines(ui.BreakLines(cells, 9, ui.WrapOptions{}))
+	if strings.Join(got, "|") != "one||two three|four" {
+		t.Errorf("lines = %q", got)
+	}
+	got = wrapLines(ui.BreakLines(cells, 9, ui.WrapOptions{MaxLines: 3, Ellipsis: true}))
+	// "two three" fills the line, so the ellipsis takes its last column.
+	if strings.Join(got, "|") != "one||two thre…" {
+		t.Errorf("ellipsis = %q", got)
+	}
+}
+
+func TestTrimCellsWide(t *testing.T) {
+	cells := ui.ParseStyles("[ab](fg:red)日本", ui.StyleClear)
+	trimmed := ui.TrimCells(cells, 4)
+	if got := ui.CellsToString(trimmed); got != "ab…" {
+		t.Errorf("TrimCells = %q, want ab…", got)
+	}
+	if trimmed[1].Style.Fg != ui.ColorRed {
+		t.Errorf("style of %q = %v, want red", trimmed[1].Rune, trimmed[1].Style.Fg)
+	}
+}
+
+func TestParagraphWrapEllipsis(t *testing.T) {
+	p := widgets.NewParagraph()
+	p.Text = "one two three four five six"
+	p.WrapOptions = ui.WrapOptions{Ellipsis: true}
+	p.SetRect(0, 0, 11, 4)
+	buf := ui.NewBuffer(p.GetRect())
+	p.Draw(buf)
+
+	var row strings.Builder
+	for x := 1; x < 10; x++ {
+		row.WriteRune(buf.GetCell(image.Pt(x, 2)).Rune)
+	}
+	if got := strings.TrimRight(row.String(), " "); !strings.HasSuffix(got, "…") {
+		t.Errorf("last row = %q, want it to end with an ellipsis", got)
+	}
+}
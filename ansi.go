@@ -0,0 +1,212 @@
+package gotui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+const (
+	ansiEscape = '\x1b'
+	ansiBell   = '\a'
+	ansiCSI    = '\u009b'
+)
+
+// ParseANSI turns text with ANSI escape sequences, such as the output of
+// "git log --color", into cells. See ParseANSIWithStyle.
+func ParseANSI(s string) []Cell {
+	return ParseANSIWithStyle(s, StyleClear)
+}
+
+// ParseANSIWithStyle turns text with ANSI escape sequences into cells
+// styled from defaultStyle. SGR sequences set colors from the 16 and 256
+// color palettes or as RGB, and the bold, dim, italic, blink, reverse and
+// strike attributes; a reset goes back to defaultStyle. Other escape
+// sequences, such as cursor movement and OSC titles or links, are dropped,
+// as are control characters other than newline and tab.
+func ParseANSIWithStyle(s string, defaultStyle Style) []Cell {
+	cells := []Cell{}
+	style := defaultStyle
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ansiCSI:
+			i = ansiCSISequence(runes, i+1, &style, defaultStyle)
+		case r == ansiEscape && i+1 < len(runes):
+			i++
+			switch runes[i] {
+			case '[':
+				i = ansiCSISequence(runes, i+1, &style, defaultStyle)
+			case ']', 'P', '_', '^', 'X':
+				i = ansiStringEnd(runes, i+1)
+			case '(', ')', '*', '+', '#', '%':
+				i++
+			}
+		case r == '\n' || r == '\t':
+			cells = append(cells, Cell{Rune: r, Style: style})
+		case r < ' ' || r == 0x7f || r >= 0x80 && r < 0xa0:
+		default:
+			cells = append(cells, Cell{Rune: r, Style: style})
+		}
+	}
+	return groupGraphemes(cells)
+}
+
+// ansiCSISequence reads a control sequence whose parameters start at i,
+// applies it to style when it is SGR and returns the index of its last
+// rune.
+func ansiCSISequence(runes []rune, i int, style *Style, defaultStyle Style) int {
+	start := i
+	for i < len(runes) && runes[i] >= 0x20 && runes[i] <= 0x3f {
+		i++
+	}
+	params := string(runes[start:i])
+	for i < len(runes) && runes[i] >= 0x20 && runes[i] <= 0x2f {
+		i++
+	}
+	if i >= len(runes) {
+		return len(runes) - 1
+	}
+	if runes[i] == 'm' {
+		applySGR(params, style, defaultStyle)
+	}
+	return i
+}
+
+// ansiStringEnd returns the index of the last rune of an OSC, DCS or
+// similar string, which ends with BEL or ESC \.
+func ansiStringEnd(runes []rune, i int) int {
+	for ; i < len(runes); i++ {
+		if runes[i] == ansiBell {
+			return i
+		}
+		if runes[i] == ansiEscape && i+1 < len(runes) && runes[i+1] == '\\' {
+			return i + 1
+		}
+	}
+	return len(runes) - 1
+}
+
+var ansiModifiers = map[int]Modifier{
+	1: ModifierBold,
+	2: ModifierDim,
+	3: ModifierItalic,
+	5: ModifierBlink,
+	6: ModifierBlink,
+	7: ModifierReverse,
+	9: ModifierStrike,
+}
+
+// applySGR applies the parameters of a Select Graphic Rendition sequence.
+// Extended colors may be written with ';' or ':' between their parts.
+func applySGR(params string, style *Style, defaultStyle Style) {
+	if params == "" {
+		*style = defaultStyle
+		return
+	}
+	fields := strings.Split(params, ";")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.Split(fields[i], ":")
+		code, err := strconv.Atoi(parts[0])
+		if parts[0] == "" {
+			code, err = 0, nil
+		}
+		if err != nil {
+			continue
+		}
+		switch {
+		case code == 0:
+			*style = defaultStyle
+		case ansiModifiers[code] != 0:
+			style.Modifier |= ansiModifiers[code]
+		case code == 21 || code == 22:
+			style.Modifier &^= ModifierBold | ModifierDim
+		case code == 23:
+			style.Modifier &^= ModifierItalic
+		case code == 25:
+			style.Modifier &^= ModifierBlink
+		case code == 27:
+			style.Modifier &^= ModifierReverse
+		case code == 29:
+			style.Modifier &^= ModifierStrike
+		case code >= 30 && code <= 37:
+			style.Fg = tcell.PaletteColor(code - 30)
+		case code >= 90 && code <= 97:
+			style.Fg = tcell.PaletteColor(code - 90 + 8)
+		case code == 39:
+			style.Fg = defaultStyle.Fg
+		case code >= 40 && code <= 47:
+			style.Bg = tcell.PaletteColor(code - 40)
+		case code >= 100 && code <= 107:
+			style.Bg = tcell.PaletteColor(code - 100 + 8)
+		case code == 49:
+			style.Bg = defaultStyle.Bg
+		case code == 38 || code == 48:
+			colon := len(parts) > 1
+			args := fields[i+1:]
+			if colon {
+				args = parts[1:]
+			}
+			c, used, ok := ansiExtendedColor(args, colon)
+			if !colon {
+				i += used
+			}
+			if !ok {
+				continue
+			}
+			if code == 38 {
+				style.Fg = c
+			} else {
+				style.Bg = c
+			}
+		}
+	}
+}
+
+// ansiExtendedColor reads the arguments of SGR 38 and 48: 5;n for the 256
+// color palette or 2;r;g;b for RGB, separated by ':' when colon is set. It
+// returns how many arguments it used.
+func ansiExtendedColor(args []string, colon bool) (Color, int, bool) {
+	if len(args) == 0 {
+		return ColorClear, 0, false
+	}
+	num := func(s string) (int32, bool) {
+		n, err := strconv.Atoi(s)
+		return int32(n), err == nil && n >= 0 && n <= 255
+	}
+	switch args[0] {
+	case "5":
+		if len(args) < 2 {
+			return ColorClear, len(args), false
+		}
+		n, ok := num(args[1])
+		return tcell.PaletteColor(int(n)), 2, ok
+	case "2":
+		// The colon form may carry a color space id before r, g and b.
+		if colon && len(args) >= 5 {
+			args = append([]string{args[0]}, args[2:]...)
+		}
+		if len(args) < 4 {
+			return ColorClear, len(args), false
+		}
+		r, okR := num(args[1])
+		g, okG := num(args[2])
+		b, okB := num(args[3])
+		return NewRGBColor(r, g, b), 4, okR && okG && okB
+	}
+	return ColorClear, 1, false
+}
+
+// ParseText turns s into cells styled from defaultStyle, reading markup or
+// ANSI escape sequences as mode says.
+func ParseText(s string, defaultStyle Style, mode TextMode) []Cell {
+	switch mode {
+	case TextANSI:
+		return ParseANSIWithStyle(s, defaultStyle)
+	case TextPlain:
+		return StringToStyledCells(s, defaultStyle)
+	}
+	return ParseStyles(s, defaultStyle)
+}
@@ -1,6 +1,7 @@
 package gotui
 
 import (
+	"context"
 	"sync"
 )
 
@@ -11,13 +12,20 @@ type Application struct {
 	running bool
 	stop    chan struct{}
 	Backend *Backend
+	Context *Context
 	sync.Mutex
 }
 
 // NewApp returns a new Application.
 func NewApp() *Application {
+	return NewAppWithContext(DefaultContext)
+}
+
+// NewAppWithContext returns a new Application that renders to the backend of ctx.
+func NewAppWithContext(ctx *Context) *Application {
 	return &Application{
-		Backend: DefaultBackend,
+		Backend: ctx.Backend,
+		Context: ctx,
 	}
 }
 
@@ -39,6 +47,59 @@ func (a *Application) SetFocus(p Widget) {
 	a.focus = p
 }
 
+// Focused returns the focused widget, following FocusContainers down to the
+// innermost focused child.
+func (a *Application) Focused() Widget {
+	a.Lock()
+	w := a.focus
+	a.Unlock()
+	for w != nil {
+		c, ok := w.(FocusContainer)
+		if !ok {
+			break
+		}
+		child := c.FocusedChild()
+		if child == nil || child == w {
+			break
+		}
+		w = child
+	}
+	return w
+}
+
+// SetTheme replaces the theme of the application context and redraws if
+// the application is running. Widgets pick up the new theme except for the
+// values that were set by hand.
+func (a *Application) SetTheme(t RootTheme) {
+	a.Context.SetTheme(t)
+	a.Lock()
+	running := a.running
+	a.Unlock()
+	if running {
+		a.Redraw()
+	}
+}
+
+// SetStylesheet sets the stylesheet of the application context and redraws
+// if the application is running. The focused widget matches ":focused".
+func (a *Application) SetStylesheet(s *Stylesheet) {
+	a.Context.Stylesheet = s
+	a.Lock()
+	running := a.running
+	a.Unlock()
+	if running {
+		a.Redraw()
+	}
+}
+
+// render styles root with the context stylesheet and renders it.
+func (a *Application) render(root Widget) {
+	if a.Context != nil {
+		a.Context.Stylesheet.Apply(root, a.Focused())
+	}
+	a.Backend.Render(root)
+}
+
 // Stop stops the application.
 func (a *Application) Stop() {
 	a.Lock()
@@ -58,33 +119,51 @@ func (a *Application) getRoot() Widget {
 
 // Run runs the application.
 func (a *Application) Run() error {
-	if err := a.Backend.Init(); err != nil {
-		return err
+	return a.RunWithContext(context.Background())
+}
+
+// RunWithContext runs the application until it is stopped or ctx is done.
+// A Backend that has already been initialized is used as is.
+func (a *Application) RunWithContext(ctx context.Context) error {
+	if a.Backend.Screen == nil {
+		if err := a.Backend.Init(); err != nil {
+			return err
+		}
 	}
 	defer a.Backend.Close()
 
 	a.Lock()
 	a.running = true
 	a.stop = make(chan struct{}) // Recreate for each Run
+	stop := a.stop
 	a.Unlock()
 
 	// Size the root widget to terminal size after init
 	root := a.getRoot()
 	if root != nil {
 		w, h := a.Backend.TerminalDimensions()
-		// Lock during SetRect to prevent race with Draw
-		root.Lock()
-		root.SetRect(0, 0, w, h)
-		root.Unlock()
-		a.Backend.Render(root)
+		layoutRoot(root, w, h)
+		a.render(root)
 	}
 
-	uiEvents := a.Backend.PollEvents()
+	pollCtx, cancel := context.WithCancel(ctx)
+	uiEvents := a.Backend.PollEventsWithContext(pollCtx)
+	// Stop the poller before the deferred Close finalizes the screen.
+	defer func() {
+		cancel()
+		for range uiEvents {
+		}
+	}()
 	for {
 		select {
-		case <-a.stop:
+		case <-stop:
+			return nil
+		case <-ctx.Done():
 			return nil
-		case e := <-uiEvents:
+		case e, ok := <-uiEvents:
+			if !ok {
+				return nil
+			}
 			if a.handleEvent(e) {
 				return nil
 			}
@@ -108,21 +187,25 @@ func (a *Application) handleEvent(e Event) bool {
 		}
 	}
 
-	// Re-render with current terminal dimensions
+	a.Redraw()
+	return false
+}
+
+// Redraw sizes the root widget to the terminal and renders it. The
+// application redraws after every event; call Redraw after changing the
+// root or its widgets from another goroutine.
+func (a *Application) Redraw() {
 	root := a.getRoot()
-	if root != nil {
-		w, h := a.Backend.TerminalDimensions()
-		if w > 0 && h > 0 {
-			// Lock during SetRect to prevent race with Draw
-			root.Lock()
-			root.SetRect(0, 0, w, h)
-			root.Unlock()
-		}
-		// Clear before render to prevent stale content when widget content changes
-		a.Backend.Clear()
-		a.Backend.Render(root)
+	if root == nil {
+		return
 	}
-	return false
+	w, h := a.Backend.TerminalDimensions()
+	if w > 0 && h > 0 {
+		layoutRoot(root, w, h)
+	}
+	// Clear before render to prevent stale content when widget content changes
+	a.Backend.Clear()
+	a.render(root)
 }
 
 func (a *Application) handleResize(e Event) {
@@ -137,13 +220,22 @@ func (a *Application) handleResize(e Event) {
 		if h < 1 {
 			h = 1
 		}
-		// Lock during SetRect to prevent race with Draw
-		root.Lock()
-		root.SetRect(0, 0, w, h)
-		root.Unlock()
+		layoutRoot(root, w, h)
 		a.Backend.Clear() // Only clear on resize to prevent stale content at edges
-		a.Backend.Render(root)
+		a.render(root)
+	}
+}
+
+// layoutRoot sizes root to the terminal, letting a Responsive root pick the
+// layout for that size first.
+func layoutRoot(root Widget, w, h int) {
+	// Lock during SetRect to prevent race with Draw
+	root.Lock()
+	defer root.Unlock()
+	if r, ok := root.(Responsive); ok {
+		r.Respond(w, h)
 	}
+	root.SetRect(0, 0, w, h)
 }
 
 func (a *Application) dispatchKeyOrMouse(e Event) bool {
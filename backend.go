@@ -11,6 +11,8 @@ import (
 type Backend struct {
 	Screen         tcell.Screen
 	ScreenshotMode bool
+
+	mouseButtons tcell.ButtonMask
 }
 
 // DefaultBackend is the default backend.
@@ -179,9 +181,26 @@ func (t *ttyAdapter) Close() error {
 	return nil
 }
 
-func (t *ttyAdapter) Start() error { return nil }
-func (t *ttyAdapter) Stop() error  { return nil }
-func (t *ttyAdapter) Drain() error { return nil }
+func (t *ttyAdapter) Start() error {
+	if s, ok := t.rw.(interface{ Start() error }); ok {
+		return s.Start()
+	}
+	return nil
+}
+
+func (t *ttyAdapter) Stop() error {
+	if s, ok := t.rw.(interface{ Stop() error }); ok {
+		return s.Stop()
+	}
+	return nil
+}
+
+func (t *ttyAdapter) Drain() error {
+	if d, ok := t.rw.(interface{ Drain() error }); ok {
+		return d.Drain()
+	}
+	return nil
+}
 
 func (t *ttyAdapter) NotifyResize(ch chan<- bool) {
 	t.resizeCh = ch
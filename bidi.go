@@ -0,0 +1,369 @@
+package gotui
+
+import "golang.org/x/text/unicode/bidi"
+
+// TextDirection returns the direction of the first letter in cells that
+// has one, the way HTML resolves dir="auto", or DirectionLTR when there is
+// none.
+func TextDirection(cells []Cell) Direction {
+	for _, c := range cells {
+		switch bidiClass(c) {
+		case bidi.L:
+			return DirectionLTR
+		case bidi.R, bidi.AL:
+			return DirectionRTL
+		}
+	}
+	return DirectionLTR
+}
+
+// ResolveDirection returns dir, or the direction of cells when dir is
+// DirectionAuto.
+func ResolveDirection(dir Direction, cells []Cell) Direction {
+	if dir == DirectionAuto {
+		return TextDirection(cells)
+	}
+	return dir
+}
+
+// MirrorAlignment returns align for text in direction dir. In
+// right-to-left text AlignLeft and AlignRight swap, so that the default
+// alignment is to the start of the text.
+func MirrorAlignment(align Alignment, dir Direction) Alignment {
+	if dir != DirectionRTL {
+		return align
+	}
+	switch align {
+	case AlignLeft:
+		return AlignRight
+	case AlignRight:
+		return AlignLeft
+	}
+	return align
+}
+
+// HasRightToLeft reports whether cells hold right-to-left letters or
+// Arabic digits, which is when a left-to-right line needs reordering.
+func HasRightToLeft(cells []Cell) bool {
+	for _, c := range cells {
+		switch bidiClass(c) {
+		case bidi.R, bidi.AL, bidi.AN:
+			return true
+		}
+	}
+	return false
+}
+
+// ReorderCells returns a line of cells in the order they are shown from
+// left to right, as the Unicode Bidirectional Algorithm (UAX #9) orders a
+// line of text in direction dir. Brackets in right-to-left runs are
+// mirrored. Explicit embedding, override and isolate controls and the
+// pairing of brackets are not applied.
+func ReorderCells(cells []Cell, dir Direction) []Cell {
+	order, levels := bidiOrder(cells, ResolveDirection(dir, cells))
+	if order == nil {
+		return cells
+	}
+	out := make([]Cell, len(cells))
+	for v, i := range order {
+		out[v] = cells[i]
+		if levels[i]%2 == 1 && cells[i].Grapheme == "" {
+			out[v].Rune = mirrorRune(cells[i].Rune)
+		}
+	}
+	return out
+}
+
+// VisualOrder returns the index in cells of each cell of a line in the
+// order ReorderCells shows them.
+func VisualOrder(cells []Cell, dir Direction) []int {
+	order, _ := bidiOrder(cells, ResolveDirection(dir, cells))
+	if order == nil {
+		order = make([]int, len(cells))
+		for i := range order {
+			order[i] = i
+		}
+	}
+	return order
+}
+
+// CaretPosition returns where a caret before cells[caret] shows in the
+// line as ReorderCells orders it, counted in cells from the left. The
+// caret shows on the cell it is before. At the end of the line it is past
+// the last cell in the direction of the text: at len(cells) when that is
+// left to right and at -1 when it is right to left.
+func CaretPosition(cells []Cell, dir Direction, caret int) int {
+	dir = ResolveDirection(dir, cells)
+	return caretSlot(VisualOrder(cells, dir), caret, dir)
+}
+
+// MoveCaret moves a caret before cells[caret] by steps cells on screen, to
+// the right when steps is positive, and returns where it ends up in
+// logical order. In right-to-left text, moving right goes toward the
+// start of the text.
+func MoveCaret(cells []Cell, dir Direction, caret, steps int) int {
+	dir = ResolveDirection(dir, cells)
+	order := VisualOrder(cells, dir)
+	n := len(cells)
+	lo, hi := 0, n
+	if dir == DirectionRTL {
+		lo, hi = -1, n-1
+	}
+	v := max(lo, min(hi, caretSlot(order, caret, dir)+steps))
+	if v < 0 || v >= n {
+		return n
+	}
+	return order[v]
+}
+
+func caretSlot(order []int, caret int, dir Direction) int {
+	if caret < len(order) {
+		for v, i := range order {
+			if i == caret {
+				return v
+			}
+		}
+	}
+	if dir == DirectionRTL {
+		return -1
+	}
+	return len(order)
+}
+
+func bidiClass(c Cell) bidi.Class {
+	p, _ := bidi.LookupRune(c.Rune)
+	return p.Class()
+}
+
+// bidiOrder returns the visual order of cells and the embedding level of
+// each, or nil when the line is left to right throughout.
+func bidiOrder(cells []Cell, dir Direction) (order, levels []int) {
+	if dir != DirectionRTL && !HasRightToLeft(cells) {
+		return nil, nil
+	}
+	levels = bidiLevels(cells, dir == DirectionRTL)
+	order = make([]int, len(cells))
+	for i := range order {
+		order[i] = i
+	}
+	// L2: from the highest level down to the lowest odd one, reverse every
+	// run at that level or higher.
+	lv := append([]int(nil), levels...)
+	high, lowOdd := 0, 1<<30
+	for _, l := range lv {
+		high = max(high, l)
+		if l%2 == 1 {
+			lowOdd = min(lowOdd, l)
+		}
+	}
+	for level := high; level >= lowOdd; level-- {
+		for i := 0; i < len(lv); {
+			if lv[i] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(lv) && lv[j] >= level {
+				j++
+			}
+			reverseInts(order[i:j])
+			reverseInts(lv[i:j])
+			i = j
+		}
+	}
+	return order, levels
+}
+
+// bidiLevels resolves the embedding level of each cell by the weak, neutral
+// and implicit rules of UAX #9 for a single line at the base level.
+func bidiLevels(cells []Cell, rtl bool) []int {
+	n := len(cells)
+	base, sos := 0, bidi.L
+	if rtl {
+		base, sos = 1, bidi.R
+	}
+	orig := make([]bidi.Class, n)
+	types := make([]bidi.Class, n)
+	for i, c := range cells {
+		t := bidiClass(c)
+		switch t {
+		case bidi.LRE, bidi.RLE, bidi.LRO, bidi.RLO, bidi.PDF, bidi.Control:
+			t = bidi.BN
+		case bidi.LRI, bidi.RLI, bidi.FSI, bidi.PDI:
+			t = bidi.ON
+		}
+		orig[i], types[i] = t, t
+	}
+
+	// W1: marks, and the characters X9 leaves out, take the type before.
+	prev := sos
+	for i, t := range types {
+		if t == bidi.NSM || t == bidi.BN {
+			types[i] = prev
+		} else {
+			prev = t
+		}
+	}
+	// W2 and W3: European digits after Arabic letters are Arabic digits,
+	// and Arabic letters are right to left.
+	strong := sos
+	for i, t := range types {
+		switch t {
+		case bidi.L, bidi.R, bidi.AL:
+			strong = t
+		case bidi.EN:
+			if strong == bidi.AL {
+				types[i] = bidi.AN
+			}
+		}
+	}
+	for i, t := range types {
+		if t == bidi.AL {
+			types[i] = bidi.R
+		}
+	}
+	// W4: a single separator between two numbers of a kind joins them.
+	for i := 1; i+1 < n; i++ {
+		before, after := types[i-1], types[i+1]
+		switch {
+		case before != after:
+		case before == bidi.EN && (types[i] == bidi.ES || types[i] == bidi.CS):
+			types[i] = bidi.EN
+		case before == bidi.AN && types[i] == bidi.CS:
+			types[i] = bidi.AN
+		}
+	}
+	// W5 and W6: terminators next to European digits join them, and the
+	// separators and terminators left over are neutral.
+	for i := 0; i < n; {
+		if types[i] != bidi.ET {
+			i++
+			continue
+		}
+		j := i
+		for j < n && types[j] == bidi.ET {
+			j++
+		}
+		if (i > 0 && types[i-1] == bidi.EN) || (j < n && types[j] == bidi.EN) {
+			for k := i; k < j; k++ {
+				types[k] = bidi.EN
+			}
+		}
+		i = j
+	}
+	for i, t := range types {
+		switch t {
+		case bidi.ES, bidi.ET, bidi.CS:
+			types[i] = bidi.ON
+		}
+	}
+	// W7: European digits after left-to-right letters are left to right.
+	strong = sos
+	for i, t := range types {
+		switch t {
+		case bidi.L, bidi.R:
+			strong = t
+		case bidi.EN:
+			if strong == bidi.L {
+				types[i] = bidi.L
+			}
+		}
+	}
+	// N1 and N2: neutrals between text of one direction take it, and other
+	// neutrals take the base direction.
+	for i := 0; i < n; {
+		if !bidiNeutral(types[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < n && bidiNeutral(types[j]) {
+			j++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before = bidiStrong(types[i-1])
+		}
+		if j < n {
+			after = bidiStrong(types[j])
+		}
+		dir := sos
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			types[k] = dir
+		}
+		i = j
+	}
+	// I1 and I2.
+	levels := make([]int, n)
+	for i, t := range types {
+		levels[i] = base
+		switch {
+		case base == 0 && t == bidi.R:
+			levels[i] = 1
+		case base == 0 && (t == bidi.EN || t == bidi.AN):
+			levels[i] = 2
+		case base == 1 && (t == bidi.L || t == bidi.EN || t == bidi.AN):
+			levels[i] = 2
+		}
+	}
+	// L1: separators, and white space before them or at the end of the
+	// line, go back to the base level.
+	trailing := true
+	for i := n - 1; i >= 0; i-- {
+		switch orig[i] {
+		case bidi.S, bidi.B:
+			levels[i] = base
+			trailing = true
+		case bidi.WS, bidi.BN:
+			if trailing {
+				levels[i] = base
+			}
+		default:
+			trailing = false
+		}
+	}
+	return levels
+}
+
+func bidiNeutral(t bidi.Class) bool {
+	switch t {
+	case bidi.B, bidi.S, bidi.WS, bidi.ON:
+		return true
+	}
+	return false
+}
+
+// bidiStrong returns the direction a resolved type counts as next to
+// neutrals, where numbers count as right to left.
+func bidiStrong(t bidi.Class) bidi.Class {
+	if t == bidi.L {
+		return bidi.L
+	}
+	return bidi.R
+}
+
+var mirrors = map[rune]rune{
+	'<': '>', '>': '<', '«': '»', '»': '«', '‹': '›', '›': '‹',
+	'≤': '≥', '≥': '≤', '∈': '∋', '∋': '∈', '⊂': '⊃', '⊃': '⊂',
+}
+
+// mirrorRune returns the mirror image of r, such as ')' for '(', for
+// showing it in a right-to-left run.
+func mirrorRune(r rune) rune {
+	if p, _ := bidi.LookupRune(r); p.IsBracket() {
+		return []rune(bidi.ReverseString(string(r)))[0]
+	}
+	if m, ok := mirrors[r]; ok {
+		return m
+	}
+	return r
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,111 @@
+package gotui
+
+import (
+	"image"
+	"math"
+)
+
+// The transparency of a color is kept in the five bits between its RGB
+// value and the flags of tcell, so that every color made without an alpha
+// is opaque. That gives 32 steps of opacity.
+const (
+	alphaShift       = 24
+	alphaSteps       = 31
+	alphaMask  Color = alphaSteps << alphaShift
+)
+
+// BlendForeground and BlendBackground stand in for ColorClear when a
+// translucent color is blended over it, since only the terminal knows the
+// real default colors.
+var (
+	BlendForeground = ColorWhite
+	BlendBackground = ColorBlack
+)
+
+// WithAlpha returns c with an opacity from 0, fully transparent, to 1,
+// opaque, rounded to one of 32 steps. Cells whose style holds such a
+// color are blended over what is already in the buffer instead of
+// replacing it.
+func WithAlpha(c Color, alpha float64) Color {
+	alpha = math.Max(0, math.Min(1, alpha))
+	return OpaqueColor(c) | Color(math.Round((1-alpha)*alphaSteps))<<alphaShift
+}
+
+// NewColorRGBA returns an RGB color with an opacity from 0 to 1.
+func NewColorRGBA(r, g, b int32, alpha float64) Color {
+	return WithAlpha(NewColorRGB(r, g, b), alpha)
+}
+
+// ColorAlpha returns the opacity of c, from 0 to 1.
+func ColorAlpha(c Color) float64 {
+	return 1 - float64(c&alphaMask>>alphaShift)/alphaSteps
+}
+
+// OpaqueColor returns c without its alpha.
+func OpaqueColor(c Color) Color {
+	return c &^ alphaMask
+}
+
+// Translucent reports whether the foreground or background of s has an
+// alpha.
+func (s Style) Translucent() bool {
+	return s.Fg&alphaMask != 0 || s.Bg&alphaMask != 0
+}
+
+// BlendColor returns c drawn over under. A translucent ColorClear leaves
+// under as it is, and ColorClear under a translucent color counts as the
+// color given as clear.
+func BlendColor(c, under, clear Color) Color {
+	alpha := ColorAlpha(c)
+	c = OpaqueColor(c)
+	switch {
+	case alpha >= 1:
+		return c
+	case c == ColorClear:
+		return under
+	}
+	under = OpaqueColor(under)
+	if under == ColorClear {
+		under = clear
+	}
+	return MixColors(under, c, alpha, ColorSpaceRGB)
+}
+
+// Over returns c drawn over under: a translucent background is blended
+// with the one under it and a translucent foreground with the background
+// the cell ends up with.
+func (c Cell) Over(under Cell) Cell {
+	c.Style.Bg = BlendColor(c.Style.Bg, under.Style.Bg, BlendBackground)
+	if OpaqueColor(c.Style.Fg) == ColorClear {
+		c.Style.Fg = ColorClear
+	} else {
+		c.Style.Fg = BlendColor(c.Style.Fg, c.Style.Bg, BlendBackground)
+	}
+	return c
+}
+
+// Blend tints the cells in rect with style, keeping their text. The colors
+// of style are blended over the colors of each cell and its modifiers are
+// added. ColorClear leaves a color as it is, so a style with only a
+// translucent background tints a selection, and one with a translucent
+// black foreground and background dims what is under a popup or casts a
+// shadow.
+func (b *Buffer) Blend(style Style, rect image.Rectangle) {
+	rect = rect.Intersect(b.Clip())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			i, ok := b.index(image.Pt(x, y))
+			if !ok {
+				continue
+			}
+			c := &b.Cells[i]
+			if OpaqueColor(style.Fg) != ColorClear {
+				c.Style.Fg = BlendColor(style.Fg, c.Style.Fg, BlendForeground)
+			}
+			if OpaqueColor(style.Bg) != ColorClear {
+				c.Style.Bg = BlendColor(style.Bg, c.Style.Bg, BlendBackground)
+			}
+			c.Style.Modifier |= style.Modifier
+		}
+	}
+}
@@ -6,60 +6,57 @@ import (
 
 // NewBlock returns a new Block.
 func NewBlock() *Block {
+	return NewBlockWithContext(DefaultContext)
+}
+
+// NewBlockWithContext returns a new Block styled with the theme of ctx.
+func NewBlockWithContext(ctx *Context) *Block {
+	theme := ctx.CurrentTheme()
 	return &Block{
 		Border:               true,
-		BorderStyle:          Theme.Block.Border,
+		BorderStyle:          theme.Block.Border,
 		BorderLeft:           true,
 		BorderRight:          true,
 		BorderTop:            true,
 		BorderBottom:         true,
 		BorderCollapse:       false,
-		TitleStyle:           Theme.Block.Title,
+		TitleStyle:           theme.Block.Title,
 		TitleAlignment:       AlignLeft,
-		TitleBottomStyle:     Theme.Block.Title,
+		TitleBottomStyle:     theme.Block.Title,
 		TitleBottomAlignment: AlignLeft,
+		Context:              ctx,
+		themed:               theme,
+	}
+}
+
+// Theme returns the theme the block was last styled with, which is the
+// theme of its context once ThemeChanged has run, or the global Theme when
+// the block has no context.
+func (b *Block) Theme() *RootTheme {
+	if b.themed != nil {
+		return b.themed
+	}
+	if theme := b.Context.CurrentTheme(); theme != nil {
+		return theme
 	}
+	return &Theme
 }
 
 // drawBorder draws the border of the block to the buffer.
 func (b *Block) drawBorder(buf *Buffer) {
-	var gradientSchema []Color
-	if b.BorderGradient.Enabled {
-		if len(b.BorderGradient.Stops) > 0 {
-			if b.BorderGradient.Direction == GradientVertical {
-				gradientSchema = GenerateMultiGradient(b.Dy(), b.BorderGradient.Stops...)
-			} else {
-				gradientSchema = GenerateMultiGradient(b.Dx(), b.BorderGradient.Stops...)
-			}
-		} else {
-			if b.BorderGradient.Direction == GradientVertical {
-				gradientSchema = GenerateGradient(b.BorderGradient.Start, b.BorderGradient.End, b.Dy())
-			} else {
-				gradientSchema = GenerateGradient(b.BorderGradient.Start, b.BorderGradient.End, b.Dx())
-			}
-		}
-	}
 	drawRune := func(r rune, p image.Point) {
 		if b.BorderCollapse {
 			existing := buf.GetCell(p).Rune
 			r = ResolveBorderRune(existing, r)
 		}
 		style := b.BorderStyle
-		if b.BackgroundColor != ColorClear && b.FillBorder {
-			style.Bg = b.BackgroundColor
+		if b.FillBorder {
+			style.Bg = b.backgroundAt(p, style.Bg)
 		}
 		if b.BorderGradient.Enabled {
-			var idx int
-			if b.BorderGradient.Direction == GradientVertical {
-				idx = p.Y - b.Min.Y
-			} else {
-				idx = p.X - b.Min.X
-			}
-			if idx >= 0 && idx < len(gradientSchema) {
-				style.Fg = gradientSchema[idx]
-			}
+			style.Fg = b.BorderGradient.ColorAt(p, b.Rectangle)
 		}
-		buf.SetCell(Cell{r, style}, p)
+		buf.SetCell(Cell{Rune: r, Style: style}, p)
 	}
 	b.drawBorderLines(drawRune)
 	b.drawBorderCorners(drawRune)
@@ -181,6 +178,7 @@ func (b *Block) drawBorderCorners(drawRune func(rune, image.Point)) {
 
 // Draw draws the block to the buffer.
 func (b *Block) Draw(buf *Buffer) {
+	b.ThemeChanged()
 	b.drawBackground(buf)
 	if b.Border {
 		b.drawBorder(buf)
@@ -188,7 +186,7 @@ func (b *Block) Draw(buf *Buffer) {
 	b.drawTitles(buf)
 }
 func (b *Block) drawBackground(buf *Buffer) {
-	if b.BackgroundColor != ColorClear {
+	if b.BackgroundColor != ColorClear || b.BackgroundGradient.Enabled {
 		bgCell := NewCell(' ', NewStyle(ColorClear, b.BackgroundColor))
 		bgRect := b.Rectangle
 		if !b.FillBorder && b.Border {
@@ -205,11 +203,33 @@ func (b *Block) drawBackground(buf *Buffer) {
 				bgRect.Max.X--
 			}
 		}
-		if bgRect.Min.X < bgRect.Max.X && bgRect.Min.Y < bgRect.Max.Y {
+		if bgRect.Empty() {
+			return
+		}
+		if !b.BackgroundGradient.Enabled {
 			buf.Fill(bgCell, bgRect)
+			return
+		}
+		for y := bgRect.Min.Y; y < bgRect.Max.Y; y++ {
+			for x := bgRect.Min.X; x < bgRect.Max.X; x++ {
+				bgCell.Style.Bg = b.BackgroundGradient.ColorAt(image.Pt(x, y), b.Rectangle)
+				buf.SetCell(bgCell, image.Pt(x, y))
+			}
 		}
 	}
 }
+
+// backgroundAt returns the background of the block at p, or bg when it
+// has none.
+func (b *Block) backgroundAt(p image.Point, bg Color) Color {
+	switch {
+	case b.BackgroundGradient.Enabled:
+		return b.BackgroundGradient.ColorAt(p, b.Rectangle)
+	case b.BackgroundColor != ColorClear:
+		return b.BackgroundColor
+	}
+	return bg
+}
 func (b *Block) drawTitles(buf *Buffer) {
 	titleX := b.Min.X + 2
 	switch b.TitleAlignment {
@@ -299,3 +319,15 @@ func (b *Block) SetRect(x1, y1, x2, y2 int) {
 func (b *Block) GetRect() image.Rectangle {
 	return b.Rectangle
 }
+
+// Hidden reports whether the block is smaller than MinWidth x MinHeight, in
+// which case containers leave its area empty instead of drawing it.
+func (b *Block) Hidden() bool {
+	return b.Dx() < b.MinWidth || b.Dy() < b.MinHeight
+}
+
+// IsHidden reports whether d hides itself at its current size.
+func IsHidden(d Drawable) bool {
+	h, ok := d.(interface{ Hidden() bool })
+	return ok && h.Hidden()
+}
@@ -1,5 +1,7 @@
 package gotui
 
+import "image"
+
 const (
 	BorderTop    = 1
 	BorderRight  = 2
@@ -10,47 +12,158 @@ const (
 	CROSS = '┼'
 )
 
-var borderMap = map[rune]int{}
-var maskToRune = map[int]rune{}
+// Line weights of a box drawing arm, ordered so that stronger lines win.
+const (
+	armNone byte = iota
+	armLight
+	armHeavy
+	armDouble
+)
+
+// boxArms holds the weight of the top, right, bottom and left arm of a box
+// drawing rune.
+type boxArms [4]byte
+
+// boxRunes lists box drawing runes with their arms as top, right, bottom and
+// left weights: '.' none, 'l' light, 'h' heavy, 'd' double. Earlier entries
+// are preferred when several runes share the same arms.
+const boxRunes = "" +
+	"─.l.l━.h.h│l.l.┃h.h." +
+	"┌.ll.┍.hl.┎.lh.┏.hh.┐..ll┑..lh┒..hl┓..hh" +
+	"└ll..┕lh..┖hl..┗hh..┘l..l┙l..h┚h..l┛h..h" +
+	"├lll.┝lhl.┞hll.┟llh.┠hlh.┡hhl.┢lhh.┣hhh." +
+	"┤l.ll┥l.lh┦h.ll┧l.hl┨h.hl┩h.lh┪l.hh┫h.hh" +
+	"┬.lll┭.llh┮.hll┯.hlh┰.lhl┱.lhh┲.hhl┳.hhh" +
+	"┴ll.l┵ll.h┶lh.l┷lh.h┸hl.l┹hl.h┺hh.l┻hh.h" +
+	"┼llll┽lllh┾lhll┿lhlh╀hlll╁llhl╂hlhl╃hllh" +
+	"╄hhll╅llhh╆lhhl╇hhlh╈lhhh╉hlhh╊hhhl╋hhhh" +
+	"═.d.d║d.d.╒.dl.╓.ld.╔.dd.╕..ld╖..dl╗..dd" +
+	"╘ld..╙dl..╚dd..╛l..d╜d..l╝d..d╞ldl.╟dld." +
+	"╠ddd.╡l.ld╢d.dl╣d.dd╤.dld╥.ldl╦.ddd╧ld.d" +
+	"╨dl.l╩dd.d╪ldld╫dldl╬dddd" +
+	"╭.ll.╮..ll╯l..l╰ll.." +
+	"╴...l╵l...╶.l..╷..l.╸...h╹h...╺.h..╻..h." +
+	"╼.l.h╽l.h.╾.h.l╿h.l."
+
+var armsByRune = map[rune]boxArms{}
+var runeByArms = map[boxArms]rune{}
 
 func init() {
-	borderMap = map[rune]int{
-		HORIZONTAL_LINE: BorderLeft | BorderRight,
-		VERTICAL_LINE:   BorderTop | BorderBottom,
-		TOP_LEFT:        BorderRight | BorderBottom,
-		TOP_RIGHT:       BorderLeft | BorderBottom,
-		BOTTOM_LEFT:     BorderRight | BorderTop,
-		BOTTOM_RIGHT:    BorderLeft | BorderTop,
-		VERTICAL_RIGHT:  BorderTop | BorderBottom | BorderRight,
-		VERTICAL_LEFT:   BorderTop | BorderBottom | BorderLeft,
-		HORIZONTAL_DOWN: BorderLeft | BorderRight | BorderBottom,
-		HORIZONTAL_UP:   BorderLeft | BorderRight | BorderTop,
-		CROSS:           BorderTop | BorderBottom | BorderLeft | BorderRight,
-		' ':             0,
-	}
-	maskToRune = map[int]rune{
-		(BorderLeft | BorderRight):                            HORIZONTAL_LINE,
-		(BorderTop | BorderBottom):                            VERTICAL_LINE,
-		(BorderRight | BorderBottom):                          TOP_LEFT,
-		(BorderLeft | BorderBottom):                           TOP_RIGHT,
-		(BorderRight | BorderTop):                             BOTTOM_LEFT,
-		(BorderLeft | BorderTop):                              BOTTOM_RIGHT,
-		(BorderTop | BorderBottom | BorderRight):              VERTICAL_RIGHT,
-		(BorderTop | BorderBottom | BorderLeft):               VERTICAL_LEFT,
-		(BorderLeft | BorderRight | BorderBottom):             HORIZONTAL_DOWN,
-		(BorderLeft | BorderRight | BorderTop):                HORIZONTAL_UP,
-		(BorderTop | BorderBottom | BorderLeft | BorderRight): CROSS,
-		0: ' ',
+	weights := map[rune]byte{'.': armNone, 'l': armLight, 'h': armHeavy, 'd': armDouble}
+	entry := []rune(boxRunes)
+	for i := 0; i+4 < len(entry); i += 5 {
+		var arms boxArms
+		for j := range arms {
+			arms[j] = weights[entry[i+1+j]]
+		}
+		armsByRune[entry[i]] = arms
+		if _, ok := runeByArms[arms]; !ok {
+			runeByArms[arms] = entry[i]
+		}
 	}
+	armsByRune[' '] = boxArms{}
 }
+
+// ResolveBorderRune returns the rune that joins the box drawing rune newRune
+// drawn over existing, e.g. '┐' over '┌' becomes '┬'. Light, heavy, double
+// and rounded lines are supported; where lines of different weight meet, the
+// stronger one wins. Runes that are not box drawing characters are returned
+// unchanged.
 func ResolveBorderRune(existing, newRune rune) rune {
-	m1, ok1 := borderMap[existing]
-	m2, ok2 := borderMap[newRune]
-	if ok1 && ok2 {
-		combined := m1 | m2
-		if r, found := maskToRune[combined]; found {
-			return r
-		}
+	a, ok1 := armsByRune[existing]
+	b, ok2 := armsByRune[newRune]
+	if !ok1 || !ok2 {
+		return newRune
+	}
+	var arms boxArms
+	for i := range arms {
+		arms[i] = max(a[i], b[i])
+	}
+	if arms == b {
+		return newRune
+	}
+	if arms == a {
+		return existing
+	}
+	if r, ok := lookupArms(arms); ok {
+		return r
 	}
 	return newRune
 }
+
+// lookupArms finds a rune for arms, simplifying line weights that have no
+// glyph: heavy lines next to double ones become light, an axis with a double
+// arm becomes double throughout and, as a last resort, everything is light.
+func lookupArms(arms boxArms) (rune, bool) {
+	if r, ok := runeByArms[arms]; ok {
+		return r, true
+	}
+	arms = replaceArms(arms, armHeavy, armLight)
+	if r, ok := runeByArms[arms]; ok {
+		return r, true
+	}
+	for _, axis := range [2][2]int{{0, 2}, {1, 3}} {
+		if arms[axis[0]] == armDouble || arms[axis[1]] == armDouble {
+			for _, i := range axis {
+				if arms[i] != armNone {
+					arms[i] = armDouble
+				}
+			}
+		}
+	}
+	if r, ok := runeByArms[arms]; ok {
+		return r, true
+	}
+	r, ok := runeByArms[replaceArms(arms, armDouble, armLight)]
+	return r, ok
+}
+
+func replaceArms(arms boxArms, from, to byte) boxArms {
+	for i := range arms {
+		if arms[i] == from {
+			arms[i] = to
+		}
+	}
+	return arms
+}
+
+// DrawMerged draws d and joins the borders on its outline with the borders
+// already in buf, so that neighbouring widgets can share a single line.
+func DrawMerged(buf *Buffer, d Drawable) {
+	if IsHidden(d) {
+		return
+	}
+	r := d.GetRect()
+	var before []rune
+	forEachEdge(r, func(p image.Point) {
+		before = append(before, buf.GetCell(p).Rune)
+	})
+	d.Lock()
+	buf.PushClip(r)
+	d.Draw(buf)
+	buf.PopClip()
+	d.Unlock()
+	i := 0
+	forEachEdge(r, func(p image.Point) {
+		c := buf.GetCell(p)
+		c.Rune = ResolveBorderRune(before[i], c.Rune)
+		buf.SetCell(c, p)
+		i++
+	})
+}
+
+// forEachEdge calls fn for every point on the outline of r.
+func forEachEdge(r image.Rectangle, fn func(image.Point)) {
+	for x := r.Min.X; x < r.Max.X; x++ {
+		fn(image.Pt(x, r.Min.Y))
+		if r.Dy() > 1 {
+			fn(image.Pt(x, r.Max.Y-1))
+		}
+	}
+	for y := r.Min.Y + 1; y < r.Max.Y-1; y++ {
+		fn(image.Pt(r.Min.X, y))
+		if r.Dx() > 1 {
+			fn(image.Pt(r.Max.X-1, y))
+		}
+	}
+}
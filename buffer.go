@@ -2,8 +2,10 @@ package gotui
 
 import (
 	"image"
+	"unicode/utf8"
 
 	rw "github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
 )
 
 func NewCell(rune rune, args ...any) Cell {
@@ -17,6 +19,34 @@ func NewCell(rune rune, args ...any) Cell {
 	}
 }
 
+// NewGraphemeCell returns a cell holding the grapheme cluster g.
+func NewGraphemeCell(g string, style Style) Cell {
+	r, size := utf8.DecodeRuneInString(g)
+	if size == len(g) {
+		return Cell{Rune: r, Style: style, Width: rw.RuneWidth(r)}
+	}
+	return Cell{Rune: r, Style: style, Grapheme: g, Width: uniseg.StringWidth(g)}
+}
+
+// Text returns the text the cell shows.
+func (c Cell) Text() string {
+	if c.Grapheme != "" {
+		return c.Grapheme
+	}
+	return string(c.Rune)
+}
+
+// ColumnWidth returns the number of columns the cell takes.
+func (c Cell) ColumnWidth() int {
+	if c.Width > 0 {
+		return c.Width
+	}
+	if c.Grapheme != "" {
+		return uniseg.StringWidth(c.Grapheme)
+	}
+	return rw.RuneWidth(c.Rune)
+}
+
 // NewBuffer returns a new Buffer.
 func NewBuffer(r image.Rectangle) *Buffer {
 	buf := &Buffer{
@@ -32,46 +62,145 @@ func (b *Buffer) GetCell(p image.Point) Cell {
 	if !p.In(b.Rectangle) {
 		return CellClear
 	}
-	idx := (p.Y-b.Min.Y)*b.Dx() + (p.X - b.Min.X)
-	if idx >= 0 && idx < len(b.Cells) {
+	if idx, ok := b.index(p); ok {
 		return b.Cells[idx]
 	}
 	return CellClear
 }
 
-// SetCell sets the cell at the given point.
+// SetCell sets the cell at the given point. A wide cell also claims the
+// column after it and is replaced by a space when that column is clipped.
+// A wide cell that is partly overwritten is blanked. A cell whose style is
+// translucent is blended over the cell it replaces.
 func (b *Buffer) SetCell(c Cell, p image.Point) {
-	if !p.In(b.Rectangle) {
+	clip := b.Clip()
+	if !p.In(clip) {
+		return
+	}
+	idx, ok := b.index(p)
+	if !ok {
 		return
 	}
-	idx := (p.Y-b.Min.Y)*b.Dx() + (p.X - b.Min.X)
-	if idx >= 0 && idx < len(b.Cells) {
-		b.Cells[idx] = c
+	right := p.Add(image.Pt(1, 0))
+	wide := c.ColumnWidth() > 1
+	if wide && !right.In(clip) {
+		c = Cell{Rune: ' ', Style: c.Style}
+		wide = false
 	}
+	old := b.Cells[idx]
+	if c.Style.Translucent() {
+		c = c.Over(old)
+	}
+	b.Cells[idx] = c
+	if left := p.Add(image.Pt(-1, 0)); old.continuation() && !c.continuation() && left.In(clip) {
+		if i, ok := b.index(left); ok && b.Cells[i].ColumnWidth() > 1 {
+			b.Cells[i] = Cell{Rune: ' ', Style: b.Cells[i].Style}
+		}
+	}
+	i, ok := b.index(right)
+	switch {
+	case !ok || !right.In(clip):
+	case wide:
+		b.Cells[i] = Cell{Style: c.Style}
+	case old.ColumnWidth() > 1 && b.Cells[i].continuation():
+		b.Cells[i] = Cell{Rune: ' ', Style: b.Cells[i].Style}
+	}
+}
+
+func (c Cell) continuation() bool {
+	return c.Rune == 0 && c.Grapheme == ""
 }
 
-// Fill fills the buffer with the given cell.
+// Fill fills the buffer with the given cell, blending it over the cells
+// there when its style is translucent.
 func (b *Buffer) Fill(c Cell, rect image.Rectangle) {
-	rect = rect.Intersect(b.Rectangle)
+	rect = rect.Intersect(b.Clip())
 	if rect.Empty() {
 		return
 	}
-	width := b.Dx()
+	translucent := c.Style.Translucent()
 	for y := rect.Min.Y; y < rect.Max.Y; y++ {
-		rowStart := (y - b.Min.Y) * width
-		startIdx := rowStart + (rect.Min.X - b.Min.X)
-		endIdx := rowStart + (rect.Max.X - b.Min.X)
+		startIdx, ok := b.index(image.Pt(rect.Min.X, y))
+		if !ok {
+			continue
+		}
+		endIdx := min(startIdx+rect.Dx(), len(b.Cells))
 		for i := startIdx; i < endIdx; i++ {
-			b.Cells[i] = c
+			if translucent {
+				b.Cells[i] = c.Over(b.Cells[i])
+			} else {
+				b.Cells[i] = c
+			}
 		}
 	}
 }
 
-// SetString writes a string to the buffer at the given point.
+// Clip returns the rectangle writes are currently limited to.
+func (b *Buffer) Clip() image.Rectangle {
+	if n := len(b.clips); n > 0 {
+		return b.clips[n-1]
+	}
+	return b.Rectangle
+}
+
+// PushClip limits writes to r until the matching PopClip. Clips nest, so
+// the new clip is r within the current one.
+func (b *Buffer) PushClip(r image.Rectangle) {
+	b.clips = append(b.clips, r.Intersect(b.Clip()))
+}
+
+// PopClip removes the clip set by the last PushClip.
+func (b *Buffer) PopClip() {
+	if n := len(b.clips); n > 0 {
+		b.clips = b.clips[:n-1]
+	}
+}
+
+// SubBuffer returns a view of rect in which rect.Min is the origin (0, 0).
+// The view writes to the cells of b and cannot write outside rect or the
+// current clip of b.
+func (b *Buffer) SubBuffer(rect image.Rectangle) *Buffer {
+	sub := &Buffer{
+		Rectangle: image.Rect(0, 0, rect.Dx(), rect.Dy()),
+		Cells:     b.Cells,
+		area:      b.cellArea(),
+		origin:    b.origin.Add(rect.Min),
+	}
+	if clip := rect.Intersect(b.Clip()); clip != rect {
+		sub.clips = []image.Rectangle{clip.Sub(rect.Min)}
+	}
+	return sub
+}
+
+// cellArea is the rectangle Cells covers, in the coordinates of the buffer
+// that owns them.
+func (b *Buffer) cellArea() image.Rectangle {
+	if b.area.Empty() {
+		return b.Rectangle
+	}
+	return b.area
+}
+
+func (b *Buffer) index(p image.Point) (int, bool) {
+	area := b.cellArea()
+	p = p.Add(b.origin)
+	if !p.In(area) {
+		return 0, false
+	}
+	idx := (p.Y-area.Min.Y)*area.Dx() + (p.X - area.Min.X)
+	return idx, idx >= 0 && idx < len(b.Cells)
+}
+
+// SetString writes a string to the buffer at the given point, one grapheme
+// cluster per cell.
 func (b *Buffer) SetString(s string, style Style, p image.Point) {
 	x := 0
-	for _, char := range s {
-		b.SetCell(Cell{char, style}, image.Pt(p.X+x, p.Y))
-		x += rw.RuneWidth(char)
+	state := -1
+	for s != "" {
+		var g string
+		g, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		c := NewGraphemeCell(g, style)
+		b.SetCell(c, image.Pt(p.X+x, p.Y))
+		x += c.ColumnWidth()
 	}
 }
@@ -8,8 +8,13 @@ import (
 
 // NewCanvas returns a new Canvas.
 func NewCanvas() *Canvas {
+	return NewCanvasWithContext(DefaultContext)
+}
+
+// NewCanvasWithContext returns a new Canvas bound to ctx.
+func NewCanvasWithContext(ctx *Context) *Canvas {
 	return &Canvas{
-		Block:  *NewBlock(),
+		Block:  *NewBlockWithContext(ctx),
 		Canvas: *drawille.NewCanvas(),
 	}
 }
@@ -35,8 +40,8 @@ func (c *Canvas) Draw(buf *Buffer) {
 				col = ColorWhite
 			}
 			convertedCell := Cell{
-				cell.Rune,
-				Style{
+				Rune: cell.Rune,
+				Style: Style{
 					col,
 					ColorClear,
 					ModifierClear,
@@ -0,0 +1,150 @@
+package gotui
+
+import "math"
+
+// MixColors returns the color at t, from 0 to 1, on the way from c1 to
+// c2, blended in the given color space.
+func MixColors(c1, c2 Color, t float64, space ColorSpace) Color {
+	t = math.Max(0, math.Min(1, t))
+	r1, g1, b1 := c1.RGB()
+	r2, g2, b2 := c2.RGB()
+	switch space {
+	case ColorSpaceOKLab:
+		l1, a1, bb1 := rgbToOKLab(r1, g1, b1)
+		l2, a2, bb2 := rgbToOKLab(r2, g2, b2)
+		return okLabToColor(lerp(l1, l2, t), lerp(a1, a2, t), lerp(bb1, bb2, t))
+	case ColorSpaceOKLCH:
+		l1, a1, bb1 := rgbToOKLab(r1, g1, b1)
+		l2, a2, bb2 := rgbToOKLab(r2, g2, b2)
+		ch1, h1 := math.Hypot(a1, bb1), math.Atan2(bb1, a1)
+		ch2, h2 := math.Hypot(a2, bb2), math.Atan2(bb2, a2)
+		h := mixHue(h1, h2, ch1, ch2, t, math.Pi)
+		ch := lerp(ch1, ch2, t)
+		return okLabToColor(lerp(l1, l2, t), ch*math.Cos(h), ch*math.Sin(h))
+	case ColorSpaceHSL:
+		h1, s1, li1 := rgbToHSL(r1, g1, b1)
+		h2, s2, li2 := rgbToHSL(r2, g2, b2)
+		return hslToColor(mixHue(h1, h2, s1, s2, t, 180), lerp(s1, s2, t), lerp(li1, li2, t))
+	}
+	return NewRGBColor(
+		int32(float64(r1)+t*float64(r2-r1)),
+		int32(float64(g1)+t*float64(g2-g1)),
+		int32(float64(b1)+t*float64(b2-b1)),
+	)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + t*(b-a)
+}
+
+// mixHue blends two hues the short way around the circle, where half is
+// half a turn. A gray, with no saturation, takes the hue of the other color.
+func mixHue(h1, h2, sat1, sat2, t, half float64) float64 {
+	const gray = 1e-4
+	switch {
+	case sat1 < gray:
+		h1 = h2
+	case sat2 < gray:
+		h2 = h1
+	}
+	d := h2 - h1
+	if d > half {
+		d -= 2 * half
+	} else if d < -half {
+		d += 2 * half
+	}
+	return h1 + t*d
+}
+
+func toLinear(c int32) float64 {
+	v := float64(c) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func fromLinear(v float64) int32 {
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return int32(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// rgbToOKLab converts sRGB to the OKLab space of Björn Ottosson, in which
+// equal steps look like equal changes of color.
+func rgbToOKLab(r, g, b int32) (l, a, bb float64) {
+	lr, lg, lb := toLinear(r), toLinear(g), toLinear(b)
+	x := math.Cbrt(0.4122214708*lr + 0.5363325363*lg + 0.0514459929*lb)
+	y := math.Cbrt(0.2119034982*lr + 0.6806995451*lg + 0.1073969566*lb)
+	z := math.Cbrt(0.0883024619*lr + 0.2817188376*lg + 0.6299787005*lb)
+	return 0.2104542553*x + 0.7936177850*y - 0.0040720468*z,
+		1.9779984951*x - 2.4285922050*y + 0.4505937099*z,
+		0.0259040371*x + 0.7827717662*y - 0.8086757660*z
+}
+
+func okLabToColor(l, a, bb float64) Color {
+	x := l + 0.3963377774*a + 0.2158037573*bb
+	y := l - 0.1055613458*a - 0.0638541728*bb
+	z := l - 0.0894841775*a - 1.2914855480*bb
+	x, y, z = x*x*x, y*y*y, z*z*z
+	return NewRGBColor(
+		fromLinear(4.0767416621*x-3.3077115913*y+0.2309699292*z),
+		fromLinear(-1.2684380046*x+2.6097574011*y-0.3413193965*z),
+		fromLinear(-0.0041960863*x-0.7034186147*y+1.7076147010*z),
+	)
+}
+
+// rgbToHSL returns the hue in degrees and the saturation and lightness
+// from 0 to 1.
+func rgbToHSL(r, g, b int32) (h, s, l float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	hi, lo := math.Max(rf, math.Max(gf, bf)), math.Min(rf, math.Min(gf, bf))
+	l = (hi + lo) / 2
+	d := hi - lo
+	if d == 0 {
+		return 0, 0, l
+	}
+	s = d / (1 - math.Abs(2*l-1))
+	switch hi {
+	case rf:
+		h = math.Mod((gf-bf)/d, 6)
+	case gf:
+		h = (bf-rf)/d + 2
+	default:
+		h = (rf-gf)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+func hslToColor(h, s, l float64) Color {
+	h = math.Mod(h+360, 360)
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = c, x
+	case h < 120:
+		r, g = x, c
+	case h < 180:
+		g, b = c, x
+	case h < 240:
+		g, b = x, c
+	case h < 300:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	m := l - c/2
+	to8 := func(v float64) int32 {
+		return int32(math.Round(math.Max(0, math.Min(1, v+m)) * 255))
+	}
+	return NewRGBColor(to8(r), to8(g), to8(b))
+}
@@ -20,56 +20,10 @@ var StandardStyles = []Style{
 	NewStyle(ColorLightCyan),
 	NewStyle(ColorWhite),
 }
-var Theme = RootTheme{
-	Default: NewStyle(ColorWhite),
-	Block: BlockTheme{
-		Title:  NewStyle(ColorWhite),
-		Border: NewStyle(ColorWhite),
-	},
-	BarChart: BarChartTheme{
-		Bars:   StandardColors,
-		Nums:   StandardStyles,
-		Labels: StandardStyles,
-	},
-	Paragraph: ParagraphTheme{
-		Text: NewStyle(ColorWhite),
-	},
-	PieChart: PieChartTheme{
-		Slices: StandardColors,
-	},
-	List: ListTheme{
-		Text: NewStyle(ColorWhite),
-	},
-	Tree: TreeTheme{
-		Text:      NewStyle(ColorWhite),
-		Collapsed: COLLAPSED,
-		Expanded:  EXPANDED,
-	},
-	StackedBarChart: StackedBarChartTheme{
-		Bars:   StandardColors,
-		Nums:   StandardStyles,
-		Labels: StandardStyles,
-	},
-	Gauge: GaugeTheme{
-		Bar:   ColorWhite,
-		Label: NewStyle(ColorWhite),
-	},
-	Sparkline: SparklineTheme{
-		Title: NewStyle(ColorWhite),
-		Line:  ColorWhite,
-	},
-	Plot: PlotTheme{
-		Lines: StandardColors,
-		Axes:  ColorWhite,
-	},
-	Table: TableTheme{
-		Text: NewStyle(ColorWhite),
-	},
-	Tab: TabTheme{
-		Active:   NewStyle(ColorRed),
-		Inactive: NewStyle(ColorWhite),
-	},
-}
+
+// Theme is the theme of DefaultContext.
+var Theme = DefaultTheme()
+
 var StyleClear = Style{
 	Fg:       ColorClear,
 	Bg:       ColorClear,
@@ -163,6 +117,61 @@ const (
 	col gridItemType = 0
 	row gridItemType = 1
 )
+const (
+	constraintLength constraintKind = iota
+	constraintPercentage
+	constraintRatio
+	constraintMin
+	constraintMax
+	constraintFill
+)
+const (
+	LayoutHorizontal LayoutDirection = iota
+	LayoutVertical
+)
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// SizeFill, given to Place as a width or height, takes the whole area on
+// that axis.
+const SizeFill = int(^uint(0) >> 1)
+
+const (
+	// TextMarkup reads markup such as [text](fg:red), see ParseStyles.
+	TextMarkup TextMode = iota
+	// TextANSI reads ANSI escape sequences, see ParseANSI.
+	TextANSI
+	// TextPlain shows the text as it is.
+	TextPlain
+)
+
+const (
+	TokenText TokenKind = iota
+	TokenKeyword
+	TokenType
+	TokenBuiltin
+	TokenVariable
+	TokenString
+	TokenNumber
+	TokenComment
+	TokenOperator
+	// TokenKey is a key of a JSON object or a YAML mapping.
+	TokenKey
+	// TokenInserted, TokenDeleted and TokenHeading are the added and removed
+	// lines of a diff, and its file and hunk headers.
+	TokenInserted
+	TokenDeleted
+	TokenHeading
+)
 
 type BorderType int
 
@@ -175,4 +184,35 @@ const (
 const (
 	GradientHorizontal int = 0
 	GradientVertical   int = 1
+	// GradientDiagonal runs from the top left corner to the bottom right,
+	// and GradientAntiDiagonal from the bottom left to the top right.
+	GradientDiagonal     int = 2
+	GradientAntiDiagonal int = 3
+	// GradientRadial runs from the center to the edges.
+	GradientRadial int = 4
+	// GradientConic turns clockwise around the center, starting at the top.
+	GradientConic int = 5
+	// GradientValue colors by the value a widget shows instead of by
+	// position, such as the percent of a Gauge or the height of a bar.
+	GradientValue int = 6
+)
+
+const (
+	// ColorSpaceRGB blends the red, green and blue channels as they are.
+	ColorSpaceRGB ColorSpace = iota
+	// ColorSpaceOKLab blends in OKLab, which keeps the midpoints bright
+	// and even.
+	ColorSpaceOKLab
+	// ColorSpaceOKLCH blends lightness, chroma and hue in OKLab, going
+	// around the hue circle the short way.
+	ColorSpaceOKLCH
+	// ColorSpaceHSL blends hue, saturation and lightness.
+	ColorSpaceHSL
+)
+
+const (
+	// DirectionAuto takes the direction of the first letter that has one.
+	DirectionAuto Direction = iota
+	DirectionLTR
+	DirectionRTL
 )
@@ -0,0 +1,68 @@
+package gotui
+
+import (
+	"context"
+	"time"
+)
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is a Clock backed by time.Now.
+var SystemClock Clock = systemClock{}
+
+// DefaultContext binds DefaultBackend, Theme and SystemClock. It backs the
+// package level helpers such as Render and the plain widget constructors.
+var DefaultContext = &Context{
+	Backend: DefaultBackend,
+	Theme:   &Theme,
+	Clock:   SystemClock,
+}
+
+// NewContext returns a Context for backend with its own copy of the default theme.
+func NewContext(backend *Backend) *Context {
+	theme := DefaultTheme()
+	return &Context{
+		Backend: backend,
+		Theme:   &theme,
+		Clock:   SystemClock,
+	}
+}
+
+// Now returns the current time according to the context clock.
+func (c *Context) Now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
+
+// Render renders the given drawables to the context backend, styling them
+// with the context stylesheet first.
+func (c *Context) Render(items ...Drawable) {
+	for _, item := range items {
+		c.Stylesheet.Apply(item, nil)
+	}
+	c.Backend.Render(items...)
+}
+
+// Clear clears the context backend.
+func (c *Context) Clear() {
+	c.Backend.Clear()
+}
+
+// TerminalDimensions returns the size of the context backend.
+func (c *Context) TerminalDimensions() (int, int) {
+	return c.Backend.TerminalDimensions()
+}
+
+// PollEvents polls for events on the context backend.
+func (c *Context) PollEvents() <-chan Event {
+	return c.Backend.PollEvents()
+}
+
+// PollEventsWithContext polls for events on the context backend until ctx is done.
+func (c *Context) PollEventsWithContext(ctx context.Context) <-chan Event {
+	return c.Backend.PollEventsWithContext(ctx)
+}
@@ -34,7 +34,7 @@ func (b *Backend) PollEvents() <-chan Event {
 			case *tcell.EventKey:
 				ch <- convertTcellKeyEvent(ev)
 			case *tcell.EventMouse:
-				ch <- convertTcellMouseEvent(ev)
+				ch <- convertTcellMouseEvent(ev, &b.mouseButtons)
 			case *tcell.EventResize:
 				w, h := ev.Size()
 				ch <- Event{
@@ -120,7 +120,7 @@ func (b *Backend) processEvent(ev tcell.Event, events chan<- Event, stop <-chan
 	case *tcell.EventKey:
 		converted = convertTcellKeyEvent(ev)
 	case *tcell.EventMouse:
-		converted = convertTcellMouseEvent(ev)
+		converted = convertTcellMouseEvent(ev, &b.mouseButtons)
 	case *tcell.EventResize:
 		w, h := ev.Size()
 		converted = Event{
@@ -216,7 +216,13 @@ func convertTcellKeyEvent(e *tcell.EventKey) Event {
 		Payload: e,
 	}
 }
-func convertTcellMouseEvent(e *tcell.EventMouse) Event {
+
+// convertTcellMouseEvent converts a tcell mouse event. pressed holds the
+// buttons held down by the previous event, so that motion with a button held
+// is reported as a drag of that button and letting go as <MouseRelease>.
+// Motion without any button held is reported as <MouseMove>; it used to be
+// reported as <MouseRelease>, which now only follows a press.
+func convertTcellMouseEvent(e *tcell.EventMouse, pressed *tcell.ButtonMask) Event {
 	btns := e.Buttons()
 	ID := "Unknown_Mouse_Button"
 	if btns&tcell.Button1 != 0 {
@@ -234,8 +240,16 @@ func convertTcellMouseEvent(e *tcell.EventMouse) Event {
 	if btns&tcell.WheelDown != 0 {
 		ID = "<MouseWheelDown>"
 	}
+	held := btns & (tcell.Button1 | tcell.Button2 | tcell.Button3)
+	drag := held != 0 && held == *pressed
 	if btns == tcell.ButtonNone {
 		ID = "<MouseRelease>"
+		if *pressed == tcell.ButtonNone {
+			ID = "<MouseMove>"
+		}
+	}
+	if btns&(tcell.WheelUp|tcell.WheelDown) == 0 {
+		*pressed = held
 	}
 	x, y := e.Position()
 	return Event{
@@ -244,7 +258,7 @@ func convertTcellMouseEvent(e *tcell.EventMouse) Event {
 		Payload: Mouse{
 			X:    x,
 			Y:    y,
-			Drag: false,
+			Drag: drag,
 		},
 	}
 }
@@ -1,9 +1,18 @@
 package gotui
 
+import (
+	"image"
+)
+
 // NewGrid returns a new Grid.
 func NewGrid() *Grid {
+	return NewGridWithContext(DefaultContext)
+}
+
+// NewGridWithContext returns a new Grid bound to ctx.
+func NewGridWithContext(ctx *Context) *Grid {
 	g := &Grid{
-		Block: *NewBlock(),
+		Block: *NewBlockWithContext(ctx),
 	}
 	g.Border = false
 	return g
@@ -11,121 +20,227 @@ func NewGrid() *Grid {
 
 // NewCol creates a new column with the given size ratio and items.
 func NewCol(ratio float64, i ...any) GridItem {
-	_, ok := i[0].(Drawable)
-	entry := i[0]
-	if !ok {
-		entry = i
-	}
-	return GridItem{
-		Type:   col,
-		Entry:  entry,
-		IsLeaf: ok,
-		ratio:  ratio,
-	}
+	return newGridItem(col, ratio, ratioConstraint(ratio), i)
 }
 
 // NewRow creates a new row with the given size ratio and items.
 func NewRow(ratio float64, i ...any) GridItem {
+	return newGridItem(row, ratio, ratioConstraint(ratio), i)
+}
+
+// NewColWithConstraint creates a new column sized by c.
+func NewColWithConstraint(c Constraint, i ...any) GridItem {
+	return newGridItem(col, c.value, c, i)
+}
+
+// NewRowWithConstraint creates a new row sized by c.
+func NewRowWithConstraint(c Constraint, i ...any) GridItem {
+	return newGridItem(row, c.value, c, i)
+}
+
+func newGridItem(t gridItemType, ratio float64, c Constraint, i []any) GridItem {
 	_, ok := i[0].(Drawable)
 	entry := i[0]
 	if !ok {
 		entry = i
 	}
 	return GridItem{
-		Type:   row,
-		Entry:  entry,
-		IsLeaf: ok,
-		ratio:  ratio,
+		Type:       t,
+		Entry:      entry,
+		IsLeaf:     ok,
+		ratio:      ratio,
+		constraint: c,
 	}
 }
 
-// Set sets the items in the grid.
+// Set sets the items in the grid. They are the default layout, used when
+// no breakpoint added with SetBreakpoint matches.
 func (g *Grid) Set(entries ...any) {
-	entry := GridItem{
+	g.setLayout(Breakpoint{}, entries)
+	g.active = 0
+	g.apply(entries)
+}
+
+// SetBreakpoint adds a layout that replaces the default one when the
+// terminal is at least bp in size. Setting the same breakpoint again
+// replaces its layout.
+func (g *Grid) SetBreakpoint(bp Breakpoint, entries ...any) {
+	if len(g.layouts) == 0 {
+		g.setLayout(Breakpoint{}, nil)
+	}
+	g.setLayout(bp, entries)
+}
+
+func (g *Grid) setLayout(bp Breakpoint, entries []any) {
+	for i := range g.layouts {
+		if g.layouts[i].Breakpoint == bp {
+			g.layouts[i].entries = entries
+			return
+		}
+	}
+	g.layouts = append(g.layouts, gridLayout{bp, entries})
+}
+
+// Respond switches to the layout with the largest breakpoint, by width and
+// then height, that fits width x height, and passes the size on to the
+// Responsive widgets in it. Application calls it when the terminal resizes.
+func (g *Grid) Respond(width, height int) {
+	best := -1
+	for i, l := range g.layouts {
+		if width < l.MinWidth || height < l.MinHeight {
+			continue
+		}
+		if best < 0 || l.MinWidth > g.layouts[best].MinWidth ||
+			(l.MinWidth == g.layouts[best].MinWidth && l.MinHeight > g.layouts[best].MinHeight) {
+			best = i
+		}
+	}
+	if best >= 0 && best != g.active {
+		g.active = best
+		g.apply(g.layouts[best].entries)
+	}
+	for _, item := range g.Items {
+		if r, ok := item.Entry.(Responsive); ok {
+			r.Respond(width, height)
+		}
+	}
+}
+
+// Breakpoint returns the breakpoint of the layout in use.
+func (g *Grid) Breakpoint() Breakpoint {
+	if g.active < len(g.layouts) {
+		return g.layouts[g.active].Breakpoint
+	}
+	return Breakpoint{}
+}
+
+func (g *Grid) apply(entries []any) {
+	g.Items = nil
+	g.root = &GridItem{
 		Type:   row,
 		Entry:  entries,
 		IsLeaf: false,
 		ratio:  1.0,
 	}
-	g.setHelper(entry, 1.0, 1.0)
+	g.addLeaves(*g.root)
 }
 
-func (g *Grid) setHelper(item GridItem, parentWidthRatio, parentHeightRatio float64) {
-	var HeightRatio float64
-	var WidthRatio float64
-	switch item.Type {
-	case col:
-		HeightRatio = 1.0
-		WidthRatio = item.ratio
-	case row:
-		HeightRatio = item.ratio
-		WidthRatio = 1.0
-	}
-	item.WidthRatio = parentWidthRatio * WidthRatio
-	item.HeightRatio = parentHeightRatio * HeightRatio
-
+// addLeaves adds the widgets under item to Items, in the order drawItem
+// lays them out.
+func (g *Grid) addLeaves(item GridItem) {
 	if item.IsLeaf {
 		g.Items = append(g.Items, &item)
-	} else {
-		XRatio := 0.0
-		YRatio := 0.0
-		cols := false
-		rows := false
-
-		children := InterfaceSlice(item.Entry)
-
-		for i := range children {
-			if children[i] == nil {
-				continue
-			}
-			child, _ := children[i].(GridItem)
-
-			child.XRatio = item.XRatio + (item.WidthRatio * XRatio)
-			child.YRatio = item.YRatio + (item.HeightRatio * YRatio)
-
-			switch child.Type {
-			case col:
-				cols = true
-				XRatio += child.ratio
-				if rows {
-					item.HeightRatio /= 2
-				}
-			case row:
-				rows = true
-				YRatio += child.ratio
-				if cols {
-					item.WidthRatio /= 2
-				}
-			}
-
-			g.setHelper(child, item.WidthRatio, item.HeightRatio)
+		return
+	}
+	for _, c := range InterfaceSlice(item.Entry) {
+		if child, ok := c.(GridItem); ok {
+			g.addLeaves(child)
 		}
 	}
 }
 
-// Draw draws the grid to the buffer.
-func (g *Grid) Draw(buf *Buffer) {
-
+// Children returns the widgets of the layout in use, implementing
+// Container.
+func (g *Grid) Children() []Drawable {
+	var children []Drawable
 	for _, item := range g.Items {
-		entry, _ := item.Entry.(Drawable)
-
-		xStart := int(float64(g.Dx())*item.XRatio) + g.Min.X
-		yStart := int(float64(g.Dy())*item.YRatio) + g.Min.Y
+		if d, ok := item.Entry.(Drawable); ok {
+			children = append(children, d)
+		}
+	}
+	return children
+}
 
-		xEnd := int(float64(g.Dx())*(item.XRatio+item.WidthRatio)) + g.Min.X
-		yEnd := int(float64(g.Dy())*(item.YRatio+item.HeightRatio)) + g.Min.Y
+// Draw draws the grid to the buffer.
+func (g *Grid) Draw(buf *Buffer) {
+	if g.root == nil {
+		return
+	}
+	leaf := 0
+	g.drawItem(buf, *g.root, g.Rectangle, &leaf)
+}
 
-		if xEnd > g.Max.X {
-			xEnd = g.Max.X
+// drawItem lays out the children of item inside area with the constraint
+// solver and draws the leaves. Rows are stacked and columns are put side
+// by side. When rows and columns share a parent, each run of columns
+// takes one Fill row between the rows and is split across it.
+func (g *Grid) drawItem(buf *Buffer, item GridItem, area image.Rectangle, leaf *int) {
+	if item.IsLeaf {
+		g.placeItem(*leaf, area)
+		*leaf++
+		g.drawLeaf(buf, item.Entry, area)
+		return
+	}
+	var children []GridItem
+	for _, c := range InterfaceSlice(item.Entry) {
+		if child, ok := c.(GridItem); ok {
+			children = append(children, child)
+		}
+	}
+	if len(children) == 0 {
+		return
+	}
+	kind := children[0].Type
+	for _, c := range children {
+		if c.Type != kind {
+			kind = row
+			break
+		}
+	}
+	var slots []GridItem
+	var constraints []Constraint
+	for i := 0; i < len(children); {
+		if children[i].Type == kind {
+			slots = append(slots, children[i])
+			constraints = append(constraints, children[i].constraint)
+			i++
+			continue
 		}
-		if yEnd > g.Max.Y {
-			yEnd = g.Max.Y
+		var run []any
+		for ; i < len(children) && children[i].Type != kind; i++ {
+			run = append(run, children[i])
 		}
+		slots = append(slots, GridItem{Type: kind, Entry: run})
+		constraints = append(constraints, Fill(1))
+	}
+	dir, spacing := LayoutVertical, g.RowSpacing
+	if kind == col {
+		dir, spacing = LayoutHorizontal, g.ColSpacing
+	}
+	if g.MergeBorders && spacing == 0 {
+		spacing = -1
+	}
+	for i, r := range SplitWithSpacing(area, dir, spacing, constraints...) {
+		g.drawItem(buf, slots[i], r, leaf)
+	}
+}
 
-		entry.SetRect(xStart, yStart, xEnd, yEnd)
+// placeItem records the area of the i-th item of Items as fractions of the
+// grid.
+func (g *Grid) placeItem(i int, area image.Rectangle) {
+	if i >= len(g.Items) || g.Dx() <= 0 || g.Dy() <= 0 {
+		return
+	}
+	item := g.Items[i]
+	item.XRatio = float64(area.Min.X-g.Min.X) / float64(g.Dx())
+	item.YRatio = float64(area.Min.Y-g.Min.Y) / float64(g.Dy())
+	item.WidthRatio = float64(area.Dx()) / float64(g.Dx())
+	item.HeightRatio = float64(area.Dy()) / float64(g.Dy())
+}
 
-		entry.Lock()
-		entry.Draw(buf)
-		entry.Unlock()
+func (g *Grid) drawLeaf(buf *Buffer, e any, area image.Rectangle) {
+	entry, _ := e.(Drawable)
+	entry.SetRect(area.Min.X, area.Min.Y, area.Max.X, area.Max.Y)
+	if IsHidden(entry) {
+		return
+	}
+	if g.MergeBorders {
+		DrawMerged(buf, entry)
+		return
 	}
+	entry.Lock()
+	buf.PushClip(area)
+	entry.Draw(buf)
+	buf.PopClip()
+	entry.Unlock()
 }
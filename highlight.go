@@ -0,0 +1,226 @@
+package gotui
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Token is a run of source code of one kind.
+type Token struct {
+	Kind TokenKind
+	Text string
+}
+
+// LexState is what a Lexer carries from the end of one line to the start
+// of the next, such as being inside a block comment. The first line starts
+// in state 0.
+type LexState int
+
+// Lexer splits source code into tokens one line at a time, so that an
+// edit only needs the lines from the edit on to be lexed again. LexLine
+// returns the tokens of line, whose texts join up to line, and the state
+// at its end.
+type Lexer interface {
+	LexLine(line string, state LexState) ([]Token, LexState)
+}
+
+// LexerFunc adapts a function to the Lexer interface.
+type LexerFunc func(line string, state LexState) ([]Token, LexState)
+
+// LexLine calls f.
+func (f LexerFunc) LexLine(line string, state LexState) ([]Token, LexState) {
+	return f(line, state)
+}
+
+var (
+	lexersMu sync.RWMutex
+	lexers   = map[string]Lexer{}
+)
+
+// RegisterLexer makes lexer available to LexerFor under the given names,
+// such as a language name and its file extensions.
+func RegisterLexer(lexer Lexer, names ...string) {
+	lexersMu.Lock()
+	defer lexersMu.Unlock()
+	for _, name := range names {
+		lexers[strings.ToLower(name)] = lexer
+	}
+}
+
+// LexerFor returns the lexer registered under name, which may be a
+// language such as "go" or "yaml" or a file extension such as ".yml". It
+// returns nil when there is none.
+func LexerFor(name string) Lexer {
+	lexersMu.RLock()
+	defer lexersMu.RUnlock()
+	return lexers[strings.TrimPrefix(strings.ToLower(name), ".")]
+}
+
+func init() {
+	RegisterLexer(GoLexer, "go", "golang")
+	RegisterLexer(JSONLexer, "json")
+	RegisterLexer(YAMLLexer, "yaml", "yml")
+	RegisterLexer(ShellLexer, "sh", "bash", "shell", "zsh")
+	RegisterLexer(DiffLexer, "diff", "patch")
+}
+
+// Style returns the style for tokens of the given kind.
+func (t SyntaxTheme) Style(kind TokenKind) Style {
+	switch kind {
+	case TokenKeyword:
+		return t.Keyword
+	case TokenType:
+		return t.Type
+	case TokenBuiltin:
+		return t.Builtin
+	case TokenVariable:
+		return t.Variable
+	case TokenString:
+		return t.String
+	case TokenNumber:
+		return t.Number
+	case TokenComment:
+		return t.Comment
+	case TokenOperator:
+		return t.Operator
+	case TokenKey:
+		return t.Key
+	case TokenInserted:
+		return t.Inserted
+	case TokenDeleted:
+		return t.Deleted
+	case TokenHeading:
+		return t.Heading
+	}
+	return t.Text
+}
+
+// TokenCells returns the cells of tokens in the styles of theme.
+func TokenCells(tokens []Token, theme SyntaxTheme) []Cell {
+	var cells []Cell
+	for _, tok := range tokens {
+		cells = append(cells, StringToStyledCells(tok.Text, theme.Style(tok.Kind))...)
+	}
+	return cells
+}
+
+// Highlight lexes src and returns its cells in the styles of theme, with
+// a '\n' cell between lines.
+func Highlight(src string, lexer Lexer, theme SyntaxTheme) []Cell {
+	var cells []Cell
+	var state LexState
+	for i, line := range strings.Split(src, "\n") {
+		if i > 0 {
+			cells = append(cells, Cell{Rune: '\n', Style: StyleClear})
+		}
+		var tokens []Token
+		tokens, state = lexer.LexLine(line, state)
+		cells = append(cells, TokenCells(tokens, theme)...)
+	}
+	return cells
+}
+
+// Highlighter keeps the tokens of a text that is edited, such as the
+// contents of a TextArea. After SetLines, only the lines that changed and
+// the lines whose start state they change are lexed again, and only when
+// Line asks for them.
+type Highlighter struct {
+	lexer  Lexer
+	lines  []string
+	tokens [][]Token
+	states []LexState // the state at the start of each line
+	ends   []LexState // the state at the end of each lexed line
+	valid  int        // lines before valid are up to date
+	// Lines from reuse to reuseEnd were lexed before the edit and are up to
+	// date if they still start in the same state.
+	reuse, reuseEnd int
+}
+
+// NewHighlighter returns a Highlighter using lexer.
+func NewHighlighter(lexer Lexer) *Highlighter {
+	return &Highlighter{lexer: lexer}
+}
+
+// SetLexer changes the lexer, dropping all tokens when it is a different
+// one.
+func (h *Highlighter) SetLexer(lexer Lexer) {
+	if !sameLexer(h.lexer, lexer) {
+		h.lexer = lexer
+		h.valid, h.reuseEnd = 0, 0
+	}
+}
+
+// sameLexer compares lexers without panicking on ones that are functions,
+// which are the same when they point to the same code.
+func sameLexer(a, b Lexer) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case va.Type() != vb.Type():
+		return false
+	case va.Kind() == reflect.Func:
+		return va.Pointer() == vb.Pointer()
+	case va.Comparable():
+		return va.Equal(vb)
+	}
+	return false
+}
+
+// SetLines replaces the text. The lines before the first change and after
+// the last one keep their tokens while their start state stays the same.
+func (h *Highlighter) SetLines(lines []string) {
+	prefix := 0
+	for prefix < len(lines) && prefix < len(h.lines) && lines[prefix] == h.lines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(lines)-prefix && suffix < len(h.lines)-prefix &&
+		lines[len(lines)-1-suffix] == h.lines[len(h.lines)-1-suffix] {
+		suffix++
+	}
+	// Of those, only the ones that were lexed can be reused.
+	reused := max(min(len(h.lines), h.valid)-(len(h.lines)-suffix), 0)
+
+	n := len(lines)
+	tokens := make([][]Token, n)
+	states := make([]LexState, n)
+	ends := make([]LexState, n)
+	valid := min(prefix, h.valid)
+	copy(tokens, h.tokens[:valid])
+	copy(states, h.states[:valid])
+	copy(ends, h.ends[:valid])
+	shift := len(h.lines) - n
+	for i := n - suffix; i < n-suffix+reused; i++ {
+		tokens[i], states[i], ends[i] = h.tokens[i+shift], h.states[i+shift], h.ends[i+shift]
+	}
+	h.lines = append(h.lines[:0:0], lines...)
+	h.tokens, h.states, h.ends = tokens, states, ends
+	h.valid = valid
+	h.reuse, h.reuseEnd = n-suffix, n-suffix+reused
+}
+
+// Line returns the tokens of line i, lexing the lines before it that are
+// out of date.
+func (h *Highlighter) Line(i int) []Token {
+	if i < 0 || i >= len(h.lines) || h.lexer == nil {
+		return nil
+	}
+	for h.valid <= i {
+		j := h.valid
+		var state LexState
+		if j > 0 {
+			state = h.ends[j-1]
+		}
+		if j >= h.reuse && j < h.reuseEnd && h.states[j] == state {
+			h.valid = h.reuseEnd
+			continue
+		}
+		h.states[j] = state
+		h.tokens[j], h.ends[j] = h.lexer.LexLine(h.lines[j], state)
+		h.valid++
+	}
+	return h.tokens[i]
+}
@@ -0,0 +1,216 @@
+package gotui
+
+import (
+	"image"
+	"math"
+)
+
+// Length returns a constraint for exactly n cells.
+func Length(n int) Constraint {
+	return Constraint{kind: constraintLength, value: float64(max(n, 0))}
+}
+
+// Percentage returns a constraint for p percent of the available space.
+func Percentage(p int) Constraint {
+	return Constraint{kind: constraintPercentage, value: float64(max(p, 0)) / 100}
+}
+
+// Ratio returns a constraint for num/den of the available space.
+func Ratio(num, den int) Constraint {
+	if den <= 0 || num < 0 {
+		return Constraint{kind: constraintRatio}
+	}
+	return Constraint{kind: constraintRatio, value: float64(num) / float64(den)}
+}
+
+// Min returns a constraint for at least n cells. It takes the remaining
+// space when the layout has no Fill constraint.
+func Min(n int) Constraint {
+	return Constraint{kind: constraintMin, value: float64(max(n, 0))}
+}
+
+// Max returns a constraint for at most n cells. It is the first to give up
+// space when the layout does not fit.
+func Max(n int) Constraint {
+	return Constraint{kind: constraintMax, value: float64(max(n, 0))}
+}
+
+// Fill returns a constraint that shares the remaining space with the other
+// Fill constraints in proportion to weight.
+func Fill(weight int) Constraint {
+	return Constraint{kind: constraintFill, value: float64(max(weight, 0))}
+}
+
+// Preferred returns the size c asks for within total cells before any
+// remaining space is shared out. Fill constraints prefer no space at all.
+func (c Constraint) Preferred(total int) int {
+	switch c.kind {
+	case constraintPercentage, constraintRatio:
+		return int(math.Floor(c.value*float64(max(total, 0)) + 0.5))
+	case constraintFill:
+		return 0
+	}
+	return int(c.value)
+}
+
+// ratioConstraint is used by the float ratios of NewRow and NewCol.
+func ratioConstraint(r float64) Constraint {
+	return Constraint{kind: constraintRatio, value: math.Max(r, 0)}
+}
+
+// SolveLayout returns the size of every constraint within total cells.
+//
+// Space is handed out in priority order: Length and Min first, then
+// Percentage and Ratio, then Max, then Fill (or Min when there is no Fill).
+// When the constraints do not fit, the lowest priority ones shrink first and
+// Length and Min are cut from the end. Sizes are rounded on their cumulative
+// edges so they never overlap and, when every cell is claimed, add up to
+// exactly total. Space that nothing claims is left after the last slot.
+func SolveLayout(total int, constraints ...Constraint) []int {
+	total = max(total, 0)
+	sizes := make([]float64, len(constraints))
+	space := float64(total)
+
+	space -= placeHard(sizes, constraints, space)
+	space -= placeRelative(sizes, constraints, float64(total), space)
+	space -= placeMax(sizes, constraints, space)
+	placeRemaining(sizes, constraints, space)
+
+	return roundEdges(sizes)
+}
+
+// Split divides area along dir according to constraints.
+func Split(area image.Rectangle, dir LayoutDirection, constraints ...Constraint) []image.Rectangle {
+	return SplitWithSpacing(area, dir, 0, constraints...)
+}
+
+// SplitWithSpacing divides area along dir according to constraints, leaving
+// spacing cells between neighbouring slots. A negative spacing makes
+// neighbours overlap, which lets them share a border.
+func SplitWithSpacing(area image.Rectangle, dir LayoutDirection, spacing int, constraints ...Constraint) []image.Rectangle {
+	total := area.Dx()
+	if dir == LayoutVertical {
+		total = area.Dy()
+	}
+	gaps := spacing * max(len(constraints)-1, 0)
+	sizes := SolveLayout(total-gaps, constraints...)
+	rects := make([]image.Rectangle, len(sizes))
+	pos := 0
+	for i, size := range sizes {
+		if dir == LayoutVertical {
+			rects[i] = image.Rect(area.Min.X, area.Min.Y+pos, area.Max.X, area.Min.Y+pos+size)
+		} else {
+			rects[i] = image.Rect(area.Min.X+pos, area.Min.Y, area.Min.X+pos+size, area.Max.Y)
+		}
+		pos += size + spacing
+	}
+	return rects
+}
+
+// Place returns a width x height rectangle pinned to the anchor point of
+// area and moved by dx, dy. Sizes are clamped to area, so a width or height
+// of 0 or less gives an empty rectangle on that axis and SizeFill takes the
+// whole of area.
+func Place(area image.Rectangle, anchor Anchor, width, height, dx, dy int) image.Rectangle {
+	width = min(max(width, 0), area.Dx())
+	height = min(max(height, 0), area.Dy())
+	x, y := area.Min.X, area.Min.Y
+	switch anchor % 3 {
+	case 1:
+		x += (area.Dx() - width) / 2
+	case 2:
+		x = area.Max.X - width
+	}
+	switch anchor / 3 {
+	case 1:
+		y += (area.Dy() - height) / 2
+	case 2:
+		y = area.Max.Y - height
+	}
+	return image.Rect(x, y, x+width, y+height).Add(image.Pt(dx, dy))
+}
+
+func placeHard(sizes []float64, constraints []Constraint, space float64) float64 {
+	used := 0.0
+	for i, c := range constraints {
+		if c.kind != constraintLength && c.kind != constraintMin {
+			continue
+		}
+		sizes[i] = math.Min(c.value, space-used)
+		used += sizes[i]
+	}
+	return used
+}
+
+func placeRelative(sizes []float64, constraints []Constraint, total, space float64) float64 {
+	want := 0.0
+	for _, c := range constraints {
+		if c.kind == constraintPercentage || c.kind == constraintRatio {
+			want += c.value * total
+		}
+	}
+	if want == 0 {
+		return 0
+	}
+	scale := math.Min(1, space/want)
+	for i, c := range constraints {
+		if c.kind == constraintPercentage || c.kind == constraintRatio {
+			sizes[i] = c.value * total * scale
+		}
+	}
+	return want * scale
+}
+
+func placeMax(sizes []float64, constraints []Constraint, space float64) float64 {
+	used := 0.0
+	for i, c := range constraints {
+		if c.kind != constraintMax {
+			continue
+		}
+		sizes[i] = math.Min(c.value, space-used)
+		used += sizes[i]
+	}
+	return used
+}
+
+func placeRemaining(sizes []float64, constraints []Constraint, space float64) {
+	if space <= 0 {
+		return
+	}
+	weights := make([]float64, len(constraints))
+	sum := 0.0
+	for i, c := range constraints {
+		if c.kind == constraintFill {
+			weights[i] = c.value
+			sum += c.value
+		}
+	}
+	if sum == 0 {
+		for i, c := range constraints {
+			if c.kind == constraintMin {
+				weights[i] = 1
+				sum++
+			}
+		}
+	}
+	if sum == 0 {
+		return
+	}
+	for i, w := range weights {
+		sizes[i] += space * w / sum
+	}
+}
+
+// roundEdges rounds the cumulative edges of sizes so that whole cells are
+// never lost or counted twice.
+func roundEdges(sizes []float64) []int {
+	out := make([]int, len(sizes))
+	edge, prev := 0.0, 0
+	for i, s := range sizes {
+		edge += s
+		next := int(math.Floor(edge + 0.5 + 1e-9))
+		out[i] = max(next-prev, 0)
+		prev += out[i]
+	}
+	return out
+}
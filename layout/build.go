@@ -0,0 +1,363 @@
+package layout
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+	"gopkg.in/yaml.v3"
+)
+
+// builder turns yaml nodes into widgets, collecting errors as it goes so
+// that a single load reports every problem in the file.
+type builder struct {
+	loader *Loader
+	file   string
+	doc    *Document
+	built  map[*yaml.Node]ui.Drawable
+	errs   []error
+}
+
+// pair is a key and its value in a yaml mapping.
+type pair struct {
+	key, value *yaml.Node
+}
+
+// flexItemKeys are the keys a child of a flex uses to describe its item.
+var flexItemKeys = []string{"size", "minsize", "maxsize", "crosssize", "focus"}
+
+// gridKeys are the keys a grid uses for its layouts.
+var gridKeys = []string{"children", "breakpoints"}
+
+func (b *builder) errorf(n *yaml.Node, format string, args ...any) {
+	b.errs = append(b.errs, &Error{File: b.file, Line: n.Line, Column: n.Column, Msg: fmt.Sprintf(format, args...)})
+}
+
+// mapping returns the pairs of a mapping node, reporting anything else.
+func (b *builder) mapping(n *yaml.Node) ([]pair, bool) {
+	if n.Kind != yaml.MappingNode {
+		b.errorf(n, "expected a mapping, got %s", kindName(n))
+		return nil, false
+	}
+	pairs := make([]pair, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		pairs = append(pairs, pair{n.Content[i], n.Content[i+1]})
+	}
+	return pairs, true
+}
+
+func lookup(pairs []pair, key string) *yaml.Node {
+	for _, p := range pairs {
+		if normalize(p.key.Value) == key {
+			return p.value
+		}
+	}
+	return nil
+}
+
+func kindName(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.ScalarNode:
+		return fmt.Sprintf("%q", n.Value)
+	}
+	return "nothing"
+}
+
+// node builds the widget described by n. parentKeys are keys that belong to
+// the parent container and are skipped here.
+func (b *builder) node(n *yaml.Node, parentKeys []string) ui.Drawable {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if w, ok := b.built[n]; ok {
+		return w
+	}
+	pairs, ok := b.mapping(n)
+	if !ok {
+		return nil
+	}
+	typeNode := lookup(pairs, "type")
+	if typeNode == nil || typeNode.Kind != yaml.ScalarNode || typeNode.Value == "" {
+		b.errorf(n, "missing widget type")
+		return nil
+	}
+	var w ui.Drawable
+	var own []string
+	switch typ := normalize(typeNode.Value); typ {
+	case "grid":
+		w, own = b.grid(n, pairs), gridKeys
+	case "flex":
+		w, own = b.flex(n, pairs), []string{"children"}
+	case "row", "col":
+		b.errorf(typeNode, "%s is only allowed in a grid", typeNode.Value)
+		return nil
+	default:
+		w, ok = b.loader.Registry.New(typ, b.loader.Context)
+		if !ok {
+			b.errorf(typeNode, "unknown widget type %q", typeNode.Value)
+			return nil
+		}
+		if c := lookup(pairs, "children"); c != nil {
+			b.errorf(c, "%s cannot have children", typeNode.Value)
+		}
+	}
+	b.built[n] = w
+	skip := append(append([]string{"type", "id"}, own...), parentKeys...)
+	b.setProps(w, typeNode.Value, pairs, skip)
+	b.register(lookup(pairs, "id"), w)
+	return w
+}
+
+func (b *builder) register(id *yaml.Node, w ui.Drawable) {
+	if id == nil {
+		return
+	}
+	if id.Kind != yaml.ScalarNode || id.Value == "" {
+		b.errorf(id, "id must be a string")
+		return
+	}
+	if _, dup := b.doc.ids[id.Value]; dup {
+		b.errorf(id, "duplicate id %q", id.Value)
+		return
+	}
+	b.doc.ids[id.Value] = w
+	if v := reflect.ValueOf(w); v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct {
+		if f, ok := field(v.Elem(), "ID"); ok && f.Kind() == reflect.String {
+			f.SetString(id.Value)
+		}
+	}
+}
+
+// list returns the items of a sequence node.
+func (b *builder) list(n *yaml.Node, what string) []*yaml.Node {
+	if n == nil {
+		return nil
+	}
+	if n.Kind != yaml.SequenceNode {
+		b.errorf(n, "%s must be a list, got %s", what, kindName(n))
+		return nil
+	}
+	return n.Content
+}
+
+func (b *builder) grid(n *yaml.Node, pairs []pair) ui.Drawable {
+	g := ui.NewGridWithContext(b.loader.Context)
+	children := lookup(pairs, "children")
+	if children == nil {
+		b.errorf(n, "grid needs children")
+	}
+	g.Set(b.gridItems(b.list(children, "children"))...)
+	for _, bp := range b.list(lookup(pairs, "breakpoints"), "breakpoints") {
+		b.breakpoint(g, bp)
+	}
+	return g
+}
+
+func (b *builder) breakpoint(g *ui.Grid, n *yaml.Node) {
+	pairs, ok := b.mapping(n)
+	if !ok {
+		return
+	}
+	var bp ui.Breakpoint
+	for _, p := range pairs {
+		switch normalize(p.key.Value) {
+		case "minwidth":
+			bp.MinWidth = b.int(p.value)
+		case "minheight":
+			bp.MinHeight = b.int(p.value)
+		case "children":
+		default:
+			b.errorf(p.key, "unknown breakpoint key %q", p.key.Value)
+		}
+	}
+	g.SetBreakpoint(bp, b.gridItems(b.list(lookup(pairs, "children"), "children"))...)
+}
+
+func (b *builder) gridItems(nodes []*yaml.Node) []any {
+	items := make([]any, 0, len(nodes))
+	for _, n := range nodes {
+		if item, ok := b.gridItem(n); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// gridItem builds a row or column holding either a single widget or more
+// rows and columns.
+func (b *builder) gridItem(n *yaml.Node) (ui.GridItem, bool) {
+	pairs, ok := b.mapping(n)
+	if !ok {
+		return ui.GridItem{}, false
+	}
+	typ := lookup(pairs, "type")
+	if typ == nil || (normalize(typ.Value) != "row" && normalize(typ.Value) != "col") {
+		b.errorf(n, "grid children must be of type row or col")
+		return ui.GridItem{}, false
+	}
+	c := ui.Fill(1)
+	for _, p := range pairs {
+		switch normalize(p.key.Value) {
+		case "type", "children":
+		case "size":
+			c = b.constraint(p.value)
+		default:
+			b.errorf(p.key, "unknown %s key %q", typ.Value, p.key.Value)
+		}
+	}
+	entries := b.gridEntries(n, b.list(lookup(pairs, "children"), "children"))
+	if len(entries) == 0 {
+		return ui.GridItem{}, false
+	}
+	if normalize(typ.Value) == "row" {
+		return ui.NewRowWithConstraint(c, entries...), true
+	}
+	return ui.NewColWithConstraint(c, entries...), true
+}
+
+func (b *builder) gridEntries(parent *yaml.Node, children []*yaml.Node) []any {
+	if len(children) == 0 {
+		b.errorf(parent, "row or col needs children")
+		return nil
+	}
+	if len(children) == 1 && !isGridItem(children[0]) {
+		if w := b.node(children[0], nil); w != nil {
+			return []any{w}
+		}
+		return nil
+	}
+	for _, c := range children {
+		if !isGridItem(c) {
+			b.errorf(c, "a widget must be the only child of its row or col")
+			return nil
+		}
+	}
+	return b.gridItems(children)
+}
+
+func isGridItem(n *yaml.Node) bool {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	for i := 0; i+1 < len(n.Content) && n.Kind == yaml.MappingNode; i += 2 {
+		if normalize(n.Content[i].Value) == "type" {
+			t := normalize(n.Content[i+1].Value)
+			return t == "row" || t == "col"
+		}
+	}
+	return false
+}
+
+func (b *builder) flex(n *yaml.Node, pairs []pair) ui.Drawable {
+	f := widgets.NewFlexWithContext(b.loader.Context)
+	for _, child := range b.list(lookup(pairs, "children"), "children") {
+		w := b.node(child, flexItemKeys)
+		if w == nil {
+			continue
+		}
+		item := f.AddItemWithConstraint(w, ui.Fill(1), false)
+		b.flexItem(child, item)
+	}
+	return f
+}
+
+func (b *builder) flexItem(n *yaml.Node, item *widgets.FlexItem) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	pairs, _ := b.mapping(n)
+	for _, p := range pairs {
+		switch normalize(p.key.Value) {
+		case "size":
+			c := b.constraint(p.value)
+			item.Constraint = &c
+		case "minsize":
+			item.MinSize = b.int(p.value)
+		case "maxsize":
+			item.MaxSize = b.int(p.value)
+		case "crosssize":
+			item.CrossSize = b.int(p.value)
+		case "focus":
+			if b.bool(p.value) {
+				item.Focus = true
+			}
+		}
+	}
+}
+
+func (b *builder) int(n *yaml.Node) int {
+	var v int
+	if n.Kind != yaml.ScalarNode || n.Decode(&v) != nil {
+		b.errorf(n, "expected an integer, got %s", kindName(n))
+	}
+	return v
+}
+
+func (b *builder) bool(n *yaml.Node) bool {
+	var v bool
+	if n.Kind != yaml.ScalarNode || n.Decode(&v) != nil {
+		b.errorf(n, "expected true or false, got %s", kindName(n))
+	}
+	return v
+}
+
+// constraint parses a size, see the package documentation.
+func (b *builder) constraint(n *yaml.Node) ui.Constraint {
+	c, err := parseConstraint(n.Value)
+	if n.Kind != yaml.ScalarNode || err != nil {
+		b.errorf(n, "invalid size %s: use 3, 30%%, 1/3, 0.5, min:3, max:3 or fill:1", kindName(n))
+		return ui.Fill(1)
+	}
+	return c
+}
+
+func parseConstraint(s string) (ui.Constraint, error) {
+	s = strings.TrimSpace(s)
+	if kind, arg, ok := strings.Cut(s, ":"); ok {
+		n, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil {
+			return ui.Constraint{}, err
+		}
+		switch strings.TrimSpace(kind) {
+		case "min":
+			return ui.Min(n), nil
+		case "max":
+			return ui.Max(n), nil
+		case "fill":
+			return ui.Fill(n), nil
+		case "length":
+			return ui.Length(n), nil
+		}
+		return ui.Constraint{}, fmt.Errorf("unknown size %q", kind)
+	}
+	if s == "fill" {
+		return ui.Fill(1), nil
+	}
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		n, err := strconv.Atoi(p)
+		return ui.Percentage(n), err
+	}
+	if num, den, ok := strings.Cut(s, "/"); ok {
+		a, err1 := strconv.Atoi(num)
+		d, err2 := strconv.Atoi(den)
+		if err1 != nil || err2 != nil || d <= 0 {
+			return ui.Constraint{}, fmt.Errorf("invalid ratio %q", s)
+		}
+		return ui.Ratio(a, d), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return ui.Length(n), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 || f > 1 {
+		return ui.Constraint{}, fmt.Errorf("invalid size %q", s)
+	}
+	return ui.Ratio(int(f*10000+0.5), 10000), nil
+}
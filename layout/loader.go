@@ -0,0 +1,145 @@
+// Package layout builds dashboards from YAML or JSON documents.
+//
+// A document is a tree of nodes. Every node has a type: "grid", "row" and
+// "col" build a ui.Grid, "flex" builds a widgets.Flex, and any other type
+// is looked up in a Registry. The remaining keys of a node set the exported
+// fields of the widget, matched ignoring case and underscores:
+//
+//	type: grid
+//	children:
+//	  - type: row
+//	    size: 3
+//	    children:
+//	      - type: paragraph
+//	        id: header
+//	        title: Status
+//	        text: all systems go
+//	  - type: row
+//	    children:
+//	      - type: col
+//	        size: 30%
+//	        children: [{type: list, id: hosts, rows: [a, b]}]
+//	      - type: col
+//	        children: [{type: gauge, id: cpu, percent: 40}]
+//
+// Sizes are constraints: 3 is Length(3), 30% is Percentage(30), 1/3 is
+// Ratio(1, 3), 0.5 is a ratio like NewRow(0.5, ...), and "min:3", "max:8",
+// "fill" or "fill:2" map to Min, Max and Fill.
+package layout
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Error is a problem at a position in a layout document.
+type Error struct {
+	File   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	file := e.File
+	if file == "" {
+		file = "<layout>"
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", file, e.Line, e.Column, e.Msg)
+}
+
+// Document is a loaded layout.
+type Document struct {
+	Root ui.Drawable
+	ids  map[string]ui.Drawable
+}
+
+// Widget returns the widget with the given id.
+func (d *Document) Widget(id string) ui.Drawable {
+	return d.ids[id]
+}
+
+// IDs returns the ids used in the document.
+func (d *Document) IDs() []string {
+	ids := make([]string, 0, len(d.ids))
+	for id := range d.ids {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Get returns the widget with the given id if it is a T.
+func Get[T ui.Drawable](d *Document, id string) (T, bool) {
+	w, ok := d.ids[id].(T)
+	return w, ok
+}
+
+// Loader builds documents with the widgets of Registry, bound to Context.
+type Loader struct {
+	Context  *ui.Context
+	Registry *Registry
+}
+
+// NewLoader returns a Loader using DefaultRegistry and ui.DefaultContext.
+func NewLoader() *Loader {
+	return &Loader{Context: ui.DefaultContext, Registry: DefaultRegistry}
+}
+
+// Load builds a document from YAML or JSON data.
+func Load(data []byte) (*Document, error) {
+	return NewLoader().Load(data)
+}
+
+// LoadFile builds a document from a YAML or JSON file.
+func LoadFile(path string) (*Document, error) {
+	return NewLoader().LoadFile(path)
+}
+
+// Load builds a document from YAML or JSON data.
+func (l *Loader) Load(data []byte) (*Document, error) {
+	return l.load("", data)
+}
+
+// LoadFile builds a document from a YAML or JSON file.
+func (l *Loader) LoadFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.load(path, data)
+}
+
+// load parses data and reports every problem found, each as an *Error.
+func (l *Loader) load(file string, data []byte) (*Document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("%s: %w", displayName(file), err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil, &Error{File: file, Line: 1, Column: 1, Msg: "empty layout"}
+	}
+	b := &builder{
+		loader: l,
+		file:   file,
+		doc:    &Document{ids: make(map[string]ui.Drawable)},
+		built:  make(map[*yaml.Node]ui.Drawable),
+	}
+	b.doc.Root = b.node(root.Content[0], nil)
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	return b.doc, nil
+}
+
+func displayName(file string) string {
+	if file == "" {
+		return "<layout>"
+	}
+	return file
+}
@@ -0,0 +1,158 @@
+package layout
+
+import (
+	"reflect"
+	"slices"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	styleType = reflect.TypeFor[ui.Style]()
+	colorType = reflect.TypeFor[ui.Color]()
+)
+
+// enumNames lets enum fields be written by name instead of number.
+var enumNames = map[reflect.Type]map[string]int64{
+	reflect.TypeFor[ui.Alignment]():          {"left": int64(ui.AlignLeft), "center": int64(ui.AlignCenter), "right": int64(ui.AlignRight)},
+	reflect.TypeFor[ui.VerticalAlignment]():  {"top": int64(ui.AlignTop), "middle": int64(ui.AlignMiddle), "bottom": int64(ui.AlignBottom)},
+	reflect.TypeFor[ui.LayoutDirection]():    {"horizontal": int64(ui.LayoutHorizontal), "vertical": int64(ui.LayoutVertical)},
+	reflect.TypeFor[widgets.FlexDirection](): {"row": int64(widgets.FlexRow), "column": int64(widgets.FlexColumn)},
+	reflect.TypeFor[widgets.FlexJustify](): {
+		"start": int64(widgets.FlexJustifyStart), "center": int64(widgets.FlexJustifyCenter),
+		"end": int64(widgets.FlexJustifyEnd), "spacebetween": int64(widgets.FlexJustifySpaceBetween),
+	},
+	reflect.TypeFor[widgets.FlexAlign](): {
+		"stretch": int64(widgets.FlexAlignStretch), "start": int64(widgets.FlexAlignStart),
+		"center": int64(widgets.FlexAlignCenter), "end": int64(widgets.FlexAlignEnd),
+	},
+	reflect.TypeFor[widgets.PlotType](): {"line": int64(widgets.LineChart), "scatter": int64(widgets.ScatterPlot)},
+}
+
+// setProps sets the exported fields of w named by the keys of pairs,
+// except for the keys in skip.
+func (b *builder) setProps(w any, typeName string, pairs []pair, skip []string) {
+	v := reflect.ValueOf(w)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	for _, p := range pairs {
+		if slices.Contains(skip, normalize(p.key.Value)) {
+			continue
+		}
+		f, ok := field(v.Elem(), p.key.Value)
+		if !ok {
+			b.errorf(p.key, "unknown property %q for %s", p.key.Value, typeName)
+			continue
+		}
+		b.decode(p.value, f, p.key.Value)
+	}
+}
+
+// field finds the settable field of v called name, looking into embedded
+// structs such as ui.Block.
+func field(v reflect.Value, name string) (reflect.Value, bool) {
+	want := normalize(name)
+	f := v.FieldByNameFunc(func(n string) bool { return normalize(n) == want })
+	return f, f.IsValid() && f.CanSet()
+}
+
+// decode stores the value of n in v, reporting values that do not fit.
+func (b *builder) decode(n *yaml.Node, v reflect.Value, name string) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if b.decodeSpecial(n, v, name) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		b.decodeScalar(n, v, name)
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			b.errorf(n, "%s: expected a list, got %s", name, kindName(n))
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), len(n.Content), len(n.Content))
+		for i, item := range n.Content {
+			b.decode(item, s.Index(i), name)
+		}
+		v.Set(s)
+	case reflect.Pointer:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			b.errorf(n, "%s cannot be set from a layout file", name)
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		b.decode(n, p.Elem(), name)
+		v.Set(p)
+	case reflect.Struct:
+		pairs, ok := b.mapping(n)
+		if !ok {
+			return
+		}
+		b.setProps(v.Addr().Interface(), name, pairs, nil)
+	default:
+		b.errorf(n, "%s cannot be set from a layout file", name)
+	}
+}
+
+// decodeSpecial handles styles, colors and enums written as strings.
+func (b *builder) decodeSpecial(n *yaml.Node, v reflect.Value, name string) bool {
+	if n.Kind != yaml.ScalarNode || n.Tag != "!!str" {
+		return false
+	}
+	switch {
+	case v.Type() == styleType:
+		s, err := ui.ParseStyle(n.Value, v.Interface().(ui.Style))
+		if err != nil {
+			b.errorf(n, "%s: %v", name, err)
+			return true
+		}
+		v.Set(reflect.ValueOf(s))
+		return true
+	case v.Type() == colorType:
+		c, err := ui.ParseColor(n.Value)
+		if err != nil {
+			b.errorf(n, "%s: %v", name, err)
+			return true
+		}
+		v.Set(reflect.ValueOf(c))
+		return true
+	}
+	names, ok := enumNames[v.Type()]
+	if !ok {
+		return false
+	}
+	val, ok := names[normalize(n.Value)]
+	if !ok {
+		b.errorf(n, "%s: unknown value %q", name, n.Value)
+		return true
+	}
+	if v.CanInt() {
+		v.SetInt(val)
+	} else {
+		v.SetUint(uint64(val))
+	}
+	return true
+}
+
+func (b *builder) decodeScalar(n *yaml.Node, v reflect.Value, name string) {
+	if n.Kind != yaml.ScalarNode {
+		b.errorf(n, "%s: expected %s, got %s", name, v.Type(), kindName(n))
+		return
+	}
+	if v.Kind() == reflect.String {
+		v.SetString(n.Value)
+		return
+	}
+	p := reflect.New(v.Type())
+	if err := n.Decode(p.Interface()); err != nil {
+		b.errorf(n, "%s: expected %s, got %s", name, v.Type(), kindName(n))
+		return
+	}
+	v.Set(p.Elem())
+}
@@ -0,0 +1,104 @@
+package layout
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"github.com/metaspartan/gotui/v5/widgets"
+)
+
+// Factory creates a widget bound to ctx.
+type Factory func(ctx *ui.Context) ui.Drawable
+
+// Registry maps widget type names used in layout files to factories.
+// Names are matched ignoring case, '_' and '-', so "line_gauge" and
+// "LineGauge" are the same type.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// DefaultRegistry holds the widgets of the widgets package.
+var DefaultRegistry = newDefaultRegistry()
+
+// Register adds a widget type to DefaultRegistry.
+func Register(name string, f Factory) {
+	DefaultRegistry.Register(name, f)
+}
+
+// Register adds a widget type, replacing any type of the same name.
+func (r *Registry) Register(name string, f Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[normalize(name)] = f
+}
+
+// New creates a widget of the named type.
+func (r *Registry) New(name string, ctx *ui.Context) (ui.Drawable, bool) {
+	r.mu.RLock()
+	f, ok := r.factories[normalize(name)]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return f(ctx), true
+}
+
+// Names returns the registered type names.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// normalize folds a name so that snake_case, kebab-case and CamelCase
+// spellings compare equal.
+func normalize(s string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(s))
+}
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	add := func(name string, f Factory) { r.Register(name, f) }
+	add("block", func(ctx *ui.Context) ui.Drawable { return ui.NewBlockWithContext(ctx) })
+	add("canvas", func(ctx *ui.Context) ui.Drawable { return ui.NewCanvasWithContext(ctx) })
+	add("barchart", func(ctx *ui.Context) ui.Drawable { return widgets.NewBarChartWithContext(ctx) })
+	add("button", func(ctx *ui.Context) ui.Drawable { return widgets.NewButtonWithContext(ctx, "") })
+	add("calendar", func(ctx *ui.Context) ui.Drawable { return widgets.NewCalendarWithContext(ctx) })
+	add("checkbox", func(ctx *ui.Context) ui.Drawable { return widgets.NewCheckboxWithContext(ctx, "") })
+	add("funnelchart", func(ctx *ui.Context) ui.Drawable { return widgets.NewFunnelChartWithContext(ctx) })
+	add("gauge", func(ctx *ui.Context) ui.Drawable { return widgets.NewGaugeWithContext(ctx) })
+	add("heatmap", func(ctx *ui.Context) ui.Drawable { return widgets.NewHeatmapWithContext(ctx) })
+	add("input", func(ctx *ui.Context) ui.Drawable { return widgets.NewInputWithContext(ctx) })
+	add("linegauge", func(ctx *ui.Context) ui.Drawable { return widgets.NewLineGaugeWithContext(ctx) })
+	add("list", func(ctx *ui.Context) ui.Drawable { return widgets.NewListWithContext(ctx) })
+	add("logo", func(ctx *ui.Context) ui.Drawable { return widgets.NewLogoWithContext(ctx) })
+	add("markdown", func(ctx *ui.Context) ui.Drawable { return widgets.NewMarkdownWithContext(ctx) })
+	add("paragraph", func(ctx *ui.Context) ui.Drawable { return widgets.NewParagraphWithContext(ctx) })
+	add("piechart", func(ctx *ui.Context) ui.Drawable { return widgets.NewPieChartWithContext(ctx) })
+	add("plot", func(ctx *ui.Context) ui.Drawable { return widgets.NewPlotWithContext(ctx) })
+	add("radarchart", func(ctx *ui.Context) ui.Drawable { return widgets.NewRadarChartWithContext(ctx) })
+	add("scrollbar", func(ctx *ui.Context) ui.Drawable { return widgets.NewScrollbarWithContext(ctx) })
+	add("sparklines", func(ctx *ui.Context) ui.Drawable { return widgets.NewSparklineGroupWithContext(ctx) })
+	add("spinner", func(ctx *ui.Context) ui.Drawable { return widgets.NewSpinnerWithContext(ctx) })
+	add("stackedbarchart", func(ctx *ui.Context) ui.Drawable { return widgets.NewStackedBarChartWithContext(ctx) })
+	add("stepchart", func(ctx *ui.Context) ui.Drawable { return widgets.NewStepChartWithContext(ctx) })
+	add("table", func(ctx *ui.Context) ui.Drawable { return widgets.NewTableWithContext(ctx) })
+	add("tabs", func(ctx *ui.Context) ui.Drawable { return widgets.NewTabPaneWithContext(ctx) })
+	add("textarea", func(ctx *ui.Context) ui.Drawable { return widgets.NewTextAreaWithContext(ctx) })
+	add("tree", func(ctx *ui.Context) ui.Drawable { return widgets.NewTreeWithContext(ctx) })
+	add("treemap", func(ctx *ui.Context) ui.Drawable { return widgets.NewTreeMapWithContext(ctx) })
+	return r
+}
@@ -0,0 +1,46 @@
+package layout
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch reloads path with DefaultRegistry whenever it changes, see
+// Loader.Watch.
+func Watch(ctx context.Context, path string, interval time.Duration, fn func(*Document, error)) {
+	NewLoader().Watch(ctx, path, interval, fn)
+}
+
+// Watch polls path every interval until ctx is done and calls fn with the
+// reloaded document each time the file changes. Errors are passed to fn as
+// well, so a broken save can be reported and fixed without restarting.
+// Watch blocks; run it in its own goroutine.
+func (l *Loader) Watch(ctx context.Context, path string, interval time.Duration, fn func(*Document, error)) {
+	if interval <= 0 {
+		interval = 500 * time.Millisecond
+	}
+	last, _ := os.Stat(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			if last != nil {
+				fn(nil, err)
+			}
+			last = nil
+			continue
+		}
+		if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+			continue
+		}
+		last = info
+		fn(l.LoadFile(path))
+	}
+}
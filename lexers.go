@@ -0,0 +1,504 @@
+package gotui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The built-in lexers. They are registered with LexerFor under their
+// language names and file extensions.
+var (
+	GoLexer    Lexer = LexerFunc(lexGo)
+	JSONLexer  Lexer = LexerFunc(lexJSON)
+	YAMLLexer  Lexer = LexerFunc(lexYAML)
+	ShellLexer Lexer = LexerFunc(lexShell)
+	DiffLexer  Lexer = LexerFunc(lexDiff)
+)
+
+// lineScanner collects the tokens of one line.
+type lineScanner struct {
+	src    string
+	pos    int
+	tokens []Token
+}
+
+func (s *lineScanner) done() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *lineScanner) peek(offset int) byte {
+	if s.pos+offset < len(s.src) {
+		return s.src[s.pos+offset]
+	}
+	return 0
+}
+
+// emit adds the next n bytes as a token, merging it with the one before
+// when they are of the same kind.
+func (s *lineScanner) emit(kind TokenKind, n int) {
+	n = min(n, len(s.src)-s.pos)
+	if n <= 0 {
+		return
+	}
+	text := s.src[s.pos : s.pos+n]
+	s.pos += n
+	if last := len(s.tokens) - 1; last >= 0 && s.tokens[last].Kind == kind {
+		s.tokens[last].Text += text
+		return
+	}
+	s.tokens = append(s.tokens, Token{Kind: kind, Text: text})
+}
+
+// emitRest adds the rest of the line as one token.
+func (s *lineScanner) emitRest(kind TokenKind) {
+	s.emit(kind, len(s.src)-s.pos)
+}
+
+// emitRune adds the next rune as a token.
+func (s *lineScanner) emitRune(kind TokenKind) {
+	_, size := utf8.DecodeRuneInString(s.src[s.pos:])
+	s.emit(kind, size)
+}
+
+// span returns the length of the run of bytes from pos that match f.
+func (s *lineScanner) span(f func(byte) bool) int {
+	n := 0
+	for s.pos+n < len(s.src) && f(s.src[s.pos+n]) {
+		n++
+	}
+	return n
+}
+
+// quoted returns the length of the string starting with the quote at pos
+// and whether it is closed on this line. Backslashes escape when escapes
+// is set.
+func (s *lineScanner) quoted(quote byte, escapes bool) (int, bool) {
+	return s.closing(quote, escapes, s.pos+1)
+}
+
+// closing returns the length from pos to the quote that closes a string,
+// looking from i on, and whether there is one on this line.
+func (s *lineScanner) closing(quote byte, escapes bool, i int) (int, bool) {
+	for ; i < len(s.src); i++ {
+		switch {
+		case escapes && s.src[i] == '\\':
+			i++
+		case s.src[i] == quote:
+			return i + 1 - s.pos, true
+		}
+	}
+	return len(s.src) - s.pos, false
+}
+
+// number returns the length of the number at pos, or 0.
+func (s *lineScanner) number() int {
+	return numberLen(s.src[s.pos:])
+}
+
+// numberLen returns the length of the number at the start of s, or 0. It
+// takes digits, letters for bases and exponents, dots and the sign after
+// an exponent.
+func numberLen(s string) int {
+	if s == "" || !isDigit(s[0]) && !(s[0] == '.' && len(s) > 1 && isDigit(s[1])) {
+		return 0
+	}
+	n := 0
+	for ; n < len(s); n++ {
+		c := s[n]
+		switch {
+		case isDigit(c) || isLetter(c) || c == '.':
+		case (c == '+' || c == '-') && strings.IndexByte("eEpP", s[n-1]) >= 0:
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// identifier returns the length of the identifier at pos, which may hold
+// letters of any script.
+func (s *lineScanner) identifier() int {
+	n := 0
+	for s.pos+n < len(s.src) {
+		r, size := utf8.DecodeRuneInString(s.src[s.pos+n:])
+		if !(r == '_' || unicode.IsLetter(r) || n > 0 && unicode.IsDigit(r)) {
+			break
+		}
+		n += size
+	}
+	return n
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+var (
+	goKeywords = wordSet(`break case chan const continue default defer else fallthrough for func go goto
+		if import interface map package range return select struct switch type var`)
+	goTypes = wordSet(`any bool byte comparable complex64 complex128 error float32 float64 int int8 int16
+		int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr`)
+	goBuiltins = wordSet(`append cap clear close complex copy delete false imag iota len make max min new
+		nil panic print println real recover true`)
+)
+
+// Go lexer states.
+const (
+	goCode LexState = iota
+	goBlockComment
+	goRawString
+)
+
+func lexGo(line string, state LexState) ([]Token, LexState) {
+	s := &lineScanner{src: line}
+	for !s.done() {
+		switch state {
+		case goBlockComment:
+			end := strings.Index(line[s.pos:], "*/")
+			if end < 0 {
+				s.emitRest(TokenComment)
+				continue
+			}
+			s.emit(TokenComment, end+2)
+			state = goCode
+			continue
+		case goRawString:
+			end := strings.IndexByte(line[s.pos:], '`')
+			if end < 0 {
+				s.emitRest(TokenString)
+				continue
+			}
+			s.emit(TokenString, end+1)
+			state = goCode
+			continue
+		}
+		c := s.peek(0)
+		switch {
+		case isSpace(c):
+			s.emit(TokenText, s.span(isSpace))
+		case c == '/' && s.peek(1) == '/':
+			s.emitRest(TokenComment)
+		case c == '/' && s.peek(1) == '*':
+			s.emit(TokenComment, 2)
+			state = goBlockComment
+		case c == '`':
+			s.emit(TokenString, 1)
+			state = goRawString
+		case c == '"' || c == '\'':
+			n, _ := s.quoted(c, true)
+			s.emit(TokenString, n)
+		case s.number() > 0:
+			s.emit(TokenNumber, s.number())
+		case s.identifier() > 0:
+			n := s.identifier()
+			word := line[s.pos : s.pos+n]
+			kind := TokenText
+			switch {
+			case goKeywords[word]:
+				kind = TokenKeyword
+			case goTypes[word]:
+				kind = TokenType
+			case goBuiltins[word]:
+				kind = TokenBuiltin
+			}
+			s.emit(kind, n)
+		case strings.IndexByte("+-*/%&|^<>=!:.,;()[]{}~", c) >= 0:
+			s.emit(TokenOperator, 1)
+		default:
+			s.emitRune(TokenText)
+		}
+	}
+	return s.tokens, state
+}
+
+// lexJSON lexes JSON, telling the keys of objects from other strings by
+// the colon after them.
+func lexJSON(line string, state LexState) ([]Token, LexState) {
+	s := &lineScanner{src: line}
+	for !s.done() {
+		c := s.peek(0)
+		switch {
+		case isSpace(c):
+			s.emit(TokenText, s.span(isSpace))
+		case c == '"':
+			n, _ := s.quoted('"', true)
+			kind := TokenString
+			rest := strings.TrimLeft(line[s.pos+n:], " \t")
+			if strings.HasPrefix(rest, ":") {
+				kind = TokenKey
+			}
+			s.emit(kind, n)
+		case c == '-' || isDigit(c):
+			n := 0
+			if c == '-' {
+				n = 1
+			}
+			s.emit(TokenNumber, n+numberLen(line[s.pos+n:]))
+		case isLetter(c):
+			n := s.span(isLetter)
+			kind := TokenText
+			switch line[s.pos : s.pos+n] {
+			case "true", "false", "null":
+				kind = TokenKeyword
+			}
+			s.emit(kind, n)
+		case strings.IndexByte("{}[]:,", c) >= 0:
+			s.emit(TokenOperator, 1)
+		default:
+			s.emitRune(TokenText)
+		}
+	}
+	return s.tokens, state
+}
+
+var yamlKeywords = wordSet(`true false yes no on off null True False TRUE FALSE Yes No Null NULL ~`)
+
+// lexYAML lexes YAML. A state above zero is inside a block scalar whose
+// lines are indented by at least that many columns.
+func lexYAML(line string, state LexState) ([]Token, LexState) {
+	s := &lineScanner{src: line}
+	indent := s.span(isSpace)
+	if state > 0 {
+		if strings.TrimSpace(line) == "" || indent >= int(state) {
+			s.emitRest(TokenString)
+			return s.tokens, state
+		}
+		state = 0
+	}
+	s.emit(TokenText, indent)
+	switch {
+	case strings.HasPrefix(line, "---") || strings.HasPrefix(line, "..."):
+		s.emit(TokenHeading, 3)
+		s.yamlValue()
+		return s.tokens, state
+	case strings.HasPrefix(line[s.pos:], "#"):
+		s.emitRest(TokenComment)
+		return s.tokens, state
+	}
+	// Sequence entries, which may hold a mapping on the same line.
+	for s.peek(0) == '-' && (isSpace(s.peek(1)) || s.pos+1 == len(line)) {
+		s.emit(TokenOperator, 1)
+		s.emit(TokenText, s.span(isSpace))
+	}
+	if n := yamlKey(line[s.pos:]); n > 0 {
+		s.emit(TokenKey, n)
+		s.emit(TokenOperator, 1)
+	}
+	if s.yamlValue() {
+		state = LexState(indent + 1)
+	}
+	return s.tokens, state
+}
+
+// yamlKey returns the length of the mapping key at the start of s, or 0.
+func yamlKey(s string) int {
+	if s == "" || strings.IndexByte("[{#&*!|>%@`", s[0]) >= 0 {
+		return 0
+	}
+	if s[0] == '"' || s[0] == '\'' {
+		sc := &lineScanner{src: s}
+		n, ok := sc.quoted(s[0], s[0] == '"')
+		if ok && strings.HasPrefix(strings.TrimLeft(s[n:], " "), ":") {
+			return n + len(s[n:]) - len(strings.TrimLeft(s[n:], " "))
+		}
+		return 0
+	}
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == ':' && (i+1 == len(s) || isSpace(s[i+1])):
+			return i
+		case s[i] == '#' && i > 0 && isSpace(s[i-1]):
+			return 0
+		}
+	}
+	return 0
+}
+
+// yamlValue lexes the value after a key or a sequence entry and reports
+// whether it starts a block scalar.
+func (s *lineScanner) yamlValue() bool {
+	s.emit(TokenText, s.span(isSpace))
+	c := s.peek(0)
+	switch {
+	case s.done():
+		return false
+	case c == '#':
+		s.emitRest(TokenComment)
+		return false
+	case c == '|' || c == '>':
+		s.emit(TokenOperator, s.span(func(c byte) bool { return !isSpace(c) }))
+		s.yamlComment()
+		return true
+	case c == '&' || c == '*':
+		s.emit(TokenVariable, s.span(func(c byte) bool { return !isSpace(c) }))
+		return s.yamlValue()
+	case c == '!':
+		s.emit(TokenType, s.span(func(c byte) bool { return !isSpace(c) }))
+		return s.yamlValue()
+	}
+	for !s.done() {
+		c := s.peek(0)
+		switch {
+		case isSpace(c):
+			s.emit(TokenText, s.span(isSpace))
+			if s.peek(0) == '#' {
+				s.emitRest(TokenComment)
+			}
+		case c == '"' || c == '\'':
+			n, _ := s.quoted(c, c == '"')
+			s.emit(TokenString, n)
+		case strings.IndexByte("[]{},", c) >= 0:
+			s.emit(TokenOperator, 1)
+		default:
+			n := s.span(func(c byte) bool { return !isSpace(c) && strings.IndexByte("[]{},", c) < 0 })
+			word := s.src[s.pos : s.pos+n]
+			kind := TokenString
+			switch {
+			case yamlKeywords[word]:
+				kind = TokenKeyword
+			case numberLen(strings.TrimPrefix(word, "-")) == len(strings.TrimPrefix(word, "-")):
+				kind = TokenNumber
+			}
+			s.emit(kind, n)
+		}
+	}
+	return false
+}
+
+func (s *lineScanner) yamlComment() {
+	s.emit(TokenText, s.span(isSpace))
+	s.emitRest(TokenComment)
+}
+
+var (
+	shellKeywords = wordSet(`if then else elif fi for while until do done case esac in function select
+		return break continue time`)
+	shellBuiltins = wordSet(`alias bg cd command declare echo eval exec exit export false fg getopts hash
+		jobs kill local printf pwd read readonly set shift source test trap true type ulimit umask
+		unalias unset wait`)
+)
+
+// Shell lexer states.
+const (
+	shellCode LexState = iota
+	shellDoubleQuote
+	shellSingleQuote
+)
+
+func isShellWord(c byte) bool {
+	return c > ' ' && strings.IndexByte("|&;<>()$`\\\"'= \t", c) < 0
+}
+
+func lexShell(line string, state LexState) ([]Token, LexState) {
+	s := &lineScanner{src: line}
+	for !s.done() {
+		if state != shellCode {
+			quote := byte('"')
+			if state == shellSingleQuote {
+				quote = '\''
+			}
+			n, closed := s.closing(quote, quote == '"', s.pos)
+			s.emit(TokenString, n)
+			if closed {
+				state = shellCode
+			}
+			continue
+		}
+		c := s.peek(0)
+		switch {
+		case isSpace(c):
+			s.emit(TokenText, s.span(isSpace))
+		case c == '#' && (s.pos == 0 || isSpace(line[s.pos-1])):
+			s.emitRest(TokenComment)
+		case c == '\\':
+			s.emit(TokenText, 2)
+		case c == '"' || c == '\'':
+			n, closed := s.quoted(c, c == '"')
+			s.emit(TokenString, n)
+			if !closed {
+				state = shellDoubleQuote
+				if c == '\'' {
+					state = shellSingleQuote
+				}
+			}
+		case c == '$' && s.peek(1) == '{':
+			n := strings.IndexByte(line[s.pos:], '}') + 1
+			if n == 0 {
+				n = len(line) - s.pos
+			}
+			s.emit(TokenVariable, n)
+		case c == '$' && s.peek(1) == '(':
+			s.emit(TokenOperator, 2)
+		case c == '$':
+			n := 1
+			if strings.IndexByte("@*#?$!-0123456789", s.peek(1)) >= 0 {
+				n = 2
+			} else if isLetter(s.peek(1)) {
+				for isLetter(s.peek(n)) || isDigit(s.peek(n)) {
+					n++
+				}
+			}
+			s.emit(TokenVariable, n)
+		case isShellWord(c):
+			n := s.span(isShellWord)
+			word := line[s.pos : s.pos+n]
+			kind := TokenText
+			switch {
+			case s.peek(n) == '=' && isLetter(word[0]):
+				kind = TokenVariable
+			case shellKeywords[word]:
+				kind = TokenKeyword
+			case shellBuiltins[word]:
+				kind = TokenBuiltin
+			case strings.Trim(word, "0123456789") == "":
+				kind = TokenNumber
+			}
+			s.emit(kind, n)
+		default:
+			s.emit(TokenOperator, 1)
+		}
+	}
+	return s.tokens, state
+}
+
+// lexDiff lexes unified diffs one line at a time.
+func lexDiff(line string, state LexState) ([]Token, LexState) {
+	s := &lineScanner{src: line}
+	switch {
+	case strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "--- "), strings.HasPrefix(line, "+++ "):
+		s.emitRest(TokenHeading)
+	case strings.HasPrefix(line, "@@"):
+		end := strings.Index(line[2:], "@@")
+		if end < 0 {
+			s.emitRest(TokenHeading)
+			break
+		}
+		s.emit(TokenHeading, end+4)
+		s.emitRest(TokenText)
+	case strings.HasPrefix(line, "+"):
+		s.emitRest(TokenInserted)
+	case strings.HasPrefix(line, "-"):
+		s.emitRest(TokenDeleted)
+	default:
+		s.emitRest(TokenText)
+	}
+	return s.tokens, state
+}
@@ -9,7 +9,7 @@ import (
 
 // Render renders the given drawables to the screen.
 func Render(items ...Drawable) {
-	DefaultBackend.Render(items...)
+	DefaultContext.Render(items...)
 }
 
 func (b *Backend) Render(items ...Drawable) {
@@ -28,8 +28,13 @@ func (b *Backend) Render(items ...Drawable) {
 	buf := NewBuffer(image.Rect(minX, minY, maxX, maxY))
 
 	for _, item := range items {
+		if IsHidden(item) {
+			continue
+		}
 		item.Lock()
+		buf.PushClip(item.GetRect())
 		item.Draw(buf)
+		buf.PopClip()
 		item.Unlock()
 	}
 
@@ -77,6 +82,7 @@ func (b *Backend) renderBuffer(buf *Buffer) {
 	screenW, screenH := b.Screen.Size()
 
 	for i, cell := range buf.Cells {
+		// Continuation cells are covered by the wide cell before them.
 		if cell.Rune == 0 {
 			continue
 		}
@@ -90,8 +96,8 @@ func (b *Backend) renderBuffer(buf *Buffer) {
 		}
 
 		style := tcell.StyleDefault.
-			Foreground(cell.Style.Fg).
-			Background(cell.Style.Bg).
+			Foreground(OpaqueColor(cell.Style.Fg)).
+			Background(OpaqueColor(cell.Style.Bg)).
 			Bold(cell.Style.Modifier&tcell.AttrBold != 0).
 			Reverse(cell.Style.Modifier&tcell.AttrReverse != 0).
 			Dim(cell.Style.Modifier&tcell.AttrDim != 0).
@@ -99,7 +105,11 @@ func (b *Backend) renderBuffer(buf *Buffer) {
 			Italic(cell.Style.Modifier&tcell.AttrItalic != 0).
 			StrikeThrough(cell.Style.Modifier&tcell.AttrStrikeThrough != 0)
 
-		b.Screen.SetContent(x, y, cell.Rune, nil, style)
+		if cell.Grapheme != "" {
+			b.Screen.Put(x, y, cell.Grapheme, style)
+		} else {
+			b.Screen.SetContent(x, y, cell.Rune, nil, style)
+		}
 	}
 	b.Screen.Show()
 }
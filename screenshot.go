@@ -24,7 +24,9 @@ func SaveImage(path string, width, height int, items ...Drawable) error {
 func Capture(width, height int, items ...Drawable) *image.RGBA {
 	buf := NewBuffer(image.Rect(0, 0, width, height))
 	for _, item := range items {
+		buf.PushClip(item.GetRect())
 		item.Draw(buf)
+		buf.PopClip()
 	}
 	return RenderBufferToImage(buf)
 }
@@ -132,7 +134,7 @@ func drawCell(img *image.RGBA, cell Cell, x, y, cw, ch, ascent int, fontFace, br
 			Face: face,
 			Dot:  fixed.P(px, py+asc),
 		}
-		drawer.DrawString(string(cell.Rune))
+		drawer.DrawString(cell.Text())
 	}
 }
 func resolveColors(s Style) (Color, Color) {
@@ -267,6 +269,7 @@ func drawBoxRounded(fill func(int, int, int, int), w, h int, r rune) {
 	}
 }
 func toNRGBA(c Color) color.NRGBA {
+	c = OpaqueColor(c)
 	if c == ColorClear {
 		return color.NRGBA{0, 0, 0, 255}
 	}
@@ -0,0 +1,382 @@
+package ssh
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	ui "github.com/metaspartan/gotui/v5"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// ErrServerClosed is returned by Serve after Shutdown or Close.
+var ErrServerClosed = errors.New("ssh: server closed")
+
+var errAuthFailed = errors.New("ssh: authentication failed")
+
+// Handler builds the application served to a session. Widgets should be
+// created with the session context, e.g. widgets.NewParagraphWithContext(sess.UI).
+type Handler func(*Session) *ui.Application
+
+// Server serves gotui applications over SSH, one Application per session.
+type Server struct {
+	Addr             string
+	Handler          Handler
+	HostSigners      []gossh.Signer
+	PasswordHandler  func(user, password string) bool
+	PublicKeyHandler func(user string, key gossh.PublicKey) bool
+	IdleTimeout      time.Duration
+	MaxSessions      int
+	Theme            ui.RootTheme
+
+	mu        sync.Mutex
+	closing   bool
+	active    int
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	sessions  map[*Session]struct{}
+	wg        sync.WaitGroup
+}
+
+// NewServer returns a new Server listening on addr.
+func NewServer(addr string, handler Handler) *Server {
+	return &Server{
+		Addr:      addr,
+		Handler:   handler,
+		Theme:     ui.DefaultTheme(),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+		sessions:  make(map[*Session]struct{}),
+	}
+}
+
+// GenerateHostKey returns a new in-memory ed25519 host key.
+func GenerateHostKey() (gossh.Signer, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return gossh.NewSignerFromKey(key)
+}
+
+// LoadHostKey reads a PEM encoded private key from path.
+func LoadHostKey(path string) (gossh.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return gossh.ParsePrivateKey(data)
+}
+
+// AddHostKey adds a host key to the server.
+func (s *Server) AddHostKey(signer gossh.Signer) {
+	s.HostSigners = append(s.HostSigners, signer)
+}
+
+// ListenAndServe listens on Addr and serves sessions.
+func (s *Server) ListenAndServe() error {
+	addr := s.Addr
+	if addr == "" {
+		addr = ":2222"
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l until the server is closed.
+func (s *Server) Serve(l net.Listener) error {
+	config, err := s.serverConfig()
+	if err != nil {
+		return err
+	}
+	if !s.trackListener(l, true) {
+		return ErrServerClosed
+	}
+	defer s.trackListener(l, false)
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosing() {
+				return ErrServerClosed
+			}
+			return err
+		}
+		go s.handleConn(conn, config)
+	}
+}
+
+// Shutdown stops accepting connections, stops every running application and
+// waits for the sessions to end or ctx to be done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closing = true
+	s.closeListenersLocked()
+	for sess := range s.sessions {
+		sess.Close()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	s.closeConns()
+	return err
+}
+
+// Close immediately closes all listeners and connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closing = true
+	s.closeListenersLocked()
+	for sess := range s.sessions {
+		sess.Close()
+	}
+	s.mu.Unlock()
+	s.closeConns()
+	return nil
+}
+
+// ActiveSessions returns the number of running sessions.
+func (s *Server) ActiveSessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
+
+func (s *Server) serverConfig() (*gossh.ServerConfig, error) {
+	if len(s.HostSigners) == 0 {
+		return nil, errors.New("ssh: no host keys configured")
+	}
+	config := &gossh.ServerConfig{
+		NoClientAuth: s.PasswordHandler == nil && s.PublicKeyHandler == nil,
+	}
+	if s.PasswordHandler != nil {
+		config.PasswordCallback = func(meta gossh.ConnMetadata, password []byte) (*gossh.Permissions, error) {
+			if s.PasswordHandler(meta.User(), string(password)) {
+				return nil, nil
+			}
+			return nil, errAuthFailed
+		}
+	}
+	if s.PublicKeyHandler != nil {
+		config.PublicKeyCallback = func(meta gossh.ConnMetadata, key gossh.PublicKey) (*gossh.Permissions, error) {
+			if s.PublicKeyHandler(meta.User(), key) {
+				return nil, nil
+			}
+			return nil, errAuthFailed
+		}
+	}
+	for _, signer := range s.HostSigners {
+		config.AddHostKey(signer)
+	}
+	return config, nil
+}
+
+func (s *Server) handleConn(conn net.Conn, config *gossh.ServerConfig) {
+	if !s.trackConn(conn, true) {
+		conn.Close()
+		return
+	}
+	defer s.trackConn(conn, false)
+	defer conn.Close()
+
+	sconn, chans, reqs, err := gossh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+	for newCh := range chans {
+		if newCh.ChannelType() != "session" {
+			_ = newCh.Reject(gossh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		if !s.acquire() {
+			_ = newCh.Reject(gossh.ResourceShortage, "too many sessions")
+			continue
+		}
+		go s.handleChannel(sconn, newCh)
+	}
+}
+
+func (s *Server) handleChannel(conn *gossh.ServerConn, newCh gossh.NewChannel) {
+	defer s.release()
+	ch, reqs, err := newCh.Accept()
+	if err != nil {
+		return
+	}
+	defer ch.Close()
+
+	sess := newSession(conn, ch)
+	defer sess.cancel()
+	if !s.trackSession(sess, true) {
+		return
+	}
+	defer s.trackSession(sess, false)
+	go func() {
+		for req := range reqs {
+			sess.handleRequest(req)
+		}
+		sess.cancel()
+	}()
+
+	select {
+	case <-sess.ready:
+	case <-sess.ctx.Done():
+		return
+	}
+	sendExitStatus(ch, s.runSession(sess))
+}
+
+func (s *Server) runSession(sess *Session) uint32 {
+	backend, err := ui.NewBackend(&ui.InitConfig{CustomTTY: sess.tty})
+	if err != nil {
+		fmt.Fprintln(sess.tty.ch.Stderr(), "gotui:", err)
+		return 1
+	}
+	sess.UI = ui.NewContext(backend)
+	*sess.UI.Theme = s.Theme.Clone()
+	app := s.Handler(sess)
+	if app == nil {
+		backend.Close()
+		return 1
+	}
+	app.Backend = backend
+	app.Context = sess.UI
+
+	if s.IdleTimeout > 0 {
+		go s.watchIdle(sess)
+	}
+	if err := app.RunWithContext(sess.ctx); err != nil {
+		fmt.Fprintln(sess.tty.ch.Stderr(), "gotui:", err)
+		return 1
+	}
+	return 0
+}
+
+func (s *Server) watchIdle(sess *Session) {
+	timer := time.NewTimer(s.IdleTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-sess.ctx.Done():
+			return
+		case <-timer.C:
+			idle := sess.tty.idle()
+			if idle >= s.IdleTimeout {
+				sess.Close()
+				return
+			}
+			timer.Reset(s.IdleTimeout - idle)
+		}
+	}
+}
+
+func (s *Server) acquire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing || (s.MaxSessions > 0 && s.active >= s.MaxSessions) {
+		return false
+	}
+	s.active++
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active--
+	s.wg.Done()
+}
+
+func (s *Server) trackSession(sess *Session, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.sessions, sess)
+		return true
+	}
+	if s.closing {
+		return false
+	}
+	if s.sessions == nil {
+		s.sessions = make(map[*Session]struct{})
+	}
+	s.sessions[sess] = struct{}{}
+	return true
+}
+
+func (s *Server) isClosing() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closing
+}
+
+func (s *Server) trackListener(l net.Listener, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.listeners, l)
+		return true
+	}
+	if s.closing {
+		return false
+	}
+	if s.listeners == nil {
+		s.listeners = make(map[net.Listener]struct{})
+	}
+	s.listeners[l] = struct{}{}
+	return true
+}
+
+func (s *Server) trackConn(c net.Conn, add bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.conns, c)
+		return true
+	}
+	if s.closing {
+		return false
+	}
+	if s.conns == nil {
+		s.conns = make(map[net.Conn]struct{})
+	}
+	s.conns[c] = struct{}{}
+	return true
+}
+
+func (s *Server) closeListenersLocked() {
+	for l := range s.listeners {
+		l.Close()
+	}
+}
+
+func (s *Server) closeConns() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.conns {
+		c.Close()
+	}
+}
+
+func sendExitStatus(ch gossh.Channel, status uint32) {
+	_, _ = ch.SendRequest("exit-status", false, gossh.Marshal(exitStatus{Status: status}))
+}
@@ -0,0 +1,110 @@
+package ssh
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	ui "github.com/metaspartan/gotui/v5"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Session describes a single interactive SSH session.
+type Session struct {
+	User       string
+	RemoteAddr net.Addr
+	Term       string
+	Environ    []string
+	UI         *ui.Context
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	tty    *sessionTTY
+	ready  chan struct{}
+	once   sync.Once
+}
+
+type ptyRequest struct {
+	Term     string
+	Columns  uint32
+	Rows     uint32
+	WidthPx  uint32
+	HeightPx uint32
+	Modes    string
+}
+
+type windowChangeRequest struct {
+	Columns  uint32
+	Rows     uint32
+	WidthPx  uint32
+	HeightPx uint32
+}
+
+type envRequest struct {
+	Name  string
+	Value string
+}
+
+type exitStatus struct {
+	Status uint32
+}
+
+func newSession(conn *gossh.ServerConn, ch gossh.Channel) *Session {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Session{
+		User:       conn.User(),
+		RemoteAddr: conn.RemoteAddr(),
+		ctx:        ctx,
+		cancel:     cancel,
+		tty:        newSessionTTY(ch),
+		ready:      make(chan struct{}),
+	}
+}
+
+// Context returns a context that is canceled when the session ends.
+func (s *Session) Context() context.Context {
+	return s.ctx
+}
+
+// WindowSize returns the current terminal size of the client.
+func (s *Session) WindowSize() (int, int) {
+	return s.tty.size()
+}
+
+// Close ends the session.
+func (s *Session) Close() {
+	s.cancel()
+}
+
+func (s *Session) start() {
+	s.once.Do(func() { close(s.ready) })
+}
+
+func (s *Session) handleRequest(req *gossh.Request) {
+	ok := true
+	switch req.Type {
+	case "pty-req":
+		var p ptyRequest
+		if ok = gossh.Unmarshal(req.Payload, &p) == nil; ok {
+			s.Term = p.Term
+			s.tty.setSize(int(p.Columns), int(p.Rows))
+		}
+	case "window-change":
+		var w windowChangeRequest
+		if ok = gossh.Unmarshal(req.Payload, &w) == nil; ok {
+			s.tty.setSize(int(w.Columns), int(w.Rows))
+		}
+	case "env":
+		var e envRequest
+		if ok = gossh.Unmarshal(req.Payload, &e) == nil; ok {
+			s.Environ = append(s.Environ, e.Name+"="+e.Value)
+		}
+	case "shell":
+		s.start()
+	default:
+		ok = false
+	}
+	if req.WantReply {
+		_ = req.Reply(ok, nil)
+	}
+}
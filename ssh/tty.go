@@ -0,0 +1,165 @@
+package ssh
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v3"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+var errDrained = errors.New("ssh: tty drained")
+
+// sessionTTY adapts an SSH channel to the tty interface expected by the backend.
+type sessionTTY struct {
+	ch       gossh.Channel
+	input    chan []byte
+	pending  []byte
+	closed   chan struct{}
+	lastRead atomic.Int64
+
+	mu       sync.Mutex
+	width    int
+	height   int
+	resizeCh chan<- bool
+	drainQ   chan struct{}
+	once     sync.Once
+}
+
+func newSessionTTY(ch gossh.Channel) *sessionTTY {
+	t := &sessionTTY{
+		ch:     ch,
+		input:  make(chan []byte),
+		closed: make(chan struct{}),
+		drainQ: make(chan struct{}),
+		width:  80,
+		height: 24,
+	}
+	t.touch()
+	go t.pump()
+	return t
+}
+
+func (t *sessionTTY) pump() {
+	defer close(t.input)
+	for {
+		chunk := make([]byte, 256)
+		n, err := t.ch.Read(chunk)
+		if n > 0 {
+			t.touch()
+			select {
+			case t.input <- chunk[:n]:
+			case <-t.closed:
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (t *sessionTTY) touch() {
+	t.lastRead.Store(time.Now().UnixNano())
+}
+
+func (t *sessionTTY) idle() time.Duration {
+	return time.Since(time.Unix(0, t.lastRead.Load()))
+}
+
+func (t *sessionTTY) setSize(w, h int) {
+	t.mu.Lock()
+	if w > 0 {
+		t.width = w
+	}
+	if h > 0 {
+		t.height = h
+	}
+	ch := t.resizeCh
+	t.mu.Unlock()
+	if ch != nil {
+		select {
+		case ch <- true:
+		default:
+		}
+	}
+}
+
+func (t *sessionTTY) size() (int, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.width, t.height
+}
+
+func (t *sessionTTY) drained() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.drainQ
+}
+
+func (t *sessionTTY) Read(p []byte) (int, error) {
+	if len(t.pending) == 0 {
+		select {
+		case data, ok := <-t.input:
+			if !ok {
+				return 0, io.EOF
+			}
+			t.pending = data
+		case <-t.drained():
+			return 0, errDrained
+		}
+	}
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+func (t *sessionTTY) Write(p []byte) (int, error) {
+	return t.ch.Write(p)
+}
+
+func (t *sessionTTY) Start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.drainQ:
+		t.drainQ = make(chan struct{})
+	default:
+	}
+	return nil
+}
+
+func (t *sessionTTY) Stop() error {
+	return nil
+}
+
+func (t *sessionTTY) Drain() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.drainQ:
+	default:
+		close(t.drainQ)
+	}
+	return nil
+}
+
+func (t *sessionTTY) NotifyResize(ch chan<- bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resizeCh = ch
+}
+
+func (t *sessionTTY) WindowSize() (tcell.WindowSize, error) {
+	w, h := t.size()
+	return tcell.WindowSize{Width: w, Height: h}, nil
+}
+
+// Close stops the input pump. The channel itself is owned by the server.
+func (t *sessionTTY) Close() error {
+	t.once.Do(func() { close(t.closed) })
+	return nil
+}
@@ -2,8 +2,12 @@ package gotui
 
 import (
 	"fmt"
+	"image"
+	"math"
 )
 
+// InterpolateColor returns the color at step of steps from c1 to c2,
+// blended in RGB. Use MixColors to blend in other color spaces.
 func InterpolateColor(c1, c2 Color, step, steps int) Color {
 	if steps <= 1 {
 		return c1
@@ -110,3 +114,85 @@ func GenerateMultiGradient(length int, colors ...Color) []Color {
 
 	return result
 }
+
+// stops returns the colors that the gradient runs through.
+func (g Gradient) stops() []Color {
+	if len(g.Stops) > 0 {
+		return g.Stops
+	}
+	return []Color{g.Start, g.End}
+}
+
+// At returns the color at t, from 0 at the start of the gradient to 1 at
+// its end.
+func (g Gradient) At(t float64) Color {
+	stops := g.stops()
+	if len(stops) == 1 {
+		return stops[0]
+	}
+	segments := len(stops) - 1
+	pos := math.Max(0, math.Min(1, t)) * float64(segments)
+	i := min(int(pos), segments-1)
+	return MixColors(stops[i], stops[i+1], pos-float64(i), g.Space)
+}
+
+// Colors returns length colors spread evenly over the gradient.
+func (g Gradient) Colors(length int) []Color {
+	if length <= 0 {
+		return []Color{}
+	}
+	colors := make([]Color, length)
+	for i := range colors {
+		colors[i] = g.At(fraction(i, length))
+	}
+	return colors
+}
+
+// ApplyToText colors each rune of text along the gradient.
+func (g Gradient) ApplyToText(text string) []Cell {
+	runes := []rune(text)
+	colors := g.Colors(len(runes))
+	cells := make([]Cell, len(runes))
+	for i, r := range runes {
+		cells[i] = Cell{Rune: r, Style: NewStyle(colors[i])}
+	}
+	return cells
+}
+
+// ColorAt returns the color of the cell at p when the gradient fills area
+// in the shape given by Direction. GradientValue is taken as horizontal.
+func (g Gradient) ColorAt(p image.Point, area image.Rectangle) Color {
+	x, y := p.X-area.Min.X, p.Y-area.Min.Y
+	var t float64
+	switch g.Direction {
+	case GradientVertical:
+		t = fraction(y, area.Dy())
+	case GradientDiagonal:
+		t = (fraction(x, area.Dx()) + fraction(y, area.Dy())) / 2
+	case GradientAntiDiagonal:
+		t = (fraction(x, area.Dx()) + 1 - fraction(y, area.Dy())) / 2
+	case GradientRadial, GradientConic:
+		// Offsets from the center, scaled so that the edges are at 1.
+		hw, hh := float64(area.Dx())/2, float64(area.Dy())/2
+		dx, dy := (float64(x)+0.5-hw)/hw, (float64(y)+0.5-hh)/hh
+		if g.Direction == GradientRadial {
+			t = math.Hypot(dx, dy)
+			break
+		}
+		t = math.Atan2(dx, -dy) / (2 * math.Pi)
+		if t < 0 {
+			t++
+		}
+	default:
+		t = fraction(x, area.Dx())
+	}
+	return g.At(t)
+}
+
+// fraction returns how far i is along n cells, from 0 to 1.
+func fraction(i, n int) float64 {
+	if n <= 1 {
+		return 0
+	}
+	return float64(i) / float64(n-1)
+}
@@ -1,6 +1,8 @@
 package gotui
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/gdamore/tcell/v3"
@@ -18,14 +20,6 @@ const (
 	tokenEndStyle        = ')'
 )
 
-type parserState uint
-
-const (
-	parserStateDefault parserState = iota
-	parserStateStyleItems
-	parserStateStyledText
-)
-
 var StyleParserColorMap = map[string]Color{
 	"red":        ColorRed,
 	"blue":       ColorBlue,
@@ -86,103 +80,309 @@ var modifierMap = map[string]Modifier{
 	// NOTE: "underline" was removed in tcell v3
 }
 
-func readStyle(runes []rune, defaultStyle Style) Style {
-	style := defaultStyle
-	split := strings.SplitSeq(string(runes), tokenItemSeparator)
-	for item := range split {
-		pair := strings.Split(item, tokenValueSeparator)
-		if len(pair) == 2 {
-			switch pair[0] {
-			case tokenFg:
-				if c, ok := StyleParserColorMap[pair[1]]; ok {
-					style.Fg = c
-				} else {
-					style.Fg = tcell.GetColor(pair[1])
-				}
-			case tokenBg:
-				if c, ok := StyleParserColorMap[pair[1]]; ok {
-					style.Bg = c
-				} else {
-					style.Bg = tcell.GetColor(pair[1])
-				}
-			case tokenModifier:
-				style.Modifier = modifierMap[pair[1]]
+func lookupColor(name string) Color {
+	if c, ok := StyleParserColorMap[name]; ok {
+		return c
+	}
+	return tcell.GetColor(name)
+}
+
+// ParseColor returns the color called name, which is one of the names in
+// StyleParserColorMap, a W3C color name, "#rrggbb", "#rgb" or
+// "rgb(r, g, b)" with components from 0 to 255. "#rrggbbaa" gives the
+// color an alpha, see WithAlpha.
+func ParseColor(name string) (Color, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if rgb, ok := strings.CutPrefix(name, "rgb("); ok {
+		return parseRGB(name, rgb)
+	}
+	if len(name) == 9 && name[0] == '#' {
+		a, err := strconv.ParseUint(name[7:], 16, 8)
+		if err != nil {
+			return ColorClear, fmt.Errorf("invalid alpha in color %q", name)
+		}
+		c, err := ParseColor(name[:7])
+		return WithAlpha(c, float64(a)/255), err
+	}
+	if len(name) == 4 && name[0] == '#' {
+		name = string([]byte{'#', name[1], name[1], name[2], name[2], name[3], name[3]})
+	}
+	c := lookupColor(name)
+	if c == tcell.ColorDefault && name != "default" && name != "clear" {
+		return c, fmt.Errorf("unknown color %q", name)
+	}
+	return c, nil
+}
+
+func parseRGB(name, rgb string) (Color, error) {
+	parts := strings.Split(strings.TrimSuffix(rgb, ")"), ",")
+	if !strings.HasSuffix(rgb, ")") || len(parts) != 3 {
+		return ColorClear, fmt.Errorf("invalid color %q, want rgb(r, g, b)", name)
+	}
+	var v [3]int32
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || n > 255 {
+			return ColorClear, fmt.Errorf("invalid color %q, components go from 0 to 255", name)
+		}
+		v[i] = int32(n)
+	}
+	return NewRGBColor(v[0], v[1], v[2]), nil
+}
+
+// ParseStyle parses a style written as in markup, e.g. "fg:red,bg:black,
+// mod:bold". Several modifiers can be joined with '|'. Unlike markup read
+// by ParseStyles, unknown keys, colors and modifiers are reported as errors.
+func ParseStyle(s string, defaultStyle Style) (Style, error) {
+	return readStyle(s, defaultStyle, true)
+}
+
+// readStyle applies the items of s to style. When strict is false, items
+// that do not parse are skipped instead of reported.
+func readStyle(s string, style Style, strict bool) (Style, error) {
+	for _, item := range splitStyleItems(s) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, tokenValueSeparator)
+		var err error
+		switch {
+		case !ok:
+			err = fmt.Errorf("style item %q is not key:value", item)
+		case strings.TrimSpace(key) == tokenFg:
+			var c Color
+			if c, err = ParseColor(value); err == nil {
+				style.Fg = c
+			}
+		case strings.TrimSpace(key) == tokenBg:
+			var c Color
+			if c, err = ParseColor(value); err == nil {
+				style.Bg = c
+			}
+		case strings.TrimSpace(key) == tokenModifier:
+			var m Modifier
+			if m, err = parseModifiers(value); err == nil {
+				style.Modifier = m
+			}
+		default:
+			err = fmt.Errorf("unknown style key %q", key)
+		}
+		if err != nil && strict {
+			return style, err
+		}
+	}
+	return style, nil
+}
+
+// splitStyleItems splits s on the commas outside parentheses, so that
+// "fg:rgb(1,2,3),mod:bold" has two items.
+func splitStyleItems(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, s[start:i])
+				start = i + 1
 			}
 		}
 	}
-	return style
+	return append(items, s[start:])
+}
+
+func parseModifiers(s string) (Modifier, error) {
+	var mod Modifier
+	for name := range strings.SplitSeq(s, "|") {
+		m, ok := modifierMap[strings.TrimSpace(name)]
+		if !ok {
+			return mod, fmt.Errorf("unknown modifier %q", name)
+		}
+		mod |= m
+	}
+	return mod, nil
+}
+
+// Markup is text with styled spans, read by ParseStyles and ParseMarkup.
+// A span is written [text](style), with style read as for ParseStyle. The
+// text of a span may hold more spans, which start from its style:
+//
+//	[status: [ok](fg:green,mod:bold|italic) since 9:00](fg:#888888)
+//
+// A style in brackets on its own opens a span that runs to the next [/],
+// or to the end of the span or text around it:
+//
+//	[fg:rgb(255,136,0),mod:bold]warning[/] and plain text
+//
+// A backslash makes the [, ], (, ) or \ after it literal. Brackets that do
+// not form markup, as in "[1] first", are kept as text.
+
+const tokenEscape = '\\'
+
+type markupParser struct {
+	src    []rune
+	pos    int
+	strict bool
 }
+
+// ParseStyles turns markup into cells styled from defaultStyle. Markup that
+// does not parse, and style items that are not understood, are left out of
+// the styling and kept as plain text; use ParseMarkup to have them reported.
 func ParseStyles(s string, defaultStyle Style) []Cell {
+	p := &markupParser{src: []rune(s)}
+	cells, _ := p.text(defaultStyle, len(p.src), false)
+	return groupGraphemes(cells)
+}
+
+// ParseMarkup is ParseStyles that reports unclosed brackets, [/] without
+// an open span and unknown style keys, colors and modifiers.
+func ParseMarkup(s string, defaultStyle Style) ([]Cell, error) {
+	p := &markupParser{src: []rune(s), strict: true}
+	cells, err := p.text(defaultStyle, len(p.src), false)
+	if err != nil {
+		return nil, err
+	}
+	return groupGraphemes(cells), nil
+}
+
+// EscapeMarkup escapes the characters of s that markup would read, so that
+// it shows as written.
+func EscapeMarkup(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if r == tokenBeginStyledText || r == tokenEndStyledText || r == tokenEscape {
+			sb.WriteRune(tokenEscape)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func (p *markupParser) errorf(pos int, format string, args ...any) error {
+	return fmt.Errorf("markup: column %d: %s", pos+1, fmt.Sprintf(format, args...))
+}
+
+func isMarkupToken(r rune) bool {
+	switch r {
+	case tokenBeginStyledText, tokenEndStyledText, tokenBeginStyle, tokenEndStyle, tokenEscape:
+		return true
+	}
+	return false
+}
+
+// text reads markup up to end in style. In a span opened by a bracketed
+// style, inTag is set and the text stops after the [/] closing it.
+func (p *markupParser) text(style Style, end int, inTag bool) ([]Cell, error) {
 	cells := []Cell{}
-	runes := []rune(s)
-	state := parserStateDefault
-	styledText := []rune{}
-	styleItems := []rune{}
-	squareCount := 0
-	reset := func() {
-		styledText = []rune{}
-		styleItems = []rune{}
-		state = parserStateDefault
-		squareCount = 0
-	}
-	rollback := func() {
-		cells = append(cells, RunesToStyledCells(styledText, defaultStyle)...)
-		cells = append(cells, RunesToStyledCells(styleItems, defaultStyle)...)
-		reset()
-	}
-	chop := func(s []rune) []rune {
-		return s[1 : len(s)-1]
-	}
-	for i, _rune := range runes {
-		switch state {
-		case parserStateDefault:
-			if _rune == tokenBeginStyledText {
-				state = parserStateStyledText
-				squareCount = 1
-				styledText = append(styledText, _rune)
-			} else {
-				cells = append(cells, Cell{_rune, defaultStyle})
+	for p.pos < end {
+		r := p.src[p.pos]
+		switch {
+		case r == tokenEscape && p.pos+1 < end && isMarkupToken(p.src[p.pos+1]):
+			cells = append(cells, Cell{Rune: p.src[p.pos+1], Style: style})
+			p.pos += 2
+			continue
+		case r == tokenBeginStyledText && p.closingTag(end):
+			if inTag {
+				p.pos += 3
+				return cells, nil
+			}
+			if p.strict {
+				return nil, p.errorf(p.pos, "[/] without an open span")
 			}
-		case parserStateStyledText:
-			switch {
-			case squareCount == 0:
-				switch _rune {
-				case tokenBeginStyle:
-					state = parserStateStyleItems
-					styleItems = append(styleItems, _rune)
-				default:
-					rollback()
-					switch _rune {
-					case tokenBeginStyledText:
-						state = parserStateStyledText
-						squareCount = 1
-						styleItems = append(styleItems, _rune)
-					default:
-						cells = append(cells, Cell{_rune, defaultStyle})
-					}
-				}
-			case len(runes) == i+1:
-				rollback()
-				styledText = append(styledText, _rune)
-			case _rune == tokenBeginStyledText:
-				squareCount++
-				styledText = append(styledText, _rune)
-			case _rune == tokenEndStyledText:
-				squareCount--
-				styledText = append(styledText, _rune)
-			default:
-				styledText = append(styledText, _rune)
+		case r == tokenBeginStyledText:
+			span, ok, err := p.span(style, end)
+			if err != nil {
+				return nil, err
 			}
-		case parserStateStyleItems:
-			styleItems = append(styleItems, _rune)
-			if _rune == tokenEndStyle {
-				style := readStyle(chop(styleItems), defaultStyle)
-				cells = append(cells, RunesToStyledCells(chop(styledText), style)...)
-				reset()
-			} else if len(runes) == i+1 {
-				rollback()
+			if ok {
+				cells = append(cells, span...)
+				continue
+			}
+		}
+		cells = append(cells, Cell{Rune: r, Style: style})
+		p.pos++
+	}
+	return cells, nil
+}
+
+func (p *markupParser) closingTag(end int) bool {
+	return p.pos+2 < end && p.src[p.pos+1] == '/' && p.src[p.pos+2] == tokenEndStyledText
+}
+
+// span reads the span starting at the bracket at p.pos. It reports false,
+// leaving p.pos alone, when the bracket does not start markup.
+func (p *markupParser) span(style Style, end int) ([]Cell, bool, error) {
+	open := p.pos
+	close := p.matching(open, end, tokenBeginStyledText, tokenEndStyledText, true)
+	if close < 0 {
+		if p.strict {
+			return nil, false, p.errorf(open, "unclosed %c", tokenBeginStyledText)
+		}
+		return nil, false, nil
+	}
+	if close+1 < end && p.src[close+1] == tokenBeginStyle {
+		paren := p.matching(close+1, end, tokenBeginStyle, tokenEndStyle, false)
+		if paren < 0 {
+			if p.strict {
+				return nil, false, p.errorf(close+1, "unclosed %c", tokenBeginStyle)
+			}
+			return nil, false, nil
+		}
+		s, err := readStyle(string(p.src[close+2:paren]), style, p.strict)
+		if err != nil {
+			return nil, false, p.errorf(close+2, "%v", err)
+		}
+		p.pos = open + 1
+		cells, err := p.text(s, close, false)
+		if err != nil {
+			return nil, false, err
+		}
+		p.pos = paren + 1
+		return cells, true, nil
+	}
+	spec := string(p.src[open+1 : close])
+	if !strings.Contains(spec, tokenValueSeparator) {
+		return nil, false, nil
+	}
+	s, err := readStyle(spec, style, true)
+	if err != nil {
+		// Text such as "[note: see below]" is not markup, but a known
+		// key means a style with a mistake in it.
+		key, _, _ := strings.Cut(spec, tokenValueSeparator)
+		switch strings.TrimSpace(key) {
+		case tokenFg, tokenBg, tokenModifier:
+			if p.strict {
+				return nil, false, p.errorf(open+1, "%v", err)
+			}
+		}
+		return nil, false, nil
+	}
+	p.pos = close + 1
+	cells, err := p.text(s, end, true)
+	return cells, true, err
+}
+
+// matching returns the index of the bracket that closes the one at open,
+// or -1.
+func (p *markupParser) matching(open, end int, begin, finish rune, escapes bool) int {
+	depth := 0
+	for i := open; i < end; i++ {
+		switch r := p.src[i]; {
+		case escapes && r == tokenEscape && i+1 < end && isMarkupToken(p.src[i+1]):
+			i++
+		case r == begin:
+			depth++
+		case r == finish:
+			depth--
+			if depth == 0 {
+				return i
 			}
 		}
 	}
-	return cells
+	return -1
 }
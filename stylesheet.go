@@ -0,0 +1,663 @@
+package gotui
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Stylesheets style widgets with CSS-like rules:
+//
+//	/* the titles of all widgets */
+//	Block > title { fg: cyan; bold }
+//	Table.alert > header { fg: red; bold }
+//	#log > border, List:focused > border { fg: yellow }
+//	Tabs > tab:active { fg: black; bg: green }
+//	List > selected { mod: reverse }
+//
+// A selector is a chain of compounds joined by spaces (descendant) or ">"
+// (child). A compound matches a widget by its Go type name, or the name of
+// a type it embeds such as Block, by "#id" against Block.ID, by ".class"
+// against Block.Classes and by ":state" against its states; "*" matches
+// any widget. The states of a widget are the ones set with SetStyleState,
+// the ones it reports through Stateful and "focused" for the focused
+// widget of an Application.
+//
+// A selector may end in "> part" or "> part:state", written in lower case,
+// which names the style field the rule sets: part and state are joined and
+// looked up as a Style or Color field, so "title" sets TitleStyle,
+// "row:selected" sets SelectedRowStyle and "background" sets
+// BackgroundColor. Widgets with parts that are not fields implement
+// PartStyler. Without a part, rules set TextStyle or TextColor.
+//
+// Declarations are "fg" (or "color"), "bg" (or "background"), "mod" with
+// the full set of modifiers such as "bold|italic" or "none", and bare
+// modifier names such as "bold" which add to the set. Parts that are a
+// single color take either fg or bg.
+//
+// When several rules set the same field, the one with the most specific
+// selector wins: IDs count over classes and states, which count over types
+// and parts. Ties go to the later rule. A field goes back to the value it
+// had before when no rule sets it any more, unless it has been changed by
+// hand in between.
+
+// Stylesheet is a parsed list of style rules. Set Context.Stylesheet to
+// have it applied before every render.
+type Stylesheet struct {
+	rules []styleRule
+}
+
+// PartStyler is implemented by widgets with style parts that are not
+// fields, such as the header of a Table. part is the lower case part name
+// with its state, if any, in front: "header" or "selectedheader".
+type PartStyler interface {
+	PartStyle(part string) (Style, bool)
+	SetPartStyle(part string, s Style)
+}
+
+type styleRule struct {
+	selectors []styleSelector
+	patch     stylePatch
+}
+
+type styleSelector struct {
+	compounds []styleCompound
+	// combinators[i] joins compounds[i] and compounds[i+1]: ' ' or '>'.
+	combinators []byte
+	part        string
+	spec        [3]int
+}
+
+type styleCompound struct {
+	typ     string
+	id      string
+	classes []string
+	states  []string
+}
+
+// stylePatch holds the declarations of a rule.
+type stylePatch struct {
+	fg, bg       Color
+	setFg, setBg bool
+	mod, addMod  Modifier
+	setMod       bool
+}
+
+// styledField records a field set by a stylesheet: base is the value it
+// had before and applied the value the stylesheet gave it.
+type styledField struct {
+	target  styleTarget
+	base    Style
+	applied Style
+}
+
+// styleTarget reads and writes a Style or Color field as a Style; a Color
+// is kept in Fg.
+type styleTarget struct {
+	color bool
+	get   func() Style
+	set   func(Style)
+}
+
+// styleElement is a widget being matched against selectors.
+type styleElement struct {
+	block  *Block
+	types  []string
+	states []string
+}
+
+// ParseStylesheet parses the text of a stylesheet.
+func ParseStylesheet(src string) (*Stylesheet, error) {
+	p := &styleParser{src: stripStyleComments(src)}
+	sheet := &Stylesheet{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return sheet, nil
+		}
+		rule, err := p.rule()
+		if err != nil {
+			return nil, err
+		}
+		sheet.rules = append(sheet.rules, rule)
+	}
+}
+
+// LoadStylesheet reads a stylesheet file.
+func LoadStylesheet(path string) (*Stylesheet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := ParseStylesheet(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return sheet, nil
+}
+
+// SetStyleState turns the named state on or off for selectors such as
+// "Block:warning".
+func (b *Block) SetStyleState(name string, on bool) {
+	if on {
+		if b.states == nil {
+			b.states = make(map[string]bool)
+		}
+		b.states[name] = true
+	} else {
+		delete(b.states, name)
+	}
+}
+
+// HasStyleState reports whether the named state was set with SetStyleState.
+func (b *Block) HasStyleState(name string) bool {
+	return b.states[name]
+}
+
+// AddClass adds class to Classes unless it is there already.
+func (b *Block) AddClass(class string) {
+	if !slices.Contains(b.Classes, class) {
+		b.Classes = append(b.Classes, class)
+	}
+}
+
+// RemoveClass removes class from Classes.
+func (b *Block) RemoveClass(class string) {
+	b.Classes = slices.DeleteFunc(b.Classes, func(c string) bool { return c == class })
+}
+
+// stylable gives the stylesheet the block of any widget that embeds one.
+func (b *Block) stylable() *Block {
+	return b
+}
+
+// Apply styles root and the widgets under it. focused, which may be nil,
+// gets the "focused" state.
+func (s *Stylesheet) Apply(root, focused Drawable) {
+	if s == nil || root == nil {
+		return
+	}
+	s.apply(root, focused, nil)
+}
+
+func (s *Stylesheet) apply(d, focused Drawable, path []*styleElement) {
+	d.Lock()
+	el := newStyleElement(d, focused != nil && d == focused)
+	path = append(path, el)
+	s.style(d, el, path)
+	var children []Drawable
+	if c, ok := d.(Container); ok {
+		children = c.Children()
+	}
+	d.Unlock()
+	for _, child := range children {
+		if child != nil {
+			s.apply(child, focused, path)
+		}
+	}
+}
+
+func newStyleElement(d Drawable, focused bool) *styleElement {
+	el := &styleElement{types: styleTypeNames(reflect.TypeOf(d))}
+	if b, ok := d.(interface{ stylable() *Block }); ok {
+		el.block = b.stylable()
+		for name, on := range el.block.states {
+			if on {
+				el.states = append(el.states, name)
+			}
+		}
+	}
+	if st, ok := d.(Stateful); ok {
+		el.states = append(el.states, st.StyleStates()...)
+	}
+	if focused {
+		el.states = append(el.states, "focused")
+	}
+	return el
+}
+
+var styleTypeCache sync.Map
+
+var drawableType = reflect.TypeFor[Drawable]()
+
+// styleTypeNames returns the name of t and of the widgets it embeds.
+func styleTypeNames(t reflect.Type) []string {
+	if names, ok := styleTypeCache.Load(t); ok {
+		return names.([]string)
+	}
+	var names []string
+	var walk func(reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Name() != "" {
+			names = append(names, t.Name())
+		}
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if f.Anonymous && (f.Type.Implements(drawableType) || reflect.PointerTo(f.Type).Implements(drawableType)) {
+				walk(f.Type)
+			}
+		}
+	}
+	walk(t)
+	styleTypeCache.Store(t, names)
+	return names
+}
+
+type styleMatch struct {
+	spec  [3]int
+	order int
+	patch stylePatch
+}
+
+// style sets the fields of one widget from the rules that match it.
+func (s *Stylesheet) style(d Drawable, el *styleElement, path []*styleElement) {
+	b := el.block
+	if b == nil {
+		return
+	}
+	matches := make(map[string][]styleMatch)
+	targets := make(map[string]styleTarget)
+	for i, rule := range s.rules {
+		for _, sel := range rule.selectors {
+			if !sel.matches(path) {
+				continue
+			}
+			key, t, ok := resolveStyleTarget(d, sel.part)
+			if !ok {
+				continue
+			}
+			targets[key] = t
+			matches[key] = append(matches[key], styleMatch{sel.spec, i, rule.patch})
+		}
+	}
+	for key, f := range b.styled {
+		if _, ok := matches[key]; !ok {
+			if f.target.get() == f.applied {
+				f.target.set(f.base)
+			}
+			delete(b.styled, key)
+		}
+	}
+	for key, ms := range matches {
+		t := targets[key]
+		cur := t.get()
+		f := b.styled[key]
+		if f == nil {
+			f = &styledField{base: cur}
+			if b.styled == nil {
+				b.styled = make(map[string]*styledField)
+			}
+			b.styled[key] = f
+		} else if cur != f.applied {
+			f.base = cur
+		}
+		f.target = t
+		slices.SortStableFunc(ms, func(a, b styleMatch) int {
+			if c := slices.Compare(a.spec[:], b.spec[:]); c != 0 {
+				return c
+			}
+			return a.order - b.order
+		})
+		v := f.base
+		for _, m := range ms {
+			v = m.patch.apply(v, t.color)
+		}
+		t.set(v)
+		f.applied = t.get()
+	}
+}
+
+// resolveStyleTarget finds the field that part names on d.
+func resolveStyleTarget(d Drawable, part string) (string, styleTarget, bool) {
+	name := part
+	if name == "" {
+		name = "text"
+	}
+	v := reflect.ValueOf(d)
+	if v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+		for _, suffix := range []string{"style", "color"} {
+			sf, ok := v.Type().FieldByNameFunc(func(n string) bool {
+				return strings.EqualFold(n, name+suffix)
+			})
+			if !ok || !sf.IsExported() {
+				continue
+			}
+			f := v.FieldByIndex(sf.Index)
+			switch f.Type() {
+			case themeStyleType:
+				return "field:" + sf.Name, styleTarget{
+					get: func() Style { return f.Interface().(Style) },
+					set: func(s Style) { f.Set(reflect.ValueOf(s)) },
+				}, true
+			case themeColorType:
+				return "field:" + sf.Name, styleTarget{
+					color: true,
+					get:   func() Style { return Style{Fg: f.Interface().(Color)} },
+					set:   func(s Style) { f.Set(reflect.ValueOf(s.Fg)) },
+				}, true
+			}
+		}
+	}
+	if p, ok := d.(PartStyler); ok && part != "" {
+		if _, ok := p.PartStyle(part); ok {
+			return "part:" + part, styleTarget{
+				get: func() Style { s, _ := p.PartStyle(part); return s },
+				set: func(s Style) { p.SetPartStyle(part, s) },
+			}, true
+		}
+	}
+	return "", styleTarget{}, false
+}
+
+func (p stylePatch) apply(s Style, color bool) Style {
+	if color {
+		if p.setFg {
+			s.Fg = p.fg
+		} else if p.setBg {
+			s.Fg = p.bg
+		}
+		return s
+	}
+	if p.setFg {
+		s.Fg = p.fg
+	}
+	if p.setBg {
+		s.Bg = p.bg
+	}
+	if p.setMod {
+		s.Modifier = p.mod
+	}
+	s.Modifier |= p.addMod
+	return s
+}
+
+// matches reports whether the selector matches the last element of path.
+func (sel *styleSelector) matches(path []*styleElement) bool {
+	return sel.matchAt(len(sel.compounds)-1, path, len(path)-1)
+}
+
+func (sel *styleSelector) matchAt(ci int, path []*styleElement, ei int) bool {
+	if !sel.compounds[ci].matches(path[ei]) {
+		return false
+	}
+	if ci == 0 {
+		return true
+	}
+	if sel.combinators[ci-1] == '>' {
+		return ei > 0 && sel.matchAt(ci-1, path, ei-1)
+	}
+	for j := ei - 1; j >= 0; j-- {
+		if sel.matchAt(ci-1, path, j) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *styleCompound) matches(el *styleElement) bool {
+	if c.typ != "" && c.typ != "*" && !slices.Contains(el.types, c.typ) {
+		return false
+	}
+	if c.id != "" && (el.block == nil || el.block.ID != c.id) {
+		return false
+	}
+	for _, class := range c.classes {
+		if el.block == nil || !slices.Contains(el.block.Classes, class) {
+			return false
+		}
+	}
+	for _, state := range c.states {
+		if !slices.Contains(el.states, state) {
+			return false
+		}
+	}
+	return true
+}
+
+// stripStyleComments blanks out /* */ comments, keeping newlines so that
+// error positions stay right.
+func stripStyleComments(src string) string {
+	b := []byte(src)
+	for i := 0; i+1 < len(b); i++ {
+		if b[i] != '/' || b[i+1] != '*' {
+			continue
+		}
+		end := strings.Index(src[i+2:], "*/")
+		stop := len(b)
+		if end >= 0 {
+			stop = i + 2 + end + 2
+		}
+		for j := i; j < stop; j++ {
+			if b[j] != '\n' {
+				b[j] = ' '
+			}
+		}
+		i = stop - 1
+	}
+	return string(b)
+}
+
+type styleParser struct {
+	src string
+	pos int
+}
+
+func (p *styleParser) errorf(pos int, format string, args ...any) error {
+	line := 1 + strings.Count(p.src[:pos], "\n")
+	col := pos - strings.LastIndexByte(p.src[:pos], '\n')
+	return fmt.Errorf("line %d:%d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+func (p *styleParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+// rule parses "selectors { declarations }".
+func (p *styleParser) rule() (styleRule, error) {
+	var rule styleRule
+	open := strings.IndexAny(p.src[p.pos:], "{}")
+	if open < 0 || p.src[p.pos+open] == '}' {
+		return rule, p.errorf(p.pos, "expected {")
+	}
+	open += p.pos
+	start := p.pos
+	for _, sel := range strings.Split(p.src[start:open], ",") {
+		s, err := p.selector(sel, start)
+		if err != nil {
+			return rule, err
+		}
+		rule.selectors = append(rule.selectors, s)
+		start += len(sel) + 1
+	}
+	end := strings.IndexAny(p.src[open+1:], "{}")
+	if end < 0 || p.src[open+1+end] == '{' {
+		return rule, p.errorf(open, "unclosed {")
+	}
+	end += open + 1
+	start = open + 1
+	for _, decl := range strings.Split(p.src[start:end], ";") {
+		if err := p.declaration(&rule.patch, decl, start); err != nil {
+			return rule, err
+		}
+		start += len(decl) + 1
+	}
+	p.pos = end + 1
+	return rule, nil
+}
+
+func isStyleIdent(c byte) bool {
+	return c == '-' || c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// selector parses one selector of a list; off is its position in src.
+func (p *styleParser) selector(s string, off int) (styleSelector, error) {
+	var sel styleSelector
+	i := 0
+	ident := func() string {
+		start := i
+		for i < len(s) && isStyleIdent(s[i]) {
+			i++
+		}
+		return s[start:i]
+	}
+	comb := byte(0)
+	for {
+		space := false
+		for i < len(s) && unicode.IsSpace(rune(s[i])) {
+			i++
+			space = true
+		}
+		if i >= len(s) {
+			break
+		}
+		if s[i] == '>' {
+			if comb == '>' || len(sel.compounds) == 0 {
+				return sel, p.errorf(off+i, "unexpected >")
+			}
+			comb = '>'
+			i++
+			continue
+		}
+		if len(sel.compounds) > 0 {
+			if comb == 0 && !space {
+				return sel, p.errorf(off+i, "unexpected %q", s[i])
+			}
+			if comb == 0 {
+				comb = ' '
+			}
+			sel.combinators = append(sel.combinators, comb)
+		}
+		comb = 0
+		var c styleCompound
+		start := i
+		for i < len(s) && !unicode.IsSpace(rune(s[i])) && s[i] != '>' {
+			at := i
+			switch s[i] {
+			case '*':
+				i++
+				c.typ = "*"
+			case '.', '#', ':':
+				kind := s[i]
+				i++
+				name := ident()
+				if name == "" {
+					return sel, p.errorf(off+at, "expected a name after %q", kind)
+				}
+				switch kind {
+				case '.':
+					c.classes = append(c.classes, name)
+				case '#':
+					c.id = name
+				default:
+					c.states = append(c.states, name)
+				}
+			default:
+				if i != start {
+					return sel, p.errorf(off+i, "unexpected %q", s[i])
+				}
+				if c.typ = ident(); c.typ == "" {
+					return sel, p.errorf(off+i, "unexpected %q", s[i])
+				}
+			}
+		}
+		if c.typ != "" && c.typ != "*" && unicode.IsLower(rune(c.typ[0])) {
+			prev := len(sel.combinators) - 1
+			if prev < 0 || sel.combinators[prev] != '>' || c.id != "" || len(c.classes) > 0 || len(c.states) > 1 {
+				return sel, p.errorf(off+start, "part %q must come last, after >, with at most one state", c.typ)
+			}
+			sel.part = strings.ToLower(strings.ReplaceAll(c.typ, "-", ""))
+			if len(c.states) == 1 {
+				sel.part = strings.ToLower(c.states[0]) + sel.part
+				sel.spec[1]++
+			}
+			sel.spec[2]++
+			sel.combinators = sel.combinators[:prev]
+			for i < len(s) && unicode.IsSpace(rune(s[i])) {
+				i++
+			}
+			if i < len(s) {
+				return sel, p.errorf(off+i, "part %q must come last", c.typ)
+			}
+			break
+		}
+		sel.compounds = append(sel.compounds, c)
+		if c.id != "" {
+			sel.spec[0]++
+		}
+		sel.spec[1] += len(c.classes) + len(c.states)
+		if c.typ != "" && c.typ != "*" {
+			sel.spec[2]++
+		}
+	}
+	if comb != 0 {
+		return sel, p.errorf(off+len(s), "selector ends with >")
+	}
+	if len(sel.compounds) == 0 {
+		return sel, p.errorf(off, "empty selector")
+	}
+	return sel, nil
+}
+
+// declaration parses one "name: value" or bare modifier of a rule.
+func (p *styleParser) declaration(patch *stylePatch, decl string, off int) error {
+	trimmed := strings.TrimSpace(decl)
+	if trimmed == "" {
+		return nil
+	}
+	off += strings.Index(decl, trimmed)
+	name, value, ok := strings.Cut(trimmed, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	value = strings.TrimSpace(value)
+	if !ok {
+		m, known := modifierMap[name]
+		if !known {
+			return p.errorf(off, "unknown modifier %q", name)
+		}
+		patch.addMod |= m
+		return nil
+	}
+	switch name {
+	case "fg", "color", "bg", "background":
+		c, err := ParseColor(value)
+		if err != nil {
+			return p.errorf(off, "%s: %v", name, err)
+		}
+		if name == "fg" || name == "color" {
+			patch.fg, patch.setFg = c, true
+		} else {
+			patch.bg, patch.setBg = c, true
+		}
+	case "mod", "modifier", "modifiers":
+		var mod Modifier
+		for m := range strings.FieldsFuncSeq(value, func(r rune) bool {
+			return r == '|' || r == ',' || unicode.IsSpace(r)
+		}) {
+			if m == "none" {
+				continue
+			}
+			v, known := modifierMap[strings.ToLower(m)]
+			if !known {
+				return p.errorf(off, "unknown modifier %q", m)
+			}
+			mod |= v
+		}
+		patch.mod, patch.setMod = mod, true
+		patch.addMod = 0
+	default:
+		return p.errorf(off, "unknown property %q", name)
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ const (
 	COLLAPSED            = '+'
 	EXPANDED             = '−'
 	ELLIPSES             = '…'
+	HYPHEN               = '-'
 	UP_ARROW             = '▲'
 	DOWN_ARROW           = '▼'
 	DOT                  = '•'
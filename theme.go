@@ -0,0 +1,277 @@
+package gotui
+
+import (
+	"slices"
+	"sort"
+)
+
+// DefaultTheme returns a new copy of the built-in theme.
+func DefaultTheme() RootTheme {
+	return RootTheme{
+		Default: NewStyle(ColorWhite),
+		Block: BlockTheme{
+			Title:  NewStyle(ColorWhite),
+			Border: NewStyle(ColorWhite),
+		},
+		BarChart: BarChartTheme{
+			Bars:   slices.Clone(StandardColors),
+			Nums:   slices.Clone(StandardStyles),
+			Labels: slices.Clone(StandardStyles),
+		},
+		Paragraph: ParagraphTheme{
+			Text: NewStyle(ColorWhite),
+		},
+		PieChart: PieChartTheme{
+			Slices: slices.Clone(StandardColors),
+		},
+		List: ListTheme{
+			Text: NewStyle(ColorWhite),
+		},
+		Tree: TreeTheme{
+			Text:      NewStyle(ColorWhite),
+			Collapsed: COLLAPSED,
+			Expanded:  EXPANDED,
+		},
+		StackedBarChart: StackedBarChartTheme{
+			Bars:   slices.Clone(StandardColors),
+			Nums:   slices.Clone(StandardStyles),
+			Labels: slices.Clone(StandardStyles),
+		},
+		Gauge: GaugeTheme{
+			Bar:   ColorWhite,
+			Label: NewStyle(ColorWhite),
+		},
+		Sparkline: SparklineTheme{
+			Title: NewStyle(ColorWhite),
+			Line:  ColorWhite,
+		},
+		Plot: PlotTheme{
+			Lines: slices.Clone(StandardColors),
+			Axes:  ColorWhite,
+		},
+		Table: TableTheme{
+			Text: NewStyle(ColorWhite),
+		},
+		Tab: TabTheme{
+			Active:   NewStyle(ColorRed),
+			Inactive: NewStyle(ColorWhite),
+		},
+		Markdown: MarkdownTheme{
+			Text:        NewStyle(ColorWhite),
+			Heading:     NewStyle(ColorLightCyan, ColorClear, ModifierBold),
+			Code:        NewStyle(ColorYellow),
+			Quote:       NewStyle(ColorGrey, ColorClear, ModifierItalic),
+			Link:        NewStyle(ColorLightBlue),
+			Rule:        NewStyle(ColorGrey),
+			Bullet:      NewStyle(ColorLightCyan),
+			TableHeader: NewStyle(ColorWhite, ColorClear, ModifierBold),
+		},
+		Syntax: SyntaxTheme{
+			Text:     NewStyle(ColorWhite),
+			Keyword:  NewStyle(ColorLightBlue, ColorClear, ModifierBold),
+			Type:     NewStyle(ColorLightGreen),
+			Builtin:  NewStyle(ColorLightCyan),
+			Variable: NewStyle(ColorOrange),
+			String:   NewStyle(ColorYellow),
+			Number:   NewStyle(ColorPink),
+			Comment:  NewStyle(ColorGrey, ColorClear, ModifierItalic),
+			Operator: NewStyle(ColorLightGrey),
+			Key:      NewStyle(ColorLightCyan),
+			Inserted: NewStyle(ColorGreen),
+			Deleted:  NewStyle(ColorRed),
+			Heading:  NewStyle(ColorWhite, ColorClear, ModifierBold),
+		},
+	}
+}
+
+// Clone returns a deep copy of the theme that shares no slices with t.
+func (t RootTheme) Clone() RootTheme {
+	c := t
+	c.BarChart.Bars = slices.Clone(t.BarChart.Bars)
+	c.BarChart.Nums = slices.Clone(t.BarChart.Nums)
+	c.BarChart.Labels = slices.Clone(t.BarChart.Labels)
+	c.PieChart.Slices = slices.Clone(t.PieChart.Slices)
+	c.StackedBarChart.Bars = slices.Clone(t.StackedBarChart.Bars)
+	c.StackedBarChart.Nums = slices.Clone(t.StackedBarChart.Nums)
+	c.StackedBarChart.Labels = slices.Clone(t.StackedBarChart.Labels)
+	c.Plot.Lines = slices.Clone(t.Plot.Lines)
+	return c
+}
+
+// DarkTheme returns the built-in dark theme, which is the default theme.
+func DarkTheme() RootTheme {
+	return DefaultTheme()
+}
+
+// LightTheme returns a built-in theme for terminals with a light background.
+func LightTheme() RootTheme {
+	text := NewStyle(ColorBlack)
+	colors := []Color{ColorDarkBlue, ColorDarkGreen, ColorDarkRed, ColorPurple, ColorDarkCyan, ColorBrown, ColorBlack}
+	return paletteTheme(text, NewStyle(ColorDarkGrey), NewStyle(ColorDarkBlue, ColorClear, ModifierBold), colors)
+}
+
+// SolarizedTheme returns a built-in theme using the Solarized palette.
+func SolarizedTheme() RootTheme {
+	text := NewStyle(NewRGBColor(0x83, 0x94, 0x96))
+	colors := []Color{
+		NewRGBColor(0x26, 0x8b, 0xd2), NewRGBColor(0x85, 0x99, 0x00), NewRGBColor(0xb5, 0x89, 0x00),
+		NewRGBColor(0xcb, 0x4b, 0x16), NewRGBColor(0xd3, 0x36, 0x82), NewRGBColor(0x6c, 0x71, 0xc4),
+		NewRGBColor(0x2a, 0xa1, 0x98),
+	}
+	return paletteTheme(text, NewStyle(NewRGBColor(0x58, 0x6e, 0x75)), NewStyle(colors[2], ColorClear, ModifierBold), colors)
+}
+
+// HighContrastTheme returns a built-in theme with bold, saturated colors.
+func HighContrastTheme() RootTheme {
+	text := NewStyle(ColorWhite, ColorClear, ModifierBold)
+	colors := []Color{ColorYellow, ColorLightCyan, ColorLime, ColorMagenta, ColorRed, ColorBlue, ColorWhite}
+	t := paletteTheme(text, NewStyle(ColorYellow, ColorClear, ModifierBold), NewStyle(ColorBlack, ColorYellow, ModifierBold), colors)
+	t.Tab.Inactive = text
+	return t
+}
+
+// paletteTheme builds a theme from a text style, a border style, the style
+// of the active tab and a palette for series.
+func paletteTheme(text, border, active Style, colors []Color) RootTheme {
+	styles := make([]Style, len(colors))
+	for i, c := range colors {
+		styles[i] = NewStyle(c)
+	}
+	t := DefaultTheme()
+	t.Default = text
+	t.Block = BlockTheme{Title: text, Border: border}
+	t.BarChart = BarChartTheme{Bars: colors, Nums: styles, Labels: styles}
+	t.StackedBarChart = StackedBarChartTheme{Bars: colors, Nums: styles, Labels: styles}
+	t.Gauge = GaugeTheme{Bar: colors[0], Label: text}
+	t.Plot = PlotTheme{Lines: colors, Axes: border.Fg}
+	t.List.Text = text
+	t.Tree.Text = text
+	t.Paragraph.Text = text
+	t.PieChart.Slices = colors
+	t.Sparkline = SparklineTheme{Title: text, Line: colors[0]}
+	t.Table.Text = text
+	t.Tab = TabTheme{Active: active, Inactive: text}
+	t.Markdown = MarkdownTheme{
+		Text:        text,
+		Heading:     NewStyle(colors[0], ColorClear, ModifierBold),
+		Code:        NewStyle(colors[2]),
+		Quote:       NewStyle(border.Fg, ColorClear, ModifierItalic),
+		Link:        NewStyle(colors[1]),
+		Rule:        border,
+		Bullet:      NewStyle(colors[0]),
+		TableHeader: NewStyle(text.Fg, text.Bg, text.Modifier|ModifierBold),
+	}
+	t.Syntax.Text = text
+	t.Syntax.Keyword = NewStyle(colors[0], ColorClear, ModifierBold)
+	t.Syntax.Type = NewStyle(colors[4])
+	t.Syntax.Builtin = NewStyle(colors[4])
+	t.Syntax.Variable = NewStyle(colors[3])
+	t.Syntax.String = NewStyle(colors[2])
+	t.Syntax.Number = NewStyle(colors[5])
+	t.Syntax.Comment = NewStyle(border.Fg, ColorClear, ModifierItalic)
+	t.Syntax.Operator = text
+	t.Syntax.Key = NewStyle(colors[0])
+	t.Syntax.Heading = NewStyle(text.Fg, text.Bg, text.Modifier|ModifierBold)
+	return t.Clone()
+}
+
+var builtinThemes = map[string]func() RootTheme{
+	"dark":          DarkTheme,
+	"light":         LightTheme,
+	"solarized":     SolarizedTheme,
+	"high-contrast": HighContrastTheme,
+}
+
+// BuiltinTheme returns the built-in theme called name.
+func BuiltinTheme(name string) (RootTheme, bool) {
+	f, ok := builtinThemes[name]
+	if !ok {
+		return RootTheme{}, false
+	}
+	return f(), true
+}
+
+// BuiltinThemeNames returns the names of the built-in themes.
+func BuiltinThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetTheme replaces the theme of the context. Theme is pointed at a copy
+// of t and the RootTheme it held before is left unchanged, so constructors
+// reading it on other goroutines still see one whole theme. On
+// DefaultContext the global Theme keeps its old values. Widgets bound to
+// the context pick up the new theme the next time they are drawn, except
+// for the values that were set by hand.
+func (c *Context) SetTheme(t RootTheme) {
+	theme := t.Clone()
+	c.themeMu.Lock()
+	defer c.themeMu.Unlock()
+	c.Theme = &theme
+}
+
+// CurrentTheme returns Theme, read under the lock SetTheme takes. Widget
+// constructors read the theme through it so that they are safe to call
+// while another goroutine calls SetTheme.
+func (c *Context) CurrentTheme() *RootTheme {
+	if c == nil {
+		return nil
+	}
+	c.themeMu.Lock()
+	defer c.themeMu.Unlock()
+	return c.Theme
+}
+
+// ThemeChanged reports whether the theme of the block context has been
+// replaced since the block was styled. It restyles the fields of the block
+// itself and returns the old and new themes so that widgets can restyle
+// theirs with Restyle. Widgets call it at the start of Draw, before
+// Block.Draw, which calls it too.
+//
+// The widgets in this module do so from an unexported restyle method that
+// passes each field taken from the theme in their constructor to Restyle
+// or RestyleSlice with its old and new theme values:
+//
+//	func (g *Gauge) restyle() {
+//		old, cur, ok := g.ThemeChanged()
+//		if !ok {
+//			return
+//		}
+//		ui.Restyle(&g.BarColor, old.Gauge.Bar, cur.Gauge.Bar)
+//	}
+//
+// A field that no longer holds its old theme value was set by hand and is
+// left alone.
+func (b *Block) ThemeChanged() (old, cur *RootTheme, changed bool) {
+	if b.Context == nil || b.themed == nil {
+		return nil, nil, false
+	}
+	cur = b.Context.CurrentTheme()
+	if cur == nil || cur == b.themed {
+		return nil, nil, false
+	}
+	old, b.themed = b.themed, cur
+	Restyle(&b.BorderStyle, old.Block.Border, cur.Block.Border)
+	Restyle(&b.TitleStyle, old.Block.Title, cur.Block.Title)
+	Restyle(&b.TitleBottomStyle, old.Block.Title, cur.Block.Title)
+	return old, cur, true
+}
+
+// Restyle sets *field to cur when it still holds old, the value the
+// previous theme gave it. Values set by hand are left alone.
+func Restyle[T comparable](field *T, old, cur T) {
+	if *field == old {
+		*field = cur
+	}
+}
+
+// RestyleSlice is Restyle for slices of colors or styles.
+func RestyleSlice[T comparable](field *[]T, old, cur []T) {
+	if slices.Equal(*field, old) {
+		*field = slices.Clone(cur)
+	}
+}
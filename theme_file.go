@@ -0,0 +1,314 @@
+package gotui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gdamore/tcell/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// Theme files hold the fields of RootTheme in snake_case, with styles
+// written as for ParseStyle and colors as for ParseColor:
+//
+//	default = "fg:white"
+//
+//	[block]
+//	border = "fg:#586e75"
+//	title = "fg:white,mod:bold"
+//
+//	[bar_chart]
+//	bars = ["red", "green", "#268bd2"]
+//
+// Keys that are left out keep the values of DefaultTheme.
+
+// ThemeFormat is the encoding of a theme file.
+type ThemeFormat string
+
+const (
+	ThemeTOML ThemeFormat = "toml"
+	ThemeJSON ThemeFormat = "json"
+	ThemeYAML ThemeFormat = "yaml"
+)
+
+// ThemeFormatOf returns the format of a theme file from its extension.
+func ThemeFormatOf(path string) (ThemeFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return ThemeTOML, nil
+	case ".json":
+		return ThemeJSON, nil
+	case ".yaml", ".yml":
+		return ThemeYAML, nil
+	}
+	return "", fmt.Errorf("%s: unknown theme format, use .toml, .json or .yaml", path)
+}
+
+// LoadTheme reads a theme file, choosing the format from its extension.
+func LoadTheme(path string) (RootTheme, error) {
+	format, err := ThemeFormatOf(path)
+	if err != nil {
+		return RootTheme{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RootTheme{}, err
+	}
+	t, err := ParseTheme(data, format)
+	if err != nil {
+		return RootTheme{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// SaveTheme writes t to a file, choosing the format from its extension.
+func SaveTheme(path string, t RootTheme) error {
+	format, err := ThemeFormatOf(path)
+	if err != nil {
+		return err
+	}
+	data, err := MarshalTheme(t, format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ParseTheme decodes a theme on top of DefaultTheme.
+func ParseTheme(data []byte, format ThemeFormat) (RootTheme, error) {
+	var m map[string]any
+	var err error
+	switch format {
+	case ThemeTOML:
+		err = toml.Unmarshal(data, &m)
+	case ThemeJSON:
+		err = json.Unmarshal(data, &m)
+	case ThemeYAML:
+		err = yaml.Unmarshal(data, &m)
+	default:
+		err = fmt.Errorf("unknown theme format %q", format)
+	}
+	if err != nil {
+		return RootTheme{}, err
+	}
+	t := DefaultTheme()
+	if err := decodeTheme(reflect.ValueOf(&t).Elem(), m, ""); err != nil {
+		return RootTheme{}, err
+	}
+	return t, nil
+}
+
+// MarshalTheme encodes t in the given format.
+func MarshalTheme(t RootTheme, format ThemeFormat) ([]byte, error) {
+	m := encodeTheme(reflect.ValueOf(t))
+	switch format {
+	case ThemeTOML:
+		var buf bytes.Buffer
+		enc := toml.NewEncoder(&buf)
+		enc.Indent = ""
+		err := enc.Encode(m)
+		return buf.Bytes(), err
+	case ThemeJSON:
+		data, err := json.MarshalIndent(m, "", "  ")
+		return append(data, '\n'), err
+	case ThemeYAML:
+		return yaml.Marshal(m)
+	}
+	return nil, fmt.Errorf("unknown theme format %q", format)
+}
+
+var (
+	themeStyleType = reflect.TypeFor[Style]()
+	themeColorType = reflect.TypeFor[Color]()
+	themeRuneType  = reflect.TypeFor[rune]()
+)
+
+// themeKey turns a field name such as StackedBarChart into stacked_bar_chart.
+func themeKey(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func encodeTheme(v reflect.Value) map[string]any {
+	m := make(map[string]any)
+	for i := range v.NumField() {
+		f := v.Field(i)
+		key := themeKey(v.Type().Field(i).Name)
+		if f.Kind() == reflect.Struct && f.Type() != themeStyleType {
+			m[key] = encodeTheme(f)
+			continue
+		}
+		m[key] = encodeThemeValue(f)
+	}
+	return m
+}
+
+func encodeThemeValue(v reflect.Value) any {
+	switch v.Type() {
+	case themeStyleType:
+		return FormatStyle(v.Interface().(Style))
+	case themeColorType:
+		return FormatColor(v.Interface().(Color))
+	case themeRuneType:
+		return string(rune(v.Int()))
+	}
+	if v.Kind() == reflect.Slice {
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = encodeThemeValue(v.Index(i))
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+func decodeTheme(v reflect.Value, m map[string]any, path string) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		name := path + key
+		f, ok := themeField(v, key)
+		if !ok {
+			return fmt.Errorf("unknown theme key %q", name)
+		}
+		if f.Kind() == reflect.Struct && f.Type() != themeStyleType {
+			sub, ok := m[key].(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: expected a table", name)
+			}
+			if err := decodeTheme(f, sub, name+"."); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := decodeThemeValue(f, m[key], name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func themeField(v reflect.Value, key string) (reflect.Value, bool) {
+	for i := range v.NumField() {
+		if themeKey(v.Type().Field(i).Name) == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func decodeThemeValue(v reflect.Value, x any, name string) error {
+	if v.Kind() == reflect.Slice && v.Type() != themeStyleType {
+		items, ok := x.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected a list", name)
+		}
+		s := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeThemeValue(s.Index(i), item, fmt.Sprintf("%s[%d]", name, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	str, ok := x.(string)
+	if !ok {
+		return fmt.Errorf("%s: expected a string", name)
+	}
+	switch v.Type() {
+	case themeStyleType:
+		s, err := ParseStyle(str, StyleClear)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		v.Set(reflect.ValueOf(s))
+	case themeColorType:
+		c, err := ParseColor(str)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		v.Set(reflect.ValueOf(c))
+	case themeRuneType:
+		r, size := utf8.DecodeRuneInString(str)
+		if size == 0 || size != len(str) {
+			return fmt.Errorf("%s: expected a single character, got %q", name, str)
+		}
+		v.SetInt(int64(r))
+	default:
+		return fmt.Errorf("%s cannot be set from a theme file", name)
+	}
+	return nil
+}
+
+// FormatColor returns a name for c that ParseColor reads back: a name from
+// StyleParserColorMap, a W3C name or "#rrggbb", or "#rrggbbaa" when c has
+// an alpha.
+func FormatColor(c Color) string {
+	if c == ColorClear {
+		return "clear"
+	}
+	if c&alphaMask != 0 {
+		r, g, b := OpaqueColor(c).RGB()
+		return fmt.Sprintf("#%02x%02x%02x%02x", r, g, b, int(math.Round(ColorAlpha(c)*255)))
+	}
+	for _, names := range []map[string]Color{StyleParserColorMap, tcell.ColorNames} {
+		keys := make([]string, 0, len(names))
+		for name, v := range names {
+			if v == c {
+				keys = append(keys, name)
+			}
+		}
+		if len(keys) > 0 {
+			slices.Sort(keys)
+			return keys[0]
+		}
+	}
+	return strings.ToLower(c.CSS())
+}
+
+var modifierOrder = []string{"bold", "dim", "italic", "reverse", "blink", "strike"}
+
+// FormatStyle returns s written as ParseStyle reads it, leaving out parts
+// that are clear.
+func FormatStyle(s Style) string {
+	var parts []string
+	if s.Fg != ColorClear {
+		parts = append(parts, tokenFg+tokenValueSeparator+FormatColor(s.Fg))
+	}
+	if s.Bg != ColorClear {
+		parts = append(parts, tokenBg+tokenValueSeparator+FormatColor(s.Bg))
+	}
+	var mods []string
+	for _, name := range modifierOrder {
+		if s.Modifier&modifierMap[name] != 0 {
+			mods = append(mods, name)
+		}
+	}
+	if len(mods) > 0 {
+		parts = append(parts, tokenModifier+tokenValueSeparator+strings.Join(mods, "|"))
+	}
+	return strings.Join(parts, tokenItemSeparator)
+}
@@ -4,6 +4,7 @@ import (
 	"image"
 	"io"
 	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v3"
 	"github.com/metaspartan/gotui/v5/drawille"
@@ -34,6 +35,50 @@ type Widget interface {
 	EventHandler
 }
 
+// FocusContainer is a Widget that passes keyboard focus on to one of its
+// children.
+type FocusContainer interface {
+	Widget
+	FocusedChild() Widget
+}
+
+// Container is a Drawable that holds other Drawables. Stylesheets walk
+// containers to match selectors against the widgets inside them.
+type Container interface {
+	Drawable
+	Children() []Drawable
+}
+
+// Stateful is a Drawable that reports states of its own for stylesheet
+// selectors, such as "checked" for a checked Checkbox.
+type Stateful interface {
+	Drawable
+	StyleStates() []string
+}
+
+// Responsive is a Drawable that adapts its layout to the terminal size.
+type Responsive interface {
+	Drawable
+	Respond(width, height int)
+}
+
+// Clock reports the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Context carries the state shared by a set of widgets: the backend they
+// render to, the theme they are styled with and the clock they read.
+type Context struct {
+	Backend *Backend
+	Theme   *RootTheme
+	Clock   Clock
+	// Stylesheet, when set, is applied to the widgets before each render.
+	Stylesheet *Stylesheet
+	// themeMu guards Theme against SetTheme, see CurrentTheme.
+	themeMu sync.Mutex
+}
+
 // TTYHandle represents a handle to a TTY.
 type TTYHandle interface {
 	io.ReadWriter
@@ -75,25 +120,61 @@ type Style struct {
 	Modifier Modifier
 }
 
-// Gradient represents a color gradient.
+// Gradient represents a color gradient. It runs from Start to End, or
+// through Stops when there are any, in the shape given by Direction.
 type Gradient struct {
 	Enabled   bool
 	Start     Color
 	End       Color
 	Stops     []Color
 	Direction int
+	// Space is the color space the stops are blended in.
+	Space ColorSpace
+}
+
+// ColorSpace is a space that colors are blended in, see MixColors.
+type ColorSpace int
+
+// Direction is the direction text runs in.
+type Direction int
+
+// WrapOptions sets how BreakLines wraps text.
+type WrapOptions struct {
+	// BreakLongWords breaks a word wider than the line at the edge of the
+	// line instead of letting it stick out, and Hyphen ends each broken
+	// part with HYPHEN.
+	BreakLongWords bool
+	Hyphen         bool
+	// MaxLines, when more than 0, keeps only that many lines, and Ellipsis
+	// ends the last of them with ELLIPSES when text was cut.
+	MaxLines int
+	Ellipsis bool
 }
 
 // Cell represents a single cell in the terminal.
 type Cell struct {
 	Rune  rune
 	Style Style
+	// Grapheme holds the whole grapheme cluster when it is more than Rune,
+	// such as a letter with combining marks or a ZWJ emoji sequence.
+	Grapheme string
+	// Width caches the number of columns the cell takes; 0 means it is
+	// worked out from Rune. The column after a wide cell holds a
+	// continuation cell whose Rune is 0.
+	Width int
 }
 
 // Buffer represents a buffer of cells.
+//
+// Writes outside Rectangle, or outside the clip rectangle set with PushClip,
+// are dropped. A buffer returned by SubBuffer shares its cells with its
+// parent and has its own coordinates.
 type Buffer struct {
 	image.Rectangle
-	Cells []Cell
+	Cells  []Cell
+	area   image.Rectangle
+	origin image.Point
+	clips  []image.Rectangle
 }
 
 // Alignment represents the alignment of text.
@@ -105,12 +186,33 @@ type gridItemType uint
 // Grid allows you to lay out widgets in a grid.
 type Grid struct {
 	Block
-	Items []*GridItem
+	Items        []*GridItem
+	RowSpacing   int
+	ColSpacing   int
+	MergeBorders bool
+	root         *GridItem
+	layouts      []gridLayout
+	active       int
+}
+
+// Breakpoint is the smallest terminal size a layout is meant for.
+type Breakpoint struct {
+	MinWidth  int
+	MinHeight int
+}
+
+// gridLayout is one of the alternative arrangements of a Grid.
+type gridLayout struct {
+	Breakpoint
+	entries []any
 }
 
 // GridItem represents an item in the grid.
 type GridItem struct {
-	Type        gridItemType
+	Type gridItemType
+	// XRatio, YRatio, WidthRatio and HeightRatio give where the last Draw
+	// put the item, as fractions of the size of the grid. They are set on
+	// the leaves in Grid.Items.
 	XRatio      float64
 	YRatio      float64
 	WidthRatio  float64
@@ -118,8 +220,30 @@ type GridItem struct {
 	Entry       any
 	IsLeaf      bool
 	ratio       float64
+	constraint  Constraint
 }
 
+// constraintKind represents the kind of a layout constraint.
+type constraintKind uint
+
+// Constraint describes the size of one slot in a layout.
+type Constraint struct {
+	kind  constraintKind
+	value float64
+}
+
+// LayoutDirection is the axis along which a layout splits an area.
+type LayoutDirection int
+
+// Anchor is the point of an area that Place pins a rectangle to.
+type Anchor int
+
+// TextMode is how a widget reads the escapes in its text, see ParseText.
+type TextMode int
+
+// TokenKind is the kind of a token of source code, see Lexer.
+type TokenKind int
+
 // Block is the base struct for all widgets.
 type Block struct {
 	Border                                               bool
@@ -144,7 +268,19 @@ type Block struct {
 	TitleBottomStyle     Style
 	TitleBottomAlignment Alignment
 	BorderGradient       Gradient
-	BorderSet            *BorderSet
+	// BackgroundGradient, when enabled, fills the block in place of
+	// BackgroundColor.
+	BackgroundGradient Gradient
+	BorderSet          *BorderSet
+	MinWidth           int
+	MinHeight          int
+	Context            *Context
+	// ID and Classes name the block for stylesheet selectors.
+	ID      string
+	Classes []string
+	themed  *RootTheme
+	states  map[string]bool
+	styled  map[string]*styledField
 	sync.Mutex
 }
 
@@ -167,6 +303,8 @@ type RootTheme struct {
 	StackedBarChart StackedBarChartTheme
 	Tab             TabTheme
 	Table           TableTheme
+	Markdown        MarkdownTheme
+	Syntax          SyntaxTheme
 }
 type BlockTheme struct {
 	Title  Style
@@ -217,3 +355,33 @@ type TabTheme struct {
 type TableTheme struct {
 	Text Style
 }
+
+// MarkdownTheme represents the theme for a markdown document.
+type MarkdownTheme struct {
+	Text        Style
+	Heading     Style
+	Code        Style
+	Quote       Style
+	Link        Style
+	Rule        Style
+	Bullet      Style
+	TableHeader Style
+}
+
+// SyntaxTheme represents the theme for highlighted source code, with a
+// style for each TokenKind.
+type SyntaxTheme struct {
+	Text     Style
+	Keyword  Style
+	Type     Style
+	Builtin  Style
+	Variable Style
+	String   Style
+	Number   Style
+	Comment  Style
+	Operator Style
+	Key      Style
+	Inserted Style
+	Deleted  Style
+	Heading  Style
+}
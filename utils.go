@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"strings"
 
 	rw "github.com/mattn/go-runewidth"
-	wordwrap "github.com/mitchellh/go-wordwrap"
+	"github.com/rivo/uniseg"
 )
 
 // InterfaceSlice converts a slice of any type to a slice of interface{}.
@@ -155,51 +156,50 @@ func MinInt(x, y int) int {
 	return y
 }
 
-// WrapCells wraps a slice of cells to a given width.
-func WrapCells(cells []Cell, width uint) []Cell {
-	str := CellsToString(cells)
-	wrapped := wordwrap.WrapString(str, width)
-	wrappedCells := []Cell{}
-	i := 0
-	for _, _rune := range wrapped {
-		if _rune == '\n' {
-			wrappedCells = append(wrappedCells, Cell{_rune, StyleClear})
-		} else {
-			wrappedCells = append(wrappedCells, Cell{_rune, cells[i].Style})
-		}
-		i++
-	}
-	return wrappedCells
+// RunesToStyledCells converts a slice of runes to a slice of cells with a
+// given style, one grapheme cluster per cell.
+func RunesToStyledCells(runes []rune, style Style) []Cell {
+	return StringToStyledCells(string(runes), style)
 }
 
-// RunesToStyledCells converts a slice of runes to a slice of cells with a given style.
-func RunesToStyledCells(runes []rune, style Style) []Cell {
+// StringToStyledCells converts a string to a slice of cells with a given
+// style, one grapheme cluster per cell.
+func StringToStyledCells(s string, style Style) []Cell {
 	cells := []Cell{}
-	for _, _rune := range runes {
-		cells = append(cells, Cell{_rune, style})
+	state := -1
+	for s != "" {
+		var g string
+		g, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		cells = append(cells, NewGraphemeCell(g, style))
 	}
 	return cells
 }
 
 // CellsToString converts a slice of cells to a string.
 func CellsToString(cells []Cell) string {
-	runes := make([]rune, len(cells))
-	for i, cell := range cells {
-		runes[i] = cell.Rune
+	var sb strings.Builder
+	for _, cell := range cells {
+		sb.WriteString(cell.Text())
 	}
-	return string(runes)
+	return sb.String()
 }
 
-// TrimCells trims a slice of cells to a given width.
-func TrimCells(cells []Cell, w int) []Cell {
-	s := CellsToString(cells)
-	s = TrimString(s, w)
-	runes := []rune(s)
-	newCells := []Cell{}
-	for i, r := range runes {
-		newCells = append(newCells, Cell{r, cells[i].Style})
+// groupGraphemes merges cells that hold parts of one grapheme cluster,
+// keeping the style of the first.
+func groupGraphemes(cells []Cell) []Cell {
+	out := make([]Cell, 0, len(cells))
+	rest := CellsToString(cells)
+	state := -1
+	i := 0
+	for rest != "" && i < len(cells) {
+		var g string
+		g, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		out = append(out, NewGraphemeCell(g, cells[i].Style))
+		for n := 0; n < len(g) && i < len(cells); i++ {
+			n += len(cells[i].Text())
+		}
 	}
-	return newCells
+	return out
 }
 
 // SplitCells splits a slice of cells by a rune.
@@ -231,7 +231,7 @@ func BuildCellWithXArray(cells []Cell) []CellWithX {
 	index := 0
 	for i, cell := range cells {
 		cellWithXArray[i] = CellWithX{X: index, Cell: cell}
-		index += rw.RuneWidth(cell.Rune)
+		index += cell.ColumnWidth()
 	}
 	return cellWithXArray
 }
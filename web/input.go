@@ -0,0 +1,90 @@
+package web
+
+import (
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// browserKeys maps KeyboardEvent.key names to tcell keys.
+var browserKeys = map[string]tcell.Key{
+	"Enter":      tcell.KeyEnter,
+	"Tab":        tcell.KeyTab,
+	"Backspace":  tcell.KeyBackspace,
+	"Escape":     tcell.KeyEsc,
+	"Insert":     tcell.KeyInsert,
+	"Delete":     tcell.KeyDelete,
+	"Home":       tcell.KeyHome,
+	"End":        tcell.KeyEnd,
+	"PageUp":     tcell.KeyPgUp,
+	"PageDown":   tcell.KeyPgDn,
+	"ArrowUp":    tcell.KeyUp,
+	"ArrowDown":  tcell.KeyDown,
+	"ArrowLeft":  tcell.KeyLeft,
+	"ArrowRight": tcell.KeyRight,
+	"F1":         tcell.KeyF1,
+	"F2":         tcell.KeyF2,
+	"F3":         tcell.KeyF3,
+	"F4":         tcell.KeyF4,
+	"F5":         tcell.KeyF5,
+	"F6":         tcell.KeyF6,
+	"F7":         tcell.KeyF7,
+	"F8":         tcell.KeyF8,
+	"F9":         tcell.KeyF9,
+	"F10":        tcell.KeyF10,
+	"F11":        tcell.KeyF11,
+	"F12":        tcell.KeyF12,
+}
+
+func modifiers(msg clientMessage) tcell.ModMask {
+	var mod tcell.ModMask
+	if msg.Ctrl {
+		mod |= tcell.ModCtrl
+	}
+	if msg.Alt {
+		mod |= tcell.ModAlt
+	}
+	if msg.Shift {
+		mod |= tcell.ModShift
+	}
+	return mod
+}
+
+// keyEvent translates a browser key message. It returns nil for keys that
+// have no terminal equivalent, such as a lone modifier.
+func keyEvent(msg clientMessage) *tcell.EventKey {
+	mod := modifiers(msg)
+	if msg.Key == "Tab" && msg.Shift {
+		return tcell.NewEventKey(tcell.KeyBacktab, "", mod&^tcell.ModShift)
+	}
+	if k, ok := browserKeys[msg.Key]; ok {
+		return tcell.NewEventKey(k, "", mod)
+	}
+	if utf8.RuneCountInString(msg.Key) != 1 {
+		return nil
+	}
+	return tcell.NewEventKey(tcell.KeyRune, msg.Key, mod&^tcell.ModShift)
+}
+
+// mouseEvent translates a browser mouse message. Browser buttons 0, 1 and 2
+// are left, middle and right.
+func mouseEvent(msg clientMessage) *tcell.EventMouse {
+	var btn tcell.ButtonMask
+	switch msg.Action {
+	case "press", "move":
+		switch msg.Button {
+		case 0:
+			btn = tcell.Button1
+		case 1:
+			btn = tcell.Button3
+		case 2:
+			btn = tcell.Button2
+		}
+	case "wheel":
+		btn = tcell.WheelDown
+		if msg.Delta < 0 {
+			btn = tcell.WheelUp
+		}
+	}
+	return tcell.NewEventMouse(msg.X, msg.Y, btn, modifiers(msg))
+}
@@ -0,0 +1,171 @@
+package web
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+// Cell attribute bits used by the cell-diff protocol.
+const (
+	AttrBold = 1 << iota
+	AttrItalic
+	AttrUnderline
+	AttrReverse
+	AttrDim
+	AttrStrikeThrough
+	AttrBlink
+)
+
+// screen wraps the session screen so events can be injected safely and, in
+// cell mode, every Show is followed by a frame diff.
+type screen struct {
+	tcell.Screen
+	frames *frameWriter
+
+	mu   sync.RWMutex
+	done chan struct{}
+	once sync.Once
+}
+
+func newScreen(s tcell.Screen, frames *frameWriter) *screen {
+	return &screen{Screen: s, frames: frames, done: make(chan struct{})}
+}
+
+func (s *screen) Show() {
+	if s.frames == nil {
+		s.Screen.Show()
+		return
+	}
+	s.frames.mu.Lock()
+	defer s.frames.mu.Unlock()
+	s.Screen.Show()
+	s.frames.flush(false)
+}
+
+func (s *screen) Sync() {
+	if s.frames == nil {
+		s.Screen.Sync()
+		return
+	}
+	s.frames.mu.Lock()
+	defer s.frames.mu.Unlock()
+	s.Screen.Sync()
+	s.frames.flush(true)
+}
+
+func (s *screen) Fini() {
+	s.once.Do(func() { close(s.done) })
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Screen.Fini()
+}
+
+// post delivers ev to the event queue unless the screen is finalized.
+func (s *screen) post(ev tcell.Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	select {
+	case <-s.done:
+	case s.Screen.EventQ() <- ev:
+	}
+}
+
+// frameCell is a changed cell in a frame message.
+type frameCell struct {
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Text  string `json:"t"`
+	Fg    int32  `json:"fg"`
+	Bg    int32  `json:"bg"`
+	Attrs int    `json:"a"`
+}
+
+type cursor struct {
+	X       int  `json:"x"`
+	Y       int  `json:"y"`
+	Visible bool `json:"visible"`
+}
+
+type frameMessage struct {
+	Type   string      `json:"type"`
+	Cols   int         `json:"cols"`
+	Rows   int         `json:"rows"`
+	Full   bool        `json:"full"`
+	Cells  []frameCell `json:"cells"`
+	Cursor cursor      `json:"cursor"`
+}
+
+// frameWriter diffs the simulation screen against the last frame sent.
+type frameWriter struct {
+	sim  tcell.SimulationScreen
+	send func(any) error
+
+	mu   sync.Mutex
+	prev []frameCell
+	cols int
+	rows int
+}
+
+func (f *frameWriter) flush(full bool) {
+	contents, w, h := f.sim.GetContents()
+	if w != f.cols || h != f.rows {
+		full = true
+		f.cols, f.rows = w, h
+		f.prev = make([]frameCell, w*h)
+	}
+	msg := frameMessage{Type: "frame", Cols: w, Rows: h, Full: full}
+	for i, c := range contents {
+		cell := toFrameCell(c, i%w, i/w)
+		if !full && cell == f.prev[i] {
+			continue
+		}
+		f.prev[i] = cell
+		msg.Cells = append(msg.Cells, cell)
+	}
+	msg.Cursor.X, msg.Cursor.Y, msg.Cursor.Visible = f.sim.GetCursor()
+	if len(msg.Cells) > 0 || full {
+		_ = f.send(msg)
+	}
+}
+
+func toFrameCell(c tcell.SimCell, x, y int) frameCell {
+	text := string(c.Runes)
+	if text == "" {
+		text = " "
+	}
+	return frameCell{
+		X:     x,
+		Y:     y,
+		Text:  text,
+		Fg:    c.Style.GetForeground().Hex(),
+		Bg:    c.Style.GetBackground().Hex(),
+		Attrs: attrs(c.Style),
+	}
+}
+
+func attrs(s tcell.Style) int {
+	a := 0
+	if s.HasBold() {
+		a |= AttrBold
+	}
+	if s.HasItalic() {
+		a |= AttrItalic
+	}
+	if s.HasUnderline() {
+		a |= AttrUnderline
+	}
+	if s.HasReverse() {
+		a |= AttrReverse
+	}
+	if s.HasDim() {
+		a |= AttrDim
+	}
+	if s.HasStrikeThrough() {
+		a |= AttrStrikeThrough
+	}
+	if s.HasBlink() {
+		a |= AttrBlink
+	}
+	return a
+}
@@ -0,0 +1,211 @@
+package web
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	ui "github.com/metaspartan/gotui/v5"
+	"golang.org/x/net/websocket"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// ErrServerClosed is returned for sessions started after Shutdown or Close.
+var ErrServerClosed = errors.New("web: server closed")
+
+// Mode selects how frames are streamed to the browser.
+type Mode int
+
+const (
+	// ModeANSI streams raw terminal output for xterm.js.
+	ModeANSI Mode = iota
+	// ModeCells streams JSON cell diffs for the built-in renderer.
+	ModeCells
+)
+
+// String returns the name used for the mode in URLs and messages.
+func (m Mode) String() string {
+	if m == ModeCells {
+		return "cells"
+	}
+	return "ansi"
+}
+
+// ParseMode returns the Mode named s.
+func ParseMode(s string) (Mode, bool) {
+	switch s {
+	case "ansi":
+		return ModeANSI, true
+	case "cells":
+		return ModeCells, true
+	}
+	return ModeANSI, false
+}
+
+// Handler builds the application served to a session. Widgets should be
+// created with the session context, e.g. widgets.NewParagraphWithContext(sess.UI).
+type Handler func(*Session) *ui.Application
+
+// Server serves gotui applications to browsers, one Application per
+// WebSocket connection. It serves the terminal page on "/" and the
+// WebSocket endpoint on "/ws".
+type Server struct {
+	Handler     Handler
+	Mode        Mode
+	MaxSessions int
+	Theme       ui.RootTheme
+	// CheckOrigin reports whether a WebSocket handshake is allowed. By
+	// default browsers may only connect from pages served by the same host.
+	CheckOrigin func(r *http.Request) bool
+
+	mu       sync.Mutex
+	closing  bool
+	sessions map[*Session]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewServer returns a new Server using handler for every session.
+func NewServer(handler Handler) *Server {
+	return &Server{
+		Handler:  handler,
+		Theme:    ui.DefaultTheme(),
+		sessions: make(map[*Session]struct{}),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/", "/index.html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(indexHTML)
+	case "/ws":
+		websocket.Server{Handshake: s.handshake, Handler: s.serveConn}.ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Shutdown stops every running application and waits for the sessions to
+// end or ctx to be done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.closeSessions()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops every running application without waiting.
+func (s *Server) Close() error {
+	s.closeSessions()
+	return nil
+}
+
+// ActiveSessions returns the number of running sessions.
+func (s *Server) ActiveSessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+func (s *Server) closeSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closing = true
+	for sess := range s.sessions {
+		sess.Close()
+	}
+}
+
+func (s *Server) handshake(config *websocket.Config, r *http.Request) error {
+	if s.CheckOrigin != nil {
+		if !s.CheckOrigin(r) {
+			return errors.New("web: origin not allowed")
+		}
+		return nil
+	}
+	origin, err := websocket.Origin(config, r)
+	if err != nil || (origin != nil && origin.Host != r.Host) {
+		return errors.New("web: origin not allowed")
+	}
+	return nil
+}
+
+func (s *Server) serveConn(ws *websocket.Conn) {
+	defer ws.Close()
+	sess := newSession(ws, s.sessionMode(ws.Request()))
+	defer sess.cancel()
+	if err := s.trackSession(sess, true); err != nil {
+		_ = sess.send(errorMessage(err))
+		return
+	}
+	defer s.trackSession(sess, false)
+	defer s.wg.Done()
+
+	if err := s.runSession(sess); err != nil {
+		_ = sess.send(errorMessage(err))
+	}
+}
+
+func (s *Server) sessionMode(r *http.Request) Mode {
+	if m, ok := ParseMode(r.URL.Query().Get("mode")); ok {
+		return m
+	}
+	return s.Mode
+}
+
+func (s *Server) runSession(sess *Session) error {
+	q := sess.Request.URL.Query()
+	cols, _ := strconv.Atoi(q.Get("cols"))
+	rows, _ := strconv.Atoi(q.Get("rows"))
+	backend, err := sess.open(cols, rows)
+	if err != nil {
+		return err
+	}
+	sess.UI = ui.NewContext(backend)
+	*sess.UI.Theme = s.Theme.Clone()
+	app := s.Handler(sess)
+	if app == nil {
+		backend.Close()
+		return errors.New("web: handler returned no application")
+	}
+	app.Backend = backend
+	app.Context = sess.UI
+
+	go sess.readLoop()
+	return app.RunWithContext(sess.ctx)
+}
+
+func (s *Server) trackSession(sess *Session, add bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !add {
+		delete(s.sessions, sess)
+		return nil
+	}
+	if s.closing {
+		return ErrServerClosed
+	}
+	if s.MaxSessions > 0 && len(s.sessions) >= s.MaxSessions {
+		return errors.New("web: too many sessions")
+	}
+	if s.sessions == nil {
+		s.sessions = make(map[*Session]struct{})
+	}
+	s.sessions[sess] = struct{}{}
+	s.wg.Add(1)
+	return nil
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gdamore/tcell/v3"
+	ui "github.com/metaspartan/gotui/v5"
+	"golang.org/x/net/websocket"
+)
+
+// Session describes a single browser connection.
+type Session struct {
+	RemoteAddr string
+	Request    *http.Request
+	Mode       Mode
+	UI         *ui.Context
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	ws     *websocket.Conn
+	wmu    sync.Mutex
+	screen *screen
+	tty    *wsTTY
+}
+
+// clientMessage is a message sent by the browser.
+type clientMessage struct {
+	Type   string `json:"type"`
+	Data   string `json:"data"`
+	Cols   int    `json:"cols"`
+	Rows   int    `json:"rows"`
+	Key    string `json:"key"`
+	Ctrl   bool   `json:"ctrl"`
+	Alt    bool   `json:"alt"`
+	Shift  bool   `json:"shift"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Button int    `json:"button"`
+	Action string `json:"action"`
+	Delta  int    `json:"delta"`
+}
+
+type helloMessage struct {
+	Type string `json:"type"`
+	Mode string `json:"mode"`
+}
+
+type errMessage struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+func errorMessage(err error) errMessage {
+	return errMessage{Type: "error", Error: err.Error()}
+}
+
+func newSession(ws *websocket.Conn, mode Mode) *Session {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := ws.Request()
+	return &Session{
+		RemoteAddr: r.RemoteAddr,
+		Request:    r,
+		Mode:       mode,
+		ctx:        ctx,
+		cancel:     cancel,
+		ws:         ws,
+	}
+}
+
+// Context returns a context that is canceled when the session ends.
+func (s *Session) Context() context.Context {
+	return s.ctx
+}
+
+// Close ends the session.
+func (s *Session) Close() {
+	s.cancel()
+}
+
+func (s *Session) send(v any) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	return websocket.JSON.Send(s.ws, v)
+}
+
+func (s *Session) sendBinary(p []byte) error {
+	s.wmu.Lock()
+	defer s.wmu.Unlock()
+	return websocket.Message.Send(s.ws, p)
+}
+
+// open creates the backend for the session mode.
+func (s *Session) open(cols, rows int) (*ui.Backend, error) {
+	if cols <= 0 || rows <= 0 {
+		cols, rows = 80, 24
+	}
+	if err := s.send(helloMessage{Type: "hello", Mode: s.Mode.String()}); err != nil {
+		return nil, err
+	}
+	if s.Mode == ModeCells {
+		sim := tcell.NewSimulationScreen("UTF-8")
+		if err := sim.Init(); err != nil {
+			return nil, err
+		}
+		sim.SetSize(cols, rows)
+		s.screen = newScreen(sim, &frameWriter{sim: sim, send: s.send})
+		return &ui.Backend{Screen: s.screen}, nil
+	}
+	s.tty = newWSTTY(s, cols, rows)
+	backend, err := ui.NewBackend(&ui.InitConfig{CustomTTY: s.tty})
+	if err != nil {
+		return nil, err
+	}
+	s.screen = newScreen(backend.Screen, nil)
+	backend.Screen = s.screen
+	return backend, nil
+}
+
+func (s *Session) readLoop() {
+	defer s.cancel()
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(s.ws, &data); err != nil {
+			return
+		}
+		var msg clientMessage
+		if json.Unmarshal(data, &msg) == nil {
+			s.handleMessage(msg)
+		}
+	}
+}
+
+func (s *Session) handleMessage(msg clientMessage) {
+	switch msg.Type {
+	case "data":
+		if s.tty != nil {
+			s.tty.push([]byte(msg.Data))
+		}
+	case "resize":
+		s.resize(msg.Cols, msg.Rows)
+	case "key":
+		if ev := keyEvent(msg); ev != nil {
+			s.screen.post(ev)
+		}
+	case "mouse":
+		s.screen.post(mouseEvent(msg))
+	}
+}
+
+func (s *Session) resize(cols, rows int) {
+	if cols <= 0 || rows <= 0 {
+		return
+	}
+	if s.tty != nil {
+		s.tty.setSize(cols, rows)
+		return
+	}
+	s.screen.SetSize(cols, rows)
+	s.screen.post(tcell.NewEventResize(cols, rows))
+}
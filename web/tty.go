@@ -0,0 +1,126 @@
+package web
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/gdamore/tcell/v3"
+)
+
+var errDrained = errors.New("web: tty drained")
+
+// wsTTY adapts a session to the tty interface expected by the backend.
+// Input is fed from "data" messages and output is sent as binary frames.
+type wsTTY struct {
+	sess    *Session
+	input   chan []byte
+	pending []byte
+
+	mu       sync.Mutex
+	width    int
+	height   int
+	resizeCh chan<- bool
+	drainQ   chan struct{}
+}
+
+func newWSTTY(sess *Session, width, height int) *wsTTY {
+	return &wsTTY{
+		sess:   sess,
+		input:  make(chan []byte),
+		drainQ: make(chan struct{}),
+		width:  width,
+		height: height,
+	}
+}
+
+func (t *wsTTY) push(data []byte) {
+	select {
+	case t.input <- data:
+	case <-t.sess.ctx.Done():
+	}
+}
+
+func (t *wsTTY) setSize(w, h int) {
+	t.mu.Lock()
+	t.width, t.height = w, h
+	ch := t.resizeCh
+	t.mu.Unlock()
+	if ch != nil {
+		select {
+		case ch <- true:
+		default:
+		}
+	}
+}
+
+func (t *wsTTY) drained() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.drainQ
+}
+
+func (t *wsTTY) Read(p []byte) (int, error) {
+	if len(t.pending) == 0 {
+		select {
+		case data := <-t.input:
+			t.pending = data
+		case <-t.sess.ctx.Done():
+			return 0, io.EOF
+		case <-t.drained():
+			return 0, errDrained
+		}
+	}
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+func (t *wsTTY) Write(p []byte) (int, error) {
+	if err := t.sess.sendBinary(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (t *wsTTY) Start() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.drainQ:
+		t.drainQ = make(chan struct{})
+	default:
+	}
+	return nil
+}
+
+func (t *wsTTY) Stop() error {
+	return nil
+}
+
+func (t *wsTTY) Drain() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.drainQ:
+	default:
+		close(t.drainQ)
+	}
+	return nil
+}
+
+func (t *wsTTY) NotifyResize(ch chan<- bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resizeCh = ch
+}
+
+func (t *wsTTY) WindowSize() (tcell.WindowSize, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return tcell.WindowSize{Width: t.width, Height: t.height}, nil
+}
+
+func (t *wsTTY) Close() error {
+	return nil
+}
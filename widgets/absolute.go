@@ -0,0 +1,104 @@
+package widgets
+
+import (
+	"image"
+
+	ui "github.com/metaspartan/gotui/v5"
+)
+
+// AbsoluteItem is a widget placed in an Absolute. It is Width x Height cells
+// pinned to Anchor of the container and moved right by OffsetX and down by
+// OffsetY. A Width or Height of 0 fills the container on that axis.
+type AbsoluteItem struct {
+	Widget  ui.Drawable
+	Anchor  ui.Anchor
+	Width   int
+	Height  int
+	OffsetX int
+	OffsetY int
+}
+
+// Absolute places its children at positions anchored to its edges or
+// center. Positions are worked out on every draw, so they follow resizes.
+// Later items are drawn over earlier ones.
+type Absolute struct {
+	ui.Block
+	Items []*AbsoluteItem
+}
+
+// NewAbsolute returns a new Absolute container.
+func NewAbsolute() *Absolute {
+	return NewAbsoluteWithContext(ui.DefaultContext)
+}
+
+// NewAbsoluteWithContext returns a new Absolute bound to ctx.
+func NewAbsoluteWithContext(ctx *ui.Context) *Absolute {
+	a := &Absolute{
+		Block: *ui.NewBlockWithContext(ctx),
+	}
+	a.Border = false
+	return a
+}
+
+// Add places widget at anchor with the given size and returns its item so
+// that the offsets can be set.
+func (a *Absolute) Add(widget ui.Drawable, anchor ui.Anchor, width, height int) *AbsoluteItem {
+	item := &AbsoluteItem{Widget: widget, Anchor: anchor, Width: width, Height: height}
+	a.Items = append(a.Items, item)
+	return item
+}
+
+// Remove removes widget from the container.
+func (a *Absolute) Remove(widget ui.Drawable) {
+	for i, item := range a.Items {
+		if item.Widget == widget {
+			a.Items = append(a.Items[:i], a.Items[i+1:]...)
+			return
+		}
+	}
+}
+
+// Rect returns where item is placed within area.
+func (item *AbsoluteItem) Rect(area image.Rectangle) image.Rectangle {
+	return ui.Place(area, item.Anchor, item.Width, item.Height, item.OffsetX, item.OffsetY)
+}
+
+func (a *Absolute) Draw(buf *ui.Buffer) {
+	a.Block.Draw(buf)
+	for _, item := range a.Items {
+		r := item.Rect(a.Inner)
+		item.Widget.SetRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+		if ui.IsHidden(item.Widget) {
+			continue
+		}
+		item.Widget.Lock()
+		buf.PushClip(r)
+		item.Widget.Draw(buf)
+		buf.PopClip()
+		item.Widget.Unlock()
+	}
+}
+
+// Children returns the widgets of the items, implementing ui.Container.
+func (a *Absolute) Children() []ui.Drawable {
+	children := make([]ui.Drawable, len(a.Items))
+	for i, item := range a.Items {
+		children[i] = item.Widget
+	}
+	return children
+}
+
+// HandleEvent forwards mouse events to the topmost item under the pointer.
+func (a *Absolute) HandleEvent(e ui.Event) bool {
+	m, ok := e.Payload.(ui.Mouse)
+	if !ok {
+		return false
+	}
+	for i := len(a.Items) - 1; i >= 0; i-- {
+		w, ok := a.Items[i].Widget.(ui.Widget)
+		if ok && !ui.IsHidden(w) && image.Pt(m.X, m.Y).In(w.GetRect()) {
+			return w.HandleEvent(e)
+		}
+	}
+	return false
+}
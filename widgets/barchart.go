@@ -20,23 +20,43 @@ type BarChart struct {
 	BarWidth     int
 	BarGap       int
 	MaxVal       float64
+	// Gradient, when enabled, colors the bars in place of BarColors. With
+	// GradientValue each bar takes the color for its height.
+	Gradient ui.Gradient
 }
 
 // NewBarChart returns a new BarChart.
 func NewBarChart() *BarChart {
+	return NewBarChartWithContext(ui.DefaultContext)
+}
+
+// NewBarChartWithContext returns a new BarChart bound to ctx.
+func NewBarChartWithContext(ctx *ui.Context) *BarChart {
+	theme := ctx.CurrentTheme()
 	return &BarChart{
-		Block:        *ui.NewBlock(),
-		BarColors:    ui.Theme.BarChart.Bars,
-		NumStyles:    ui.Theme.BarChart.Nums,
-		LabelStyles:  ui.Theme.BarChart.Labels,
+		Block:        *ui.NewBlockWithContext(ctx),
+		BarColors:    theme.BarChart.Bars,
+		NumStyles:    theme.BarChart.Nums,
+		LabelStyles:  theme.BarChart.Labels,
 		NumFormatter: func(n float64) string { return fmt.Sprint(n) },
 		BarGap:       1,
 		BarWidth:     3,
 	}
 }
 
+func (bc *BarChart) restyle() {
+	old, cur, ok := bc.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.RestyleSlice(&bc.BarColors, old.BarChart.Bars, cur.BarChart.Bars)
+	ui.RestyleSlice(&bc.NumStyles, old.BarChart.Nums, cur.BarChart.Nums)
+	ui.RestyleSlice(&bc.LabelStyles, old.BarChart.Labels, cur.BarChart.Labels)
+}
+
 // Draw draws the bar chart to the buffer.
 func (bc *BarChart) Draw(buf *ui.Buffer) {
+	bc.restyle()
 	bc.Block.Draw(buf)
 	maxVal := bc.MaxVal
 	if maxVal == 0 {
@@ -52,7 +72,7 @@ func (bc *BarChart) Draw(buf *ui.Buffer) {
 			height := int((data / maxVal) * float64(bc.Inner.Dy()-1))
 			for x := barXCoordinate; x < ui.MinInt(barXCoordinate+barWidth, bc.Inner.Max.X); x++ {
 				for y := bc.Inner.Max.Y - 2; y > (bc.Inner.Max.Y-2)-height; y-- {
-					c := ui.NewCell(' ', ui.NewStyle(ui.ColorClear, ui.SelectColor(bc.BarColors, i)))
+					c := ui.NewCell(' ', ui.NewStyle(ui.ColorClear, bc.barColorAt(i, data/maxVal, image.Pt(x, y))))
 					buf.SetCell(c, image.Pt(x, y))
 				}
 			}
@@ -73,7 +93,7 @@ func (bc *BarChart) Draw(buf *ui.Buffer) {
 				bc.NumFormatter(data),
 				ui.NewStyle(
 					ui.SelectStyle(bc.NumStyles, i+1).Fg,
-					ui.SelectColor(bc.BarColors, i),
+					bc.barColorAt(i, data/maxVal, image.Pt(numberXCoordinate, bc.Inner.Max.Y-2)),
 					ui.SelectStyle(bc.NumStyles, i+1).Modifier,
 				),
 				image.Pt(numberXCoordinate, bc.Inner.Max.Y-2),
@@ -82,3 +102,15 @@ func (bc *BarChart) Draw(buf *ui.Buffer) {
 		barXCoordinate += (barWidth + bc.BarGap)
 	}
 }
+
+// barColorAt returns the color of bar i, whose height is the fraction
+// value of the chart, at p.
+func (bc *BarChart) barColorAt(i int, value float64, p image.Point) ui.Color {
+	switch {
+	case !bc.Gradient.Enabled:
+		return ui.SelectColor(bc.BarColors, i)
+	case bc.Gradient.Direction == ui.GradientValue:
+		return bc.Gradient.At(value)
+	}
+	return bc.Gradient.ColorAt(p, bc.Inner)
+}
@@ -20,8 +20,13 @@ type Button struct {
 
 // NewButton returns a new Button with the given text.
 func NewButton(text string) *Button {
+	return NewButtonWithContext(ui.DefaultContext, text)
+}
+
+// NewButtonWithContext returns a new Button bound to ctx.
+func NewButtonWithContext(ctx *ui.Context, text string) *Button {
 	return &Button{
-		Block:       *ui.NewBlock(),
+		Block:       *ui.NewBlockWithContext(ctx),
 		Text:        text,
 		TextStyle:   ui.NewStyle(ui.ColorWhite),
 		ActiveStyle: ui.NewStyle(ui.ColorBlack, ui.ColorGreen),
@@ -54,6 +59,15 @@ func (b *Button) Draw(buf *ui.Buffer) {
 	buf.SetString(str, style, image.Pt(x, y))
 }
 
+// StyleStates reports "active" while the button is active, implementing
+// ui.Stateful.
+func (b *Button) StyleStates() []string {
+	if b.IsActive {
+		return []string{"active"}
+	}
+	return nil
+}
+
 func (b *Button) Activate() {
 	b.IsActive = true
 }
@@ -18,18 +18,35 @@ type Calendar struct {
 }
 
 func NewCalendar() *Calendar {
-	now := time.Now()
+	return NewCalendarWithContext(ui.DefaultContext)
+}
+
+// NewCalendarWithContext returns a new Calendar bound to ctx.
+func NewCalendarWithContext(ctx *ui.Context) *Calendar {
+	theme := ctx.CurrentTheme()
+	now := ctx.Now()
 	return &Calendar{
-		Block:       *ui.NewBlock(),
+		Block:       *ui.NewBlockWithContext(ctx),
 		Month:       now.Month(),
 		Year:        now.Year(),
 		CurrentDay:  now.Day(),
 		SelectedDay: now.Day(),
-		HeaderStyle: ui.Theme.Block.Title,
-		DayStyle:    ui.Theme.Paragraph.Text,
+		HeaderStyle: theme.Block.Title,
+		DayStyle:    theme.Paragraph.Text,
+	}
+}
+
+func (c *Calendar) restyle() {
+	old, cur, ok := c.ThemeChanged()
+	if !ok {
+		return
 	}
+	ui.Restyle(&c.HeaderStyle, old.Block.Title, cur.Block.Title)
+	ui.Restyle(&c.DayStyle, old.Paragraph.Text, cur.Paragraph.Text)
 }
+
 func (c *Calendar) Draw(buf *ui.Buffer) {
+	c.restyle()
 	c.Block.Draw(buf)
 	header := fmt.Sprintf("%s %d", c.Month, c.Year)
 	headerX := c.Inner.Min.X + (c.Inner.Dx()-len(header))/2
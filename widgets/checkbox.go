@@ -17,8 +17,13 @@ type Checkbox struct {
 
 // NewCheckbox returns a new Checkbox with the given label.
 func NewCheckbox(label string) *Checkbox {
+	return NewCheckboxWithContext(ui.DefaultContext, label)
+}
+
+// NewCheckboxWithContext returns a new Checkbox bound to ctx.
+func NewCheckboxWithContext(ctx *ui.Context, label string) *Checkbox {
 	return &Checkbox{
-		Block:       *ui.NewBlock(),
+		Block:       *ui.NewBlockWithContext(ctx),
 		Label:       label,
 		CheckedRune: 'x',
 		TextStyle:   ui.NewStyle(ui.ColorWhite),
@@ -43,6 +48,15 @@ func (c *Checkbox) Draw(buf *ui.Buffer) {
 	}
 }
 
+// StyleStates reports "checked" while the box is checked, implementing
+// ui.Stateful.
+func (c *Checkbox) StyleStates() []string {
+	if c.Checked {
+		return []string{"checked"}
+	}
+	return nil
+}
+
 func (c *Checkbox) Toggle() {
 	c.Checked = !c.Checked
 }
@@ -14,85 +14,386 @@ const (
 	FlexColumn
 )
 
-type flexItem struct {
+// FlexJustify controls how free space on the main axis is distributed.
+type FlexJustify int
+
+const (
+	FlexJustifyStart FlexJustify = iota
+	FlexJustifyCenter
+	FlexJustifyEnd
+	FlexJustifySpaceBetween
+)
+
+// FlexAlign controls how items are placed on the cross axis.
+type FlexAlign int
+
+const (
+	FlexAlignStretch FlexAlign = iota
+	FlexAlignStart
+	FlexAlignCenter
+	FlexAlignEnd
+)
+
+// FlexItem is a widget placed in a Flex. MinSize and MaxSize bound its
+// main axis size and CrossSize is used when items are not stretched.
+type FlexItem struct {
 	Widget     ui.Drawable
 	FixedSize  int
 	Proportion int
+	Constraint *ui.Constraint
+	MinSize    int
+	MaxSize    int
+	CrossSize  int
 	Focus      bool
 }
 
 // Flex represents a flex container widget.
 type Flex struct {
 	ui.Block
-	Items     []*flexItem
-	Direction FlexDirection
+	Items        []*FlexItem
+	Direction    FlexDirection
+	Gap          int
+	Justify      FlexJustify
+	AlignItems   FlexAlign
+	Wrap         bool
+	MergeBorders bool
 }
 
 // NewFlex returns a new Flex container.
 func NewFlex() *Flex {
+	return NewFlexWithContext(ui.DefaultContext)
+}
+
+// NewFlexWithContext returns a new Flex bound to ctx.
+func NewFlexWithContext(ctx *ui.Context) *Flex {
 	return &Flex{
-		Block:     *ui.NewBlock(),
-		Items:     make([]*flexItem, 0),
+		Block:     *ui.NewBlockWithContext(ctx),
+		Items:     make([]*FlexItem, 0),
 		Direction: FlexColumn,
 	}
 }
 
 // AddItem adds a new widget to the flex container.
-func (f *Flex) AddItem(widget ui.Drawable, fixedSize, proportion int, focus bool) {
-	f.Items = append(f.Items, &flexItem{
+func (f *Flex) AddItem(widget ui.Drawable, fixedSize, proportion int, focus bool) *FlexItem {
+	return f.add(&FlexItem{
 		Widget:     widget,
 		FixedSize:  fixedSize,
 		Proportion: proportion,
 		Focus:      focus,
 	})
 }
+
+// AddItemWithConstraint adds a new widget sized by c to the flex container.
+func (f *Flex) AddItemWithConstraint(widget ui.Drawable, c ui.Constraint, focus bool) *FlexItem {
+	return f.add(&FlexItem{
+		Widget:     widget,
+		Constraint: &c,
+		Focus:      focus,
+	})
+}
+
+func (f *Flex) add(item *FlexItem) *FlexItem {
+	if item.Focus {
+		f.clearFocus()
+	}
+	f.Items = append(f.Items, item)
+	return item
+}
+
+func (item *FlexItem) constraint() ui.Constraint {
+	if item.Constraint != nil {
+		return *item.Constraint
+	}
+	if item.FixedSize > 0 {
+		return ui.Length(item.FixedSize)
+	}
+	return ui.Fill(item.Proportion)
+}
+
+// basis is the main axis size an item needs when lines are wrapped.
+func (item *FlexItem) basis(total int) int {
+	return max(item.constraint().Preferred(total), item.MinSize)
+}
+
+func (item *FlexItem) clamp(size int) (int, bool) {
+	if item.MinSize > 0 && size < item.MinSize {
+		return item.MinSize, true
+	}
+	if item.MaxSize > 0 && size > item.MaxSize {
+		return item.MaxSize, true
+	}
+	return size, false
+}
+
 func (f *Flex) Draw(buf *ui.Buffer) {
 	f.Block.Draw(buf)
-	rect := f.Inner
-	totalSize := rect.Dx()
-	if f.Direction == FlexColumn {
-		totalSize = rect.Dy()
-	}
-	usedSize := 0
-	totalProportion := 0
-	for _, item := range f.Items {
-		if item.FixedSize > 0 {
-			usedSize += item.FixedSize
+	for _, p := range f.layout() {
+		p.item.Widget.SetRect(p.rect.Min.X, p.rect.Min.Y, p.rect.Max.X, p.rect.Max.Y)
+		if ui.IsHidden(p.item.Widget) {
+			continue
+		}
+		if f.MergeBorders {
+			ui.DrawMerged(buf, p.item.Widget)
 		} else {
-			totalProportion += item.Proportion
+			buf.PushClip(p.rect)
+			p.item.Widget.Draw(buf)
+			buf.PopClip()
 		}
 	}
-	remainingSize := max(totalSize-usedSize, 0)
-	currentPos := 0
+}
+
+type flexPlacement struct {
+	item *FlexItem
+	rect image.Rectangle
+}
+
+// gap returns the space between items; merged borders overlap by one cell.
+func (f *Flex) gap() int {
+	if f.MergeBorders && f.Gap == 0 {
+		return -1
+	}
+	return f.Gap
+}
+
+func (f *Flex) axes() (mainSize, crossSize int) {
+	if f.Direction == FlexRow {
+		return f.Inner.Dx(), f.Inner.Dy()
+	}
+	return f.Inner.Dy(), f.Inner.Dx()
+}
+
+func (f *Flex) rect(mainPos, mainSize, crossPos, crossSize int) image.Rectangle {
 	if f.Direction == FlexRow {
-		currentPos = rect.Min.X
-	} else {
-		currentPos = rect.Min.Y
+		return image.Rect(mainPos, crossPos, mainPos+mainSize, crossPos+crossSize).Add(f.Inner.Min)
+	}
+	return image.Rect(crossPos, mainPos, crossPos+crossSize, mainPos+mainSize).Add(f.Inner.Min)
+}
+
+func (f *Flex) layout() []flexPlacement {
+	mainTotal, crossTotal := f.axes()
+	lines := f.lines(mainTotal)
+	lineSizes := ui.SolveLayout(crossTotal-f.gap()*(len(lines)-1), f.lineConstraints(lines)...)
+	var out []flexPlacement
+	crossPos := 0
+	for i, line := range lines {
+		avail := mainTotal - f.gap()*(len(line)-1)
+		sizes := f.mainSizes(line, avail)
+		for j, mainPos := range f.positions(sizes, avail) {
+			if sizes[j] <= 0 || lineSizes[i] <= 0 {
+				continue
+			}
+			pos, size := f.crossPlacement(line[j], lineSizes[i])
+			out = append(out, flexPlacement{line[j], f.rect(mainPos, sizes[j], crossPos+pos, size)})
+		}
+		crossPos += lineSizes[i] + f.gap()
+	}
+	return out
+}
+
+// lines splits the items into lines that fit mainTotal when wrapping.
+func (f *Flex) lines(mainTotal int) [][]*FlexItem {
+	if !f.Wrap || len(f.Items) == 0 {
+		return [][]*FlexItem{f.Items}
 	}
+	var lines [][]*FlexItem
+	var line []*FlexItem
+	used := 0
 	for _, item := range f.Items {
+		need := item.basis(mainTotal)
+		if len(line) > 0 {
+			need += f.gap()
+		}
+		if len(line) > 0 && used+need > mainTotal {
+			lines = append(lines, line)
+			line, used, need = nil, 0, item.basis(mainTotal)
+		}
+		line = append(line, item)
+		used += need
+	}
+	return append(lines, line)
+}
+
+// lineConstraints sizes lines by their items' CrossSize when items are not
+// stretched, and shares the cross axis equally otherwise.
+func (f *Flex) lineConstraints(lines [][]*FlexItem) []ui.Constraint {
+	cs := make([]ui.Constraint, len(lines))
+	for i, line := range lines {
+		cs[i] = ui.Fill(1)
+		if f.AlignItems == FlexAlignStretch || !f.Wrap {
+			continue
+		}
 		size := 0
-		if item.FixedSize > 0 {
-			size = item.FixedSize
-		} else if totalProportion > 0 {
-			size = remainingSize * item.Proportion / totalProportion
-		}
-		var childRect image.Rectangle
-		if f.Direction == FlexRow {
-			if currentPos+size > rect.Max.X {
-				size = rect.Max.X - currentPos
-			}
-			childRect = image.Rect(currentPos, rect.Min.Y, currentPos+size, rect.Max.Y)
-			currentPos += size
-		} else {
-			if currentPos+size > rect.Max.Y {
-				size = rect.Max.Y - currentPos
+		for _, item := range line {
+			if item.CrossSize <= 0 {
+				size = -1
+				break
 			}
-			childRect = image.Rect(rect.Min.X, currentPos, rect.Max.X, currentPos+size)
-			currentPos += size
+			size = max(size, item.CrossSize)
 		}
 		if size > 0 {
-			item.Widget.SetRect(childRect.Min.X, childRect.Min.Y, childRect.Max.X, childRect.Max.Y)
-			item.Widget.Draw(buf)
+			cs[i] = ui.Length(size)
+		}
+	}
+	return cs
+}
+
+// mainSizes solves the main axis of a line, pinning items that break their
+// MinSize or MaxSize and solving again for the rest.
+func (f *Flex) mainSizes(line []*FlexItem, avail int) []int {
+	cs := make([]ui.Constraint, len(line))
+	for i, item := range line {
+		cs[i] = item.constraint()
+	}
+	sizes := ui.SolveLayout(avail, cs...)
+	for range line {
+		changed := false
+		for i, item := range line {
+			if size, ok := item.clamp(sizes[i]); ok {
+				cs[i] = ui.Length(size)
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		sizes = ui.SolveLayout(avail, cs...)
+	}
+	return sizes
+}
+
+// positions returns the main axis offset of every item after distributing
+// the free space according to Justify.
+func (f *Flex) positions(sizes []int, avail int) []int {
+	free := avail
+	for _, s := range sizes {
+		free -= s
+	}
+	free = max(free, 0)
+	start, between, extra := 0, 0, 0
+	switch f.Justify {
+	case FlexJustifyCenter:
+		start = free / 2
+	case FlexJustifyEnd:
+		start = free
+	case FlexJustifySpaceBetween:
+		if len(sizes) > 1 {
+			between, extra = free/(len(sizes)-1), free%(len(sizes)-1)
+		}
+	}
+	out := make([]int, len(sizes))
+	pos := start
+	for i, s := range sizes {
+		out[i] = pos
+		pos += s + f.gap() + between
+		if i < extra {
+			pos++
+		}
+	}
+	return out
+}
+
+func (f *Flex) crossPlacement(item *FlexItem, lineSize int) (int, int) {
+	if f.AlignItems == FlexAlignStretch || item.CrossSize <= 0 {
+		return 0, lineSize
+	}
+	size := min(item.CrossSize, lineSize)
+	switch f.AlignItems {
+	case FlexAlignCenter:
+		return (lineSize - size) / 2, size
+	case FlexAlignEnd:
+		return lineSize - size, size
+	}
+	return 0, size
+}
+
+// HandleEvent forwards mouse events to the item under the pointer, focusing
+// it on a left click, and keyboard events to the focused item.
+func (f *Flex) HandleEvent(e ui.Event) bool {
+	switch e.Type {
+	case ui.MouseEvent:
+		m, ok := e.Payload.(ui.Mouse)
+		if !ok {
+			return false
+		}
+		for i, item := range f.Items {
+			w, ok := item.Widget.(ui.Widget)
+			if !ok || !image.Pt(m.X, m.Y).In(w.GetRect()) {
+				continue
+			}
+			if e.ID == "<MouseLeft>" {
+				f.SetFocusedItem(i)
+			}
+			return w.HandleEvent(e)
 		}
+	case ui.KeyboardEvent:
+		if w := f.FocusedChild(); w != nil {
+			return w.HandleEvent(e)
+		}
+	}
+	return false
+}
+
+// Children returns the widgets of the items, implementing ui.Container.
+func (f *Flex) Children() []ui.Drawable {
+	children := make([]ui.Drawable, len(f.Items))
+	for i, item := range f.Items {
+		children[i] = item.Widget
+	}
+	return children
+}
+
+// FocusedChild returns the focused item, implementing ui.FocusContainer.
+func (f *Flex) FocusedChild() ui.Widget {
+	for _, item := range f.Items {
+		if w, ok := item.Widget.(ui.Widget); ok && item.Focus {
+			return w
+		}
+	}
+	return nil
+}
+
+// SetFocusedItem focuses the i-th item.
+func (f *Flex) SetFocusedItem(i int) {
+	if i < 0 || i >= len(f.Items) {
+		return
+	}
+	f.clearFocus()
+	f.Items[i].Focus = true
+}
+
+// FocusNext moves focus to the next item that handles events.
+func (f *Flex) FocusNext() {
+	f.moveFocus(1)
+}
+
+// FocusPrev moves focus to the previous item that handles events.
+func (f *Flex) FocusPrev() {
+	f.moveFocus(-1)
+}
+
+func (f *Flex) moveFocus(step int) {
+	n := len(f.Items)
+	cur := -1
+	for i, item := range f.Items {
+		if item.Focus {
+			cur = i
+			break
+		}
+	}
+	if cur < 0 && step < 0 {
+		cur = n
+	}
+	for k := 1; k <= n; k++ {
+		i := ((cur+step*k)%n + n) % n
+		if _, ok := f.Items[i].Widget.(ui.Widget); ok {
+			f.SetFocusedItem(i)
+			return
+		}
+	}
+}
+
+func (f *Flex) clearFocus() {
+	for _, item := range f.Items {
+		item.Focus = false
 	}
 }
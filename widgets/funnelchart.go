@@ -17,15 +17,30 @@ type FunnelChart struct {
 
 // NewFunnelChart returns a new FunnelChart.
 func NewFunnelChart() *FunnelChart {
+	return NewFunnelChartWithContext(ui.DefaultContext)
+}
+
+// NewFunnelChartWithContext returns a new FunnelChart bound to ctx.
+func NewFunnelChartWithContext(ctx *ui.Context) *FunnelChart {
+	theme := ctx.CurrentTheme()
 	return &FunnelChart{
-		Block:         *ui.NewBlock(),
+		Block:         *ui.NewBlockWithContext(ctx),
 		UniformHeight: true,
-		Colors:        ui.Theme.BarChart.Bars,
+		Colors:        theme.BarChart.Bars,
+	}
+}
+
+func (fc *FunnelChart) restyle() {
+	old, cur, ok := fc.ThemeChanged()
+	if !ok {
+		return
 	}
+	ui.RestyleSlice(&fc.Colors, old.BarChart.Bars, cur.BarChart.Bars)
 }
 
 // Draw draws the funnel chart to the buffer.
 func (fc *FunnelChart) Draw(buf *ui.Buffer) {
+	fc.restyle()
 	fc.Block.Draw(buf)
 	if len(fc.Data) == 0 {
 		return
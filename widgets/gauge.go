@@ -20,16 +20,32 @@ type Gauge struct {
 
 // NewGauge returns a new Gauge.
 func NewGauge() *Gauge {
+	return NewGaugeWithContext(ui.DefaultContext)
+}
+
+// NewGaugeWithContext returns a new Gauge bound to ctx.
+func NewGaugeWithContext(ctx *ui.Context) *Gauge {
+	theme := ctx.CurrentTheme()
 	return &Gauge{
-		Block:         *ui.NewBlock(),
-		BarColor:      ui.Theme.Gauge.Bar,
-		LabelStyle:    ui.Theme.Gauge.Label,
+		Block:         *ui.NewBlockWithContext(ctx),
+		BarColor:      theme.Gauge.Bar,
+		LabelStyle:    theme.Gauge.Label,
 		BarLabelStyle: ui.NewStyle(ui.ColorBlack), // Default: black text, uses bar color as bg
 	}
 }
 
+func (g *Gauge) restyle() {
+	old, cur, ok := g.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&g.BarColor, old.Gauge.Bar, cur.Gauge.Bar)
+	ui.Restyle(&g.LabelStyle, old.Gauge.Label, cur.Gauge.Label)
+}
+
 // Draw draws the gauge to the buffer.
 func (g *Gauge) Draw(buf *ui.Buffer) {
+	g.restyle()
 	g.Block.Draw(buf)
 
 	label := g.Label
@@ -44,39 +60,26 @@ func (g *Gauge) Draw(buf *ui.Buffer) {
 }
 
 func (g *Gauge) drawBar(buf *ui.Buffer, barWidth int) {
-	if g.Gradient.Enabled {
-		var gradientColors []ui.Color
-		if g.Gradient.Direction == 1 {
-			gradientColors = ui.GenerateGradient(g.Gradient.Start, g.Gradient.End, g.Inner.Dy())
-		} else if barWidth > 0 {
-			gradientColors = ui.GenerateGradient(g.Gradient.Start, g.Gradient.End, barWidth)
+	for x := g.Inner.Min.X; x < g.Inner.Min.X+barWidth; x++ {
+		for y := g.Inner.Min.Y; y < g.Inner.Max.Y; y++ {
+			p := image.Pt(x, y)
+			buf.SetCell(ui.NewCell(' ', ui.NewStyle(ui.ColorClear, g.barColorAt(p, barWidth))), p)
 		}
+	}
+}
 
-		for i := range barWidth {
-			for y := g.Inner.Min.Y; y < g.Inner.Max.Y; y++ {
-				color := ui.ColorClear
-				if g.Gradient.Direction == 1 {
-					relativeY := y - g.Inner.Min.Y
-					if relativeY < len(gradientColors) {
-						color = gradientColors[relativeY]
-					}
-				} else {
-					if i < len(gradientColors) {
-						color = gradientColors[i]
-					}
-				}
-				buf.SetCell(
-					ui.NewCell(' ', ui.NewStyle(ui.ColorClear, color)),
-					image.Pt(g.Inner.Min.X+i, y),
-				)
-			}
-		}
-	} else {
-		buf.Fill(
-			ui.NewCell(' ', ui.NewStyle(ui.ColorClear, g.BarColor)),
-			image.Rect(g.Inner.Min.X, g.Inner.Min.Y, g.Inner.Min.X+barWidth, g.Inner.Max.Y),
-		)
+// barColorAt returns the color of the bar at p. A gradient spans the
+// filled part of the bar, or with GradientValue colors all of it by
+// Percent.
+func (g *Gauge) barColorAt(p image.Point, barWidth int) ui.Color {
+	if !g.Gradient.Enabled {
+		return g.BarColor
+	}
+	if g.Gradient.Direction == ui.GradientValue {
+		return g.Gradient.At(float64(g.Percent) / 100)
 	}
+	bar := image.Rect(g.Inner.Min.X, g.Inner.Min.Y, g.Inner.Min.X+barWidth, g.Inner.Max.Y)
+	return g.Gradient.ColorAt(p, bar)
 }
 
 func (g *Gauge) drawLabel(buf *ui.Buffer, label string, barWidth int) {
@@ -87,35 +90,20 @@ func (g *Gauge) drawLabel(buf *ui.Buffer, label string, barWidth int) {
 		return
 	}
 
-	var gradientColors []ui.Color
-	if g.Gradient.Enabled {
-		if g.Gradient.Direction == 1 {
-			gradientColors = ui.GenerateGradient(g.Gradient.Start, g.Gradient.End, g.Inner.Dy())
-		} else {
-			gradientColors = ui.GenerateGradient(g.Gradient.Start, g.Gradient.End, barWidth)
-		}
-	}
-
 	for i, char := range label {
 		style := g.LabelStyle
-		barX := labelXCoordinate + i - g.Inner.Min.X
+		p := image.Pt(labelXCoordinate+i, labelYCoordinate)
+		barX := p.X - g.Inner.Min.X
 
 		if barX >= 0 && barX < barWidth {
 			if g.Gradient.Enabled {
-				if g.Gradient.Direction == 1 {
-					relativeY := labelYCoordinate - g.Inner.Min.Y
-					if relativeY >= 0 && relativeY < len(gradientColors) {
-						style = ui.NewStyle(ui.ColorWhite, gradientColors[relativeY])
-					}
-				} else if barX < len(gradientColors) {
-					style = ui.NewStyle(ui.ColorWhite, gradientColors[barX])
-				}
+				style = ui.NewStyle(ui.ColorWhite, g.barColorAt(p, barWidth))
 			} else {
 				// Use BarLabelStyle foreground with bar color as background
 				// to avoid terminal rendering quirks with ColorClear/Default
 				style = ui.NewStyle(g.BarLabelStyle.Fg, g.BarColor)
 			}
 		}
-		buf.SetCell(ui.NewCell(char, style), image.Pt(labelXCoordinate+i, labelYCoordinate))
+		buf.SetCell(ui.NewCell(char, style), p)
 	}
 }
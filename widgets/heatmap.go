@@ -20,17 +20,32 @@ type Heatmap struct {
 
 // NewHeatmap returns a new Heatmap.
 func NewHeatmap() *Heatmap {
+	return NewHeatmapWithContext(ui.DefaultContext)
+}
+
+// NewHeatmapWithContext returns a new Heatmap bound to ctx.
+func NewHeatmapWithContext(ctx *ui.Context) *Heatmap {
+	theme := ctx.CurrentTheme()
 	return &Heatmap{
-		Block:     *ui.NewBlock(),
+		Block:     *ui.NewBlockWithContext(ctx),
 		CellWidth: 3,
 		CellGap:   1,
 		Colors:    []ui.Color{ui.ColorBlack, ui.ColorRed, ui.ColorYellow, ui.ColorWhite},
-		TextColor: ui.Theme.Paragraph.Text,
+		TextColor: theme.Paragraph.Text,
+	}
+}
+
+func (h *Heatmap) restyle() {
+	old, cur, ok := h.ThemeChanged()
+	if !ok {
+		return
 	}
+	ui.Restyle(&h.TextColor, old.Paragraph.Text, cur.Paragraph.Text)
 }
 
 // Draw draws the heatmap to the buffer.
 func (h *Heatmap) Draw(buf *ui.Buffer) {
+	h.restyle()
 	h.Block.Draw(buf)
 	if len(h.Data) == 0 {
 		return
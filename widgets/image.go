@@ -18,8 +18,13 @@ type Image struct {
 
 // NewImage returns a new Image.
 func NewImage(img image.Image) *Image {
+	return NewImageWithContext(ui.DefaultContext, img)
+}
+
+// NewImageWithContext returns a new Image bound to ctx.
+func NewImageWithContext(ctx *ui.Context, img image.Image) *Image {
 	return &Image{
-		Block:               *ui.NewBlock(),
+		Block:               *ui.NewBlockWithContext(ctx),
 		MonochromeThreshold: 128,
 		Image:               img,
 	}
@@ -4,7 +4,6 @@ import (
 	"image"
 	"sync"
 
-	rw "github.com/mattn/go-runewidth"
 	ui "github.com/metaspartan/gotui/v5"
 )
 
@@ -17,7 +16,12 @@ type Input struct {
 	Placeholder string
 	EchoMode    EchoMode
 	Cursor      int
-	offset      int
+	// Direction is the direction of the text. Right-to-left text is
+	// aligned to the right, and the cursor moves across runs of either
+	// direction as they are shown. DirectionAuto takes it from the first
+	// letter.
+	Direction ui.Direction
+	offset    int
 	sync.Mutex
 }
 type EchoMode int
@@ -29,34 +33,59 @@ const (
 
 // NewInput returns a new Input.
 func NewInput() *Input {
+	return NewInputWithContext(ui.DefaultContext)
+}
+
+// NewInputWithContext returns a new Input bound to ctx.
+func NewInputWithContext(ctx *ui.Context) *Input {
+	theme := ctx.CurrentTheme()
 	return &Input{
-		Block:       *ui.NewBlock(),
-		TextStyle:   ui.Theme.Paragraph.Text,
+		Block:       *ui.NewBlockWithContext(ctx),
+		TextStyle:   theme.Paragraph.Text,
 		CursorStyle: ui.NewStyle(ui.ColorBlack, ui.ColorWhite),
 		EchoMode:    EchoNormal,
 	}
 }
 
+func (i *Input) restyle() {
+	old, cur, ok := i.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&i.TextStyle, old.Paragraph.Text, cur.Paragraph.Text)
+}
+
 // Draw draws the input to the buffer.
 func (i *Input) Draw(buf *ui.Buffer) {
+	i.restyle()
 	i.Block.Draw(buf)
 	rect := i.Inner
-	width := rect.Dx()
-	runes := []rune(i.Text)
-	if i.EchoMode == EchoPassword {
-		passwordRunes := make([]rune, len(runes))
-		for j := range passwordRunes {
-			passwordRunes[j] = '*'
-		}
-		runes = passwordRunes
-	}
-	if len(runes) == 0 && i.Placeholder != "" {
+	cells := i.cells()
+	if len(cells) == 0 && i.Placeholder != "" {
 		i.drawPlaceholder(buf, rect)
 	}
-	cursorVisualX := i.calculateOffset(runes, width)
-	i.drawText(buf, rect, runes)
+	i.Cursor = max(0, min(i.Cursor, len(cells)))
+	dir := ui.ResolveDirection(i.Direction, cells)
+	line := ui.ReorderCells(cells, dir)
+	cursorVisualX := i.calculateOffset(line, ui.CaretPosition(cells, dir, i.Cursor), dir, rect.Dx())
+	i.drawText(buf, rect, line)
 	i.drawCursor(buf, rect, cursorVisualX)
 }
+
+// cells returns the text as it is shown, one cell per rune, in logical
+// order.
+func (i *Input) cells() []ui.Cell {
+	runes := []rune(i.Text)
+	cells := make([]ui.Cell, len(runes))
+	for j, r := range runes {
+		if i.EchoMode == EchoPassword {
+			r = '*'
+		}
+		cells[j] = ui.NewCell(r, i.TextStyle)
+	}
+	return cells
+}
+
 func (i *Input) drawPlaceholder(buf *ui.Buffer, rect image.Rectangle) {
 	buf.SetString(
 		i.Placeholder,
@@ -64,16 +93,17 @@ func (i *Input) drawPlaceholder(buf *ui.Buffer, rect image.Rectangle) {
 		image.Pt(rect.Min.X, rect.Min.Y),
 	)
 }
-func (i *Input) calculateOffset(runes []rune, width int) int {
-	if i.Cursor < 0 {
-		i.Cursor = 0
-	}
-	if i.Cursor > len(runes) {
-		i.Cursor = len(runes)
-	}
-	cursorVisualX := 0
-	for j := 0; j < i.Cursor; j++ {
-		cursorVisualX += rw.RuneWidth(runes[j])
+
+// calculateOffset scrolls the line so that the cursor, which is on the
+// cell at caret in visual order, shows, and returns the column of the
+// cursor. Right-to-left text that fits is aligned to the right.
+func (i *Input) calculateOffset(line []ui.Cell, caret int, dir ui.Direction, width int) int {
+	cursorVisualX := -1
+	if caret >= 0 {
+		cursorVisualX = 0
+		for _, c := range line[:caret] {
+			cursorVisualX += c.ColumnWidth()
+		}
 	}
 	if cursorVisualX-i.offset >= width {
 		i.offset = cursorVisualX - width + 1
@@ -82,25 +112,25 @@ func (i *Input) calculateOffset(runes []rune, width int) int {
 		i.offset = cursorVisualX
 	}
 	totalWidth := 0
-	for _, r := range runes {
-		totalWidth += rw.RuneWidth(r)
+	for _, c := range line {
+		totalWidth += c.ColumnWidth()
 	}
 	if totalWidth < width {
 		i.offset = 0
+		if dir == ui.DirectionRTL {
+			i.offset = totalWidth - width
+		}
 	}
 	return cursorVisualX
 }
-func (i *Input) drawText(buf *ui.Buffer, rect image.Rectangle, runes []rune) {
+func (i *Input) drawText(buf *ui.Buffer, rect image.Rectangle, line []ui.Cell) {
 	currentX := 0
-	for _, r := range runes {
-		w := rw.RuneWidth(r)
+	for _, c := range line {
+		w := c.ColumnWidth()
 		if currentX >= i.offset {
 			screenX := rect.Min.X + (currentX - i.offset)
 			if screenX+w <= rect.Max.X {
-				buf.SetCell(
-					ui.NewCell(r, i.TextStyle),
-					image.Pt(screenX, rect.Min.Y),
-				)
+				buf.SetCell(c, image.Pt(screenX, rect.Min.Y))
 			}
 		}
 		currentX += w
@@ -137,18 +167,23 @@ func (i *Input) Backspace() {
 		i.Cursor--
 	}
 }
+
+// MoveCursorLeft moves the cursor one cell to the left on screen, which in
+// right-to-left text is toward the end of the text.
 func (i *Input) MoveCursorLeft() {
 	i.Lock()
 	defer i.Unlock()
-	if i.Cursor > 0 {
-		i.Cursor--
-	}
+	i.moveCursor(-1)
 }
+
+// MoveCursorRight moves the cursor one cell to the right on screen.
 func (i *Input) MoveCursorRight() {
 	i.Lock()
 	defer i.Unlock()
-	runes := []rune(i.Text)
-	if i.Cursor < len(runes) {
-		i.Cursor++
-	}
+	i.moveCursor(1)
+}
+
+func (i *Input) moveCursor(steps int) {
+	cells := i.cells()
+	i.Cursor = ui.MoveCaret(cells, i.Direction, max(0, min(i.Cursor, len(cells))), steps)
 }
@@ -21,16 +21,32 @@ type LineGauge struct {
 
 // NewLineGauge returns a new LineGauge.
 func NewLineGauge() *LineGauge {
+	return NewLineGaugeWithContext(ui.DefaultContext)
+}
+
+// NewLineGaugeWithContext returns a new LineGauge bound to ctx.
+func NewLineGaugeWithContext(ctx *ui.Context) *LineGauge {
+	theme := ctx.CurrentTheme()
 	return &LineGauge{
-		Block:          *ui.NewBlock(),
-		LineColor:      ui.Theme.Gauge.Bar,
-		LabelStyle:     ui.Theme.Gauge.Label,
+		Block:          *ui.NewBlockWithContext(ctx),
+		LineColor:      theme.Gauge.Bar,
+		LabelStyle:     theme.Gauge.Label,
 		LabelAlignment: ui.AlignCenter,
 	}
 }
 
+func (g *LineGauge) restyle() {
+	old, cur, ok := g.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&g.LineColor, old.Gauge.Bar, cur.Gauge.Bar)
+	ui.Restyle(&g.LabelStyle, old.Gauge.Label, cur.Gauge.Label)
+}
+
 // Draw draws the line gauge to the buffer.
 func (g *LineGauge) Draw(buf *ui.Buffer) {
+	g.restyle()
 	g.Block.Draw(buf)
 	label := g.Label
 	if label == "" {
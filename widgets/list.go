@@ -3,17 +3,20 @@ package widgets
 import (
 	"image"
 
-	rw "github.com/mattn/go-runewidth"
-
 	ui "github.com/metaspartan/gotui/v5"
 )
 
 // List represents a widget that displays a list of items.
 type List struct {
 	ui.Block
-	Rows          []string
-	WrapText      bool
-	TextStyle     ui.Style
+	Rows     []string
+	WrapText bool
+	// WrapOptions sets how rows are wrapped when WrapText is set.
+	WrapOptions ui.WrapOptions
+	TextStyle   ui.Style
+	TextMode    ui.TextMode
+	// SelectedStyle replaces the style of the selected row, or is blended
+	// over it when translucent, so that the row keeps its own colors.
 	SelectedStyle ui.Style
 	TextAlignment ui.Alignment
 	SelectedRow   int
@@ -23,16 +26,32 @@ type List struct {
 
 // NewList returns a new List.
 func NewList() *List {
+	return NewListWithContext(ui.DefaultContext)
+}
+
+// NewListWithContext returns a new List bound to ctx.
+func NewListWithContext(ctx *ui.Context) *List {
+	theme := ctx.CurrentTheme()
 	return &List{
-		Block:         *ui.NewBlock(),
-		TextStyle:     ui.Theme.List.Text,
-		SelectedStyle: ui.Theme.List.Text,
+		Block:         *ui.NewBlockWithContext(ctx),
+		TextStyle:     theme.List.Text,
+		SelectedStyle: theme.List.Text,
 		TextAlignment: ui.AlignLeft,
 	}
 }
 
+func (l *List) restyle() {
+	old, cur, ok := l.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&l.TextStyle, old.List.Text, cur.List.Text)
+	ui.Restyle(&l.SelectedStyle, old.List.Text, cur.List.Text)
+}
+
 // Draw draws the list to the buffer.
 func (l *List) Draw(buf *ui.Buffer) {
+	l.restyle()
 	l.Block.Draw(buf)
 
 	if l.SelectedRow >= l.Inner.Dy()+l.topRow {
@@ -48,8 +67,8 @@ func (l *List) Draw(buf *ui.Buffer) {
 // drawRows draws the visible rows of the list to the buffer.
 func (l *List) drawRows(buf *ui.Buffer) {
 	var gradientColors []ui.Color
-	if l.Gradient.Enabled && l.Gradient.Direction == 1 {
-		gradientColors = ui.GenerateGradient(l.Gradient.Start, l.Gradient.End, l.Inner.Dy())
+	if l.Gradient.Enabled && l.Gradient.Direction == ui.GradientVertical {
+		gradientColors = l.Gradient.Colors(l.Inner.Dy())
 	}
 
 	y := l.Inner.Min.Y
@@ -61,11 +80,11 @@ func (l *List) drawRows(buf *ui.Buffer) {
 // getRowCells prepares the cells for a given row, applying styles and gradients.
 func (l *List) getRowCells(row int) []ui.Cell {
 	var cells []ui.Cell
-	if l.Gradient.Enabled && l.Gradient.Direction == 0 {
-		cells = ui.ApplyGradientToText(l.Rows[row], l.Gradient.Start, l.Gradient.End)
+	if l.Gradient.Enabled && l.Gradient.Direction == ui.GradientHorizontal {
+		cells = l.Gradient.ApplyToText(l.Rows[row])
 	} else {
-		cells = ui.ParseStyles(l.Rows[row], l.TextStyle)
-		if row == l.SelectedRow {
+		cells = ui.ParseText(l.Rows[row], l.TextStyle, l.TextMode)
+		if row == l.SelectedRow && !l.SelectedStyle.Translucent() {
 			for i := 0; i < len(cells); i++ {
 				if cells[i].Style.Fg == l.TextStyle.Fg && cells[i].Style.Bg == l.TextStyle.Bg {
 					cells[i].Style = l.SelectedStyle
@@ -80,11 +99,14 @@ func (l *List) getRowCells(row int) []ui.Cell {
 func (l *List) drawRow(buf *ui.Buffer, row int, y int, gradientColors []ui.Color) int {
 	cells := l.getRowCells(row)
 
+	var rows [][]ui.Cell
 	if l.WrapText {
-		cells = ui.WrapCells(cells, uint(l.Inner.Dx()))
+		rows = ui.BreakLines(cells, l.Inner.Dx(), l.WrapOptions)
+	} else {
+		rows = ui.SplitCells(cells, '\n')
 	}
 
-	rows := ui.SplitCells(cells, '\n')
+	top := y
 	for _, rowCells := range rows {
 		if y >= l.Inner.Max.Y {
 			break
@@ -92,6 +114,9 @@ func (l *List) drawRow(buf *ui.Buffer, row int, y int, gradientColors []ui.Color
 		l.drawRowLine(buf, row, y, rowCells, gradientColors)
 		y++
 	}
+	if row == l.SelectedRow && l.SelectedStyle.Translucent() {
+		buf.Blend(l.SelectedStyle, image.Rect(l.Inner.Min.X, top, l.Inner.Max.X, y))
+	}
 	return y
 }
 
@@ -100,7 +125,7 @@ func (l *List) drawRowLine(buf *ui.Buffer, row int, y int, rowCells []ui.Cell, g
 	xOffset := 0
 	rowWidth := 0
 	for _, c := range rowCells {
-		rowWidth += rw.RuneWidth(c.Rune)
+		rowWidth += c.ColumnWidth()
 	}
 
 	switch l.TextAlignment {
@@ -120,14 +145,21 @@ func (l *List) drawRowLine(buf *ui.Buffer, row int, y int, rowCells []ui.Cell, g
 			break
 		}
 
-		if l.Gradient.Enabled && l.Gradient.Direction == 1 {
-			relativeY := y - l.Inner.Min.Y
-			if relativeY >= 0 && relativeY < len(gradientColors) {
-				cell.Style.Fg = gradientColors[relativeY]
+		if l.Gradient.Enabled {
+			switch l.Gradient.Direction {
+			case ui.GradientHorizontal:
+				// Colored along the row in getRowCells.
+			case ui.GradientVertical:
+				relativeY := y - l.Inner.Min.Y
+				if relativeY >= 0 && relativeY < len(gradientColors) {
+					cell.Style.Fg = gradientColors[relativeY]
+				}
+			default:
+				cell.Style.Fg = l.Gradient.ColorAt(image.Pt(x, y), l.Inner)
 			}
 		}
 
-		if row == l.SelectedRow && l.Gradient.Enabled {
+		if row == l.SelectedRow && l.Gradient.Enabled && !l.SelectedStyle.Translucent() {
 			cell.Style.Modifier |= l.SelectedStyle.Modifier
 			if l.SelectedStyle.Bg != ui.ColorClear {
 				cell.Style.Bg = l.SelectedStyle.Bg
@@ -139,7 +171,7 @@ func (l *List) drawRowLine(buf *ui.Buffer, row int, y int, rowCells []ui.Cell, g
 		if x >= l.Inner.Min.X {
 			buf.SetCell(cell, image.Pt(x, y))
 		}
-		x += rw.RuneWidth(cell.Rune)
+		x += cell.ColumnWidth()
 	}
 }
 
@@ -14,8 +14,13 @@ type Logo struct {
 
 // NewLogo returns a new Logo.
 func NewLogo() *Logo {
+	return NewLogoWithContext(ui.DefaultContext)
+}
+
+// NewLogoWithContext returns a new Logo bound to ctx.
+func NewLogoWithContext(ctx *ui.Context) *Logo {
 	return &Logo{
-		Block: *ui.NewBlock(),
+		Block: *ui.NewBlockWithContext(ctx),
 		Gradient: ui.Gradient{
 			Enabled: false,
 			Start:   ui.NewRGBColor(100, 100, 255),
@@ -50,10 +55,7 @@ func (l *Logo) generateGradientColors(logoWidth, logoHeight int) []ui.Color {
 	if l.Gradient.Direction == 1 {
 		length = logoHeight
 	}
-	if len(l.Gradient.Stops) > 0 {
-		return ui.GenerateMultiGradient(length, l.Gradient.Stops...)
-	}
-	return ui.GenerateGradient(l.Gradient.Start, l.Gradient.End, length)
+	return l.Gradient.Colors(length)
 }
 
 func (l *Logo) drawLogoLines(buf *ui.Buffer, lines []string, xStart, yStart int, gradientColors []ui.Color) {
@@ -75,7 +77,7 @@ func (l *Logo) drawLogoLines(buf *ui.Buffer, lines []string, xStart, yStart int,
 
 func (l *Logo) getCharStyle(r, c int, gradientColors []ui.Color) ui.Style {
 	if gradientColors == nil {
-		return ui.NewStyle(ui.Theme.Gauge.Bar)
+		return ui.NewStyle(l.Theme().Gauge.Bar)
 	}
 	idx := c
 	if l.Gradient.Direction == 1 {
@@ -84,5 +86,5 @@ func (l *Logo) getCharStyle(r, c int, gradientColors []ui.Color) ui.Style {
 	if idx < len(gradientColors) {
 		return ui.NewStyle(gradientColors[idx])
 	}
-	return ui.NewStyle(ui.Theme.Gauge.Bar)
+	return ui.NewStyle(l.Theme().Gauge.Bar)
 }
@@ -0,0 +1,493 @@
+package widgets
+
+import (
+	"image"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	ui "github.com/metaspartan/gotui/v5"
+)
+
+// Markdown renders a CommonMark document: headings, emphasis, lists, block
+// quotes, code, links, horizontal rules and GitHub style tables. The text
+// is wrapped to the width of the widget and scrolls with OffsetY.
+type Markdown struct {
+	ui.Block
+	Text             string
+	TextStyle        ui.Style
+	HeadingStyle     ui.Style
+	CodeStyle        ui.Style
+	QuoteStyle       ui.Style
+	LinkStyle        ui.Style
+	RuleStyle        ui.Style
+	BulletStyle      ui.Style
+	TableHeaderStyle ui.Style
+	// ShowLinkURLs writes the target of each link after its text.
+	ShowLinkURLs  bool
+	OffsetY       int
+	ScrollStep    int
+	ShowScrollbar bool
+	Scrollbar     *Scrollbar
+
+	parsedText string
+	blocks     []*mdBlock
+	lineCount  int
+	viewHeight int
+}
+
+// mdLine is one row of a laid out document, or a table that takes height
+// rows.
+type mdLine struct {
+	cells  []ui.Cell
+	table  *Table
+	height int
+}
+
+// NewMarkdown returns a new Markdown.
+func NewMarkdown() *Markdown {
+	return NewMarkdownWithContext(ui.DefaultContext)
+}
+
+// NewMarkdownWithContext returns a new Markdown bound to ctx.
+func NewMarkdownWithContext(ctx *ui.Context) *Markdown {
+	bar := NewScrollbarWithContext(ctx)
+	bar.Border = false
+	th := ctx.CurrentTheme().Markdown
+	return &Markdown{
+		Block:            *ui.NewBlockWithContext(ctx),
+		TextStyle:        th.Text,
+		HeadingStyle:     th.Heading,
+		CodeStyle:        th.Code,
+		QuoteStyle:       th.Quote,
+		LinkStyle:        th.Link,
+		RuleStyle:        th.Rule,
+		BulletStyle:      th.Bullet,
+		TableHeaderStyle: th.TableHeader,
+		ShowLinkURLs:     true,
+		ScrollStep:       1,
+		ShowScrollbar:    true,
+		Scrollbar:        bar,
+	}
+}
+
+func (m *Markdown) restyle() {
+	old, cur, ok := m.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&m.TextStyle, old.Markdown.Text, cur.Markdown.Text)
+	ui.Restyle(&m.HeadingStyle, old.Markdown.Heading, cur.Markdown.Heading)
+	ui.Restyle(&m.CodeStyle, old.Markdown.Code, cur.Markdown.Code)
+	ui.Restyle(&m.QuoteStyle, old.Markdown.Quote, cur.Markdown.Quote)
+	ui.Restyle(&m.LinkStyle, old.Markdown.Link, cur.Markdown.Link)
+	ui.Restyle(&m.RuleStyle, old.Markdown.Rule, cur.Markdown.Rule)
+	ui.Restyle(&m.BulletStyle, old.Markdown.Bullet, cur.Markdown.Bullet)
+	ui.Restyle(&m.TableHeaderStyle, old.Markdown.TableHeader, cur.Markdown.TableHeader)
+}
+
+// Draw draws the document to the buffer.
+func (m *Markdown) Draw(buf *ui.Buffer) {
+	m.restyle()
+	m.Block.Draw(buf)
+
+	view := m.Inner
+	lines, total := m.layout(view.Dx())
+	bar := m.ShowScrollbar && m.Scrollbar != nil && total > view.Dy()
+	if bar {
+		view.Max.X--
+		lines, total = m.layout(view.Dx())
+	}
+	m.lineCount, m.viewHeight = total, view.Dy()
+	m.OffsetY = max(min(m.OffsetY, total-view.Dy()), 0)
+
+	buf.PushClip(view)
+	y := view.Min.Y - m.OffsetY
+	for _, line := range lines {
+		if y >= view.Max.Y {
+			break
+		}
+		if y+line.height > view.Min.Y {
+			m.drawLine(buf, line, view.Min.X, y)
+		}
+		y += line.height
+	}
+	buf.PopClip()
+
+	if bar {
+		m.Scrollbar.Max = total
+		m.Scrollbar.PageSize = view.Dy()
+		m.Scrollbar.Current = m.OffsetY
+		m.Scrollbar.SetRect(view.Max.X-1, view.Min.Y-1, view.Max.X+2, view.Max.Y+1)
+		m.Scrollbar.Draw(buf)
+	}
+}
+
+func (m *Markdown) drawLine(buf *ui.Buffer, line mdLine, x, y int) {
+	indent := 0
+	for _, cx := range ui.BuildCellWithXArray(line.cells) {
+		for dy := range line.height {
+			buf.SetCell(cx.Cell, image.Pt(x+cx.X, y+dy))
+		}
+		indent = cx.X + cx.Cell.ColumnWidth()
+	}
+	if line.table != nil {
+		w := ui.SumIntSlice(line.table.ColumnWidths) + len(line.table.ColumnWidths) - 1
+		r := image.Rect(x+indent, y, x+indent+w, y+line.height)
+		line.table.SetRect(r.Min.X-1, r.Min.Y-1, r.Max.X+1, r.Max.Y+1)
+		line.table.Draw(buf)
+	}
+}
+
+// layout parses the text if it changed and lays it out at the given width,
+// returning the lines and the number of rows they take.
+func (m *Markdown) layout(width int) ([]mdLine, int) {
+	if m.blocks == nil || m.parsedText != m.Text {
+		m.blocks = parseMarkdown(m.Text)
+		m.parsedText = m.Text
+	}
+	lines := m.layoutBlocks(m.blocks, width, nil, nil, m.TextStyle, 0)
+	total := 0
+	for _, line := range lines {
+		total += line.height
+	}
+	return lines, total
+}
+
+// layoutBlocks lays out blocks behind a prefix, which is first on the
+// first line and rest on the others. Blocks are separated by a blank line.
+func (m *Markdown) layoutBlocks(blocks []*mdBlock, width int, first, rest []ui.Cell, style ui.Style, depth int) []mdLine {
+	var lines []mdLine
+	for i, b := range blocks {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		} else {
+			lines = append(lines, mdLine{cells: rest, height: 1})
+		}
+		lines = append(lines, m.layoutBlock(b, width, prefix, rest, style, depth)...)
+	}
+	return lines
+}
+
+func (m *Markdown) layoutBlock(b *mdBlock, width int, first, rest []ui.Cell, style ui.Style, depth int) []mdLine {
+	avail := max(width-ui.CellsWidth(rest), 1)
+	switch b.kind {
+	case mdHeading:
+		cells := m.inlineCells(b.text, m.HeadingStyle)
+		rows := wrapRows(cells, avail)
+		if b.level <= 2 {
+			r := '═'
+			if b.level == 2 {
+				r = '─'
+			}
+			w := 0
+			for _, row := range rows {
+				w = max(w, ui.CellsWidth(row))
+			}
+			rows = append(rows, ui.StringToStyledCells(strings.Repeat(string(r), min(w, avail)), m.HeadingStyle))
+		}
+		return prefixRows(rows, first, rest)
+	case mdCode:
+		rows := make([][]ui.Cell, 0, len(b.lines))
+		for _, text := range b.lines {
+			row := ui.StringToStyledCells(" "+text, m.CodeStyle)
+			for w := ui.CellsWidth(row); w < avail; w++ {
+				row = append(row, ui.NewCell(' ', m.CodeStyle))
+			}
+			rows = append(rows, row)
+		}
+		return prefixRows(rows, first, rest)
+	case mdQuote:
+		bar := ui.StringToStyledCells("│ ", m.QuoteStyle)
+		return m.layoutBlocks(b.children, width, joinCells(first, bar), joinCells(rest, bar), m.QuoteStyle, depth)
+	case mdList:
+		return m.layoutList(b, width, first, rest, style, depth)
+	case mdRule:
+		row := ui.StringToStyledCells(strings.Repeat("─", avail), m.RuleStyle)
+		return prefixRows([][]ui.Cell{row}, first, rest)
+	case mdTable:
+		table, height := m.newTable(b.rows, avail, style)
+		return []mdLine{{cells: first, table: table, height: height}}
+	}
+	return prefixRows(wrapRows(m.inlineCells(b.text, style), avail), first, rest)
+}
+
+var mdBullets = []string{"•", "◦", "▪"}
+
+// layoutList lays out the items of a list, each behind its bullet or
+// number. Only loose lists, written with blank lines, keep them.
+func (m *Markdown) layoutList(b *mdBlock, width int, first, rest []ui.Cell, style ui.Style, depth int) []mdLine {
+	markers := make([]string, len(b.items))
+	markerWidth := 0
+	for i := range b.items {
+		if b.ordered {
+			markers[i] = strconv.Itoa(b.start+i) + "."
+		} else {
+			markers[i] = mdBullets[depth%len(mdBullets)]
+		}
+		markerWidth = max(markerWidth, utf8.RuneCountInString(markers[i]))
+	}
+	pad := ui.StringToStyledCells(strings.Repeat(" ", markerWidth+1), style)
+	var lines []mdLine
+	for i, item := range b.items {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		text := strings.Repeat(" ", markerWidth-utf8.RuneCountInString(markers[i])) + markers[i] + " "
+		marker := ui.StringToStyledCells(text, m.BulletStyle)
+		if len(item) == 0 {
+			lines = append(lines, mdLine{cells: joinCells(prefix, marker), height: 1})
+			continue
+		}
+		if b.loose && i > 0 {
+			lines = append(lines, mdLine{cells: rest, height: 1})
+		}
+		head, body := joinCells(prefix, marker), joinCells(rest, pad)
+		for j, child := range item {
+			if j > 0 && b.loose {
+				lines = append(lines, mdLine{cells: body, height: 1})
+			}
+			if j > 0 {
+				head = body
+			}
+			lines = append(lines, m.layoutBlock(child, width, head, body, style, depth+1)...)
+		}
+	}
+	return lines
+}
+
+// newTable returns a Table showing rows at most width columns wide, and
+// its height.
+func (m *Markdown) newTable(rows [][]string, width int, style ui.Style) (*Table, int) {
+	t := NewTableWithContext(m.Context)
+	t.Border = false
+	t.TextWrap = true
+	t.SelectedRow = -1
+	t.TextStyle = style
+	t.BorderStyle = m.RuleStyle
+	t.RowStyles[0] = m.TableHeaderStyle
+	t.Rows = make([][]string, len(rows))
+	for i, row := range rows {
+		rowStyle := style
+		if i == 0 {
+			rowStyle = m.TableHeaderStyle
+		}
+		t.Rows[i] = make([]string, len(row))
+		for j, cell := range row {
+			t.Rows[i][j] = m.inlineMarkup(cell, rowStyle)
+		}
+	}
+	t.ColumnWidths = tableWidths(t, width)
+
+	height := 0
+	for i, row := range t.Rows {
+		rowStyle := t.TextStyle
+		if s, ok := t.RowStyles[i]; ok {
+			rowStyle = s
+		}
+		height += t.rowHeight(row, rowStyle, t.ColumnWidths)
+	}
+	if t.RowSeparator {
+		height += len(t.Rows) - 1
+	}
+	return t, height
+}
+
+// tableWidths returns the width of the widest cell in each column, taking
+// columns in turn from the widest until the table fits in width.
+func tableWidths(t *Table, width int) []int {
+	if len(t.Rows) == 0 {
+		return nil
+	}
+	widths := make([]int, len(t.Rows[0]))
+	for _, row := range t.Rows {
+		for j, text := range row {
+			if j < len(widths) {
+				widths[j] = max(widths[j], ui.CellsWidth(ui.ParseText(text, t.TextStyle, t.TextMode)), 1)
+			}
+		}
+	}
+	avail := width - (len(widths) - 1)
+	for ui.SumIntSlice(widths) > avail {
+		widest := 0
+		for j, w := range widths {
+			if w > widths[widest] {
+				widest = j
+			}
+		}
+		if widths[widest] <= 1 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// inlineStyle returns the style of a run of inline text inside base.
+func (m *Markdown) inlineStyle(run mdInline, base ui.Style) ui.Style {
+	style := base
+	switch run.kind {
+	case mdCodeSpan:
+		style = m.CodeStyle
+	case mdLink:
+		style = m.LinkStyle
+	}
+	for _, mod := range run.mods {
+		switch mod {
+		case mdEmphasis:
+			style.Modifier |= ui.ModifierItalic
+		case mdStrong:
+			style.Modifier |= ui.ModifierBold
+		case mdStrike:
+			style.Modifier |= ui.ModifierStrike
+		}
+	}
+	return style
+}
+
+// linkSuffix returns the URL written after a link, or "".
+func (m *Markdown) linkSuffix(runs []mdInline, i int) string {
+	run := runs[i]
+	if !m.ShowLinkURLs || run.kind != mdLink || run.url == "" || run.url == run.text {
+		return ""
+	}
+	if i+1 < len(runs) && runs[i+1].kind == mdLink && runs[i+1].url == run.url {
+		return ""
+	}
+	return " (" + run.url + ")"
+}
+
+// inlineCells styles the inline markdown in s.
+func (m *Markdown) inlineCells(s string, base ui.Style) []ui.Cell {
+	runs := parseInline(s, nil)
+	var cells []ui.Cell
+	dim := base
+	dim.Modifier |= ui.ModifierDim
+	for i, run := range runs {
+		if run.kind == mdBreak {
+			cells = append(cells, ui.NewCell('\n', base))
+			continue
+		}
+		cells = append(cells, ui.StringToStyledCells(run.text, m.inlineStyle(run, base))...)
+		if url := m.linkSuffix(runs, i); url != "" {
+			cells = append(cells, ui.StringToStyledCells(url, dim)...)
+		}
+	}
+	return cells
+}
+
+// inlineMarkup turns the inline markdown in s into markup for a Table.
+func (m *Markdown) inlineMarkup(s string, base ui.Style) string {
+	runs := parseInline(s, nil)
+	var sb strings.Builder
+	span := func(text string, style ui.Style) {
+		text = ui.EscapeMarkup(text)
+		if style == base {
+			sb.WriteString(text)
+			return
+		}
+		sb.WriteString("[" + text + "](" + ui.FormatStyle(style) + ")")
+	}
+	dim := base
+	dim.Modifier |= ui.ModifierDim
+	for i, run := range runs {
+		if run.kind == mdBreak {
+			sb.WriteString(" ")
+			continue
+		}
+		span(run.text, m.inlineStyle(run, base))
+		if url := m.linkSuffix(runs, i); url != "" {
+			span(url, dim)
+		}
+	}
+	return sb.String()
+}
+
+func wrapRows(cells []ui.Cell, width int) [][]ui.Cell {
+	rows := ui.BreakLines(cells, width, ui.WrapOptions{})
+	if len(rows) == 0 {
+		rows = append(rows, nil)
+	}
+	return rows
+}
+
+func prefixRows(rows [][]ui.Cell, first, rest []ui.Cell) []mdLine {
+	lines := make([]mdLine, len(rows))
+	for i, row := range rows {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		lines[i] = mdLine{cells: joinCells(prefix, row), height: 1}
+	}
+	return lines
+}
+
+func joinCells(a, b []ui.Cell) []ui.Cell {
+	return append(append(make([]ui.Cell, 0, len(a)+len(b)), a...), b...)
+}
+
+// ScrollBy scrolls by n rows, down when n is positive.
+func (m *Markdown) ScrollBy(n int) {
+	m.OffsetY = max(min(m.OffsetY+n, m.lineCount-m.viewHeight), 0)
+}
+
+// ScrollUp scrolls up by ScrollStep rows.
+func (m *Markdown) ScrollUp() {
+	m.ScrollBy(-m.ScrollStep)
+}
+
+// ScrollDown scrolls down by ScrollStep rows.
+func (m *Markdown) ScrollDown() {
+	m.ScrollBy(m.ScrollStep)
+}
+
+// ScrollPageUp scrolls up by one page.
+func (m *Markdown) ScrollPageUp() {
+	m.ScrollBy(-m.viewHeight)
+}
+
+// ScrollPageDown scrolls down by one page.
+func (m *Markdown) ScrollPageDown() {
+	m.ScrollBy(m.viewHeight)
+}
+
+// ScrollTop scrolls to the start of the document.
+func (m *Markdown) ScrollTop() {
+	m.OffsetY = 0
+}
+
+// ScrollBottom scrolls to the end of the document.
+func (m *Markdown) ScrollBottom() {
+	m.ScrollBy(m.lineCount)
+}
+
+// HandleEvent scrolls on navigation keys and on the mouse wheel over the
+// widget.
+func (m *Markdown) HandleEvent(e ui.Event) bool {
+	if e.Type == ui.MouseEvent {
+		mouse, ok := e.Payload.(ui.Mouse)
+		if !ok || !image.Pt(mouse.X, mouse.Y).In(m.Rectangle) {
+			return false
+		}
+	}
+	switch e.ID {
+	case "<Up>", "<MouseWheelUp>":
+		m.ScrollUp()
+	case "<Down>", "<MouseWheelDown>":
+		m.ScrollDown()
+	case "<PageUp>":
+		m.ScrollPageUp()
+	case "<PageDown>":
+		m.ScrollPageDown()
+	case "<Home>":
+		m.ScrollTop()
+	case "<End>":
+		m.ScrollBottom()
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,603 @@
+package widgets
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// mdKind is the kind of a markdown block.
+type mdKind int
+
+const (
+	mdParagraph mdKind = iota
+	mdHeading
+	mdCode
+	mdQuote
+	mdList
+	mdRule
+	mdTable
+)
+
+// mdBlock is a block of a markdown document. Paragraphs and headings keep
+// their inline source in text, to be styled when laid out.
+type mdBlock struct {
+	kind     mdKind
+	level    int
+	text     string
+	lines    []string
+	children []*mdBlock
+	items    [][]*mdBlock
+	ordered  bool
+	loose    bool
+	start    int
+	rows     [][]string
+}
+
+// parseMarkdown splits src into blocks following CommonMark, plus the
+// tables and strikethrough of GitHub flavored markdown.
+func parseMarkdown(src string) []*mdBlock {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	lines := strings.Split(src, "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+	return parseMDBlocks(lines)
+}
+
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var sb strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := 4 - col%4
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		sb.WriteRune(r)
+		col++
+	}
+	return sb.String()
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func parseMDBlocks(lines []string) []*mdBlock {
+	var blocks []*mdBlock
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, &mdBlock{kind: mdParagraph, text: joinParagraph(para)})
+			para = nil
+		}
+	}
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " ")
+		indent := indentOf(line)
+		switch {
+		case isBlank(line):
+			flush()
+			i++
+		case indent >= 4 && len(para) == 0:
+			var code []string
+			for i < len(lines) && (isBlank(lines[i]) || indentOf(lines[i]) >= 4) {
+				code = append(code, strings.TrimPrefix(lines[i], "    "))
+				i++
+			}
+			for len(code) > 0 && isBlank(code[len(code)-1]) {
+				code = code[:len(code)-1]
+			}
+			blocks = append(blocks, &mdBlock{kind: mdCode, lines: code})
+		case indent < 4 && mdFence(trimmed) != "":
+			flush()
+			fence := mdFence(trimmed)
+			i++
+			var code []string
+			for i < len(lines) {
+				t := strings.TrimLeft(lines[i], " ")
+				if strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == "" {
+					i++
+					break
+				}
+				code = append(code, trimIndent(lines[i], indent))
+				i++
+			}
+			blocks = append(blocks, &mdBlock{kind: mdCode, lines: code})
+		case indent < 4 && mdHeadingLevel(trimmed) > 0:
+			flush()
+			level := mdHeadingLevel(trimmed)
+			text := strings.TrimSpace(trimmed[level:])
+			if t := strings.TrimRight(text, "#"); t == "" || strings.HasSuffix(t, " ") {
+				text = strings.TrimSpace(t)
+			}
+			blocks = append(blocks, &mdBlock{kind: mdHeading, level: level, text: text})
+			i++
+		case indent < 4 && len(para) > 0 && mdSetext(trimmed) > 0:
+			blocks = append(blocks, &mdBlock{kind: mdHeading, level: mdSetext(trimmed), text: joinParagraph(para)})
+			para = nil
+			i++
+		case indent < 4 && mdIsRule(trimmed):
+			flush()
+			blocks = append(blocks, &mdBlock{kind: mdRule})
+			i++
+		case indent < 4 && strings.HasPrefix(trimmed, ">"):
+			flush()
+			var quoted []string
+			for i < len(lines) {
+				t := strings.TrimLeft(lines[i], " ")
+				if indentOf(lines[i]) >= 4 || !strings.HasPrefix(t, ">") {
+					// Lazy continuation of a quoted paragraph.
+					if isBlank(lines[i]) || len(quoted) == 0 || isBlank(quoted[len(quoted)-1]) || mdStartsBlock(t) {
+						break
+					}
+					quoted = append(quoted, t)
+					i++
+					continue
+				}
+				t = strings.TrimPrefix(t[1:], " ")
+				quoted = append(quoted, t)
+				i++
+			}
+			blocks = append(blocks, &mdBlock{kind: mdQuote, children: parseMDBlocks(quoted)})
+		case indent < 4 && mdListMarker(line).ok && (len(para) == 0 || mdListMarker(line).canInterrupt(line)):
+			flush()
+			var b *mdBlock
+			b, i = parseMDList(lines, i)
+			blocks = append(blocks, b)
+		case indent < 4 && strings.Contains(line, "|") && i+1 < len(lines) && len(mdTableRow(line)) == len(mdTableDelimiter(lines[i+1])):
+			flush()
+			header := mdTableRow(line)
+			rows := [][]string{header}
+			i += 2
+			for i < len(lines) && !isBlank(lines[i]) && strings.Contains(lines[i], "|") {
+				row := mdTableRow(lines[i])
+				for len(row) < len(header) {
+					row = append(row, "")
+				}
+				rows = append(rows, row[:len(header)])
+				i++
+			}
+			blocks = append(blocks, &mdBlock{kind: mdTable, rows: rows})
+		default:
+			para = append(para, line)
+			i++
+		}
+	}
+	flush()
+	return blocks
+}
+
+// joinParagraph joins the lines of a paragraph with spaces, keeping the
+// hard line breaks written as two trailing spaces or a backslash.
+func joinParagraph(lines []string) string {
+	var sb strings.Builder
+	for i, line := range lines {
+		line = strings.TrimLeft(line, " ")
+		if i == len(lines)-1 {
+			sb.WriteString(strings.TrimRight(line, " "))
+			break
+		}
+		switch {
+		case strings.HasSuffix(line, "  "):
+			sb.WriteString(strings.TrimRight(line, " ") + "\n")
+		case strings.HasSuffix(line, "\\") && !strings.HasSuffix(line, "\\\\"):
+			sb.WriteString(line[:len(line)-1] + "\n")
+		default:
+			sb.WriteString(line + " ")
+		}
+	}
+	return sb.String()
+}
+
+func trimIndent(line string, n int) string {
+	return line[min(n, indentOf(line)):]
+}
+
+// mdFence returns the fence that opens a code block, or "".
+func mdFence(s string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(s) - len(strings.TrimLeft(s, c))
+		if n >= 3 && !(c == "`" && strings.Contains(s[n:], "`")) {
+			return s[:n]
+		}
+	}
+	return ""
+}
+
+func mdHeadingLevel(s string) int {
+	n := len(s) - len(strings.TrimLeft(s, "#"))
+	if n < 1 || n > 6 || (len(s) > n && s[n] != ' ') {
+		return 0
+	}
+	return n
+}
+
+// mdSetext returns the heading level of an underline of = or -, or 0.
+func mdSetext(s string) int {
+	s = strings.TrimRight(s, " ")
+	switch {
+	case s != "" && strings.Trim(s, "=") == "":
+		return 1
+	case s != "" && strings.Trim(s, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+func mdIsRule(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 3 {
+		return false
+	}
+	c := s[:1]
+	return (c == "-" || c == "*" || c == "_") && strings.Trim(s, c) == ""
+}
+
+func mdStartsBlock(t string) bool {
+	return strings.HasPrefix(t, ">") || mdHeadingLevel(t) > 0 || mdFence(t) != "" ||
+		mdIsRule(t) || mdListMarker(t).ok
+}
+
+// mdMarker describes the marker that starts a list item.
+type mdMarker struct {
+	ok      bool
+	ordered bool
+	char    byte
+	start   int
+	indent  int
+	content int
+}
+
+func mdListMarker(line string) mdMarker {
+	indent := indentOf(line)
+	s := line[indent:]
+	m := mdMarker{indent: indent}
+	n := 0
+	switch {
+	case s != "" && (s[0] == '-' || s[0] == '*' || s[0] == '+'):
+		m.char, n = s[0], 1
+	default:
+		for n < len(s) && n < 9 && s[n] >= '0' && s[n] <= '9' {
+			n++
+		}
+		if n == 0 || n >= len(s) || (s[n] != '.' && s[n] != ')') {
+			return m
+		}
+		m.ordered, m.char = true, s[n]
+		m.start, _ = strconv.Atoi(s[:n])
+		n++
+	}
+	if n < len(s) && s[n] != ' ' {
+		return m
+	}
+	spaces := indentOf(s[n:])
+	if spaces == 0 || spaces > 4 || n+spaces == len(s) {
+		spaces = 1
+	}
+	m.ok = true
+	m.content = indent + n + spaces
+	return m
+}
+
+// canInterrupt reports whether the item may end a paragraph: ordered
+// lists must start at 1 and the item must not be empty.
+func (m mdMarker) canInterrupt(line string) bool {
+	return (!m.ordered || m.start == 1) && m.content < len(line)
+}
+
+func parseMDList(lines []string, i int) (*mdBlock, int) {
+	first := mdListMarker(lines[i])
+	b := &mdBlock{kind: mdList, ordered: first.ordered, start: first.start}
+	var item []string
+	marker := first
+	flush := func() {
+		for len(item) > 0 && isBlank(item[len(item)-1]) {
+			item = item[:len(item)-1]
+		}
+		b.items = append(b.items, parseMDBlocks(item))
+		item = nil
+	}
+	sameList := func(line string) (mdMarker, bool) {
+		m := mdListMarker(line)
+		return m, m.ok && m.char == first.char && m.indent < marker.content && !mdIsRule(strings.TrimSpace(line))
+	}
+	item = append(item, lines[i][min(marker.content, len(lines[i])):])
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if isBlank(line) {
+			j := i + 1
+			for j < len(lines) && isBlank(lines[j]) {
+				j++
+			}
+			if j == len(lines) {
+				break
+			}
+			if _, ok := sameList(lines[j]); !ok && indentOf(lines[j]) < marker.content {
+				break
+			}
+			item = append(item, "")
+			b.loose = true
+			continue
+		}
+		if m, ok := sameList(line); ok {
+			flush()
+			marker = m
+			item = append(item, line[min(m.content, len(line)):])
+			continue
+		}
+		if indentOf(line) >= marker.content {
+			item = append(item, line[marker.content:])
+			continue
+		}
+		t := strings.TrimLeft(line, " ")
+		if len(item) > 0 && !isBlank(item[len(item)-1]) && !mdStartsBlock(t) {
+			item = append(item, t)
+			continue
+		}
+		break
+	}
+	flush()
+	return b, i
+}
+
+// mdTableRow splits a table row on the pipes that are not escaped.
+func mdTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var sb strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			sb.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(sb.String()))
+			sb.Reset()
+		default:
+			sb.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(sb.String()))
+}
+
+// mdTableDelimiter returns the cells of a delimiter row such as
+// "|---|:-:|", or nil when line is not one.
+func mdTableDelimiter(line string) []string {
+	if !strings.Contains(line, "-") {
+		return nil
+	}
+	cells := mdTableRow(line)
+	for _, c := range cells {
+		c = strings.TrimSuffix(strings.TrimPrefix(c, ":"), ":")
+		if c == "" || strings.Trim(c, "-") != "" {
+			return nil
+		}
+	}
+	return cells
+}
+
+// mdInline is one styled run of inline markdown.
+type mdInline struct {
+	text string
+	kind mdInlineKind
+	mods []mdInlineKind
+	url  string
+}
+
+type mdInlineKind int
+
+const (
+	mdText mdInlineKind = iota
+	mdEmphasis
+	mdStrong
+	mdStrike
+	mdCodeSpan
+	mdLink
+	mdBreak
+)
+
+// parseInline splits the inline markdown of a paragraph into runs. mods
+// holds the emphasis that the runs are nested in.
+func parseInline(s string, mods []mdInlineKind) []mdInline {
+	var out []mdInline
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			out = append(out, mdInline{text: text.String(), kind: mdText, mods: mods})
+			text.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]):
+			text.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '\n':
+			flush()
+			out = append(out, mdInline{kind: mdBreak, mods: mods})
+			i++
+			continue
+		case c == '`':
+			n := runLength(s, i, '`')
+			if end := strings.Index(s[i+n:], strings.Repeat("`", n)); end >= 0 && runLength(s, i+n+end, '`') == n {
+				code := strings.ReplaceAll(s[i+n:i+n+end], "\n", " ")
+				if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' {
+					code = code[1 : len(code)-1]
+				}
+				flush()
+				out = append(out, mdInline{text: code, kind: mdCodeSpan, mods: mods})
+				i += 2*n + end
+				continue
+			}
+			text.WriteString(s[i : i+n])
+			i += n
+			continue
+		case c == '*' || c == '_' || c == '~':
+			n := runLength(s, i, c)
+			if end, ok := closingDelimiter(s, i, n, c); ok {
+				flush()
+				inner := append([]mdInlineKind(nil), mods...)
+				switch {
+				case c == '~':
+					inner = append(inner, mdStrike)
+				case n == 1:
+					inner = append(inner, mdEmphasis)
+				case n == 2:
+					inner = append(inner, mdStrong)
+				default:
+					inner = append(inner, mdStrong, mdEmphasis)
+				}
+				out = append(out, parseInline(s[i+n:end], inner)...)
+				i = end + n
+				continue
+			}
+			text.WriteString(s[i : i+n])
+			i += n
+			continue
+		case c == '[' || c == '!' && i+1 < len(s) && s[i+1] == '[':
+			open := i
+			if c == '!' {
+				open++
+			}
+			if label, url, end, ok := parseLink(s, open); ok {
+				flush()
+				for _, run := range parseInline(label, mods) {
+					if run.kind == mdText {
+						run.kind, run.url = mdLink, url
+					}
+					out = append(out, run)
+				}
+				i = end
+				continue
+			}
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				url := s[i+1 : i+end]
+				if !strings.ContainsAny(url, " <") && (strings.Contains(url, "://") || strings.Contains(url, "@")) {
+					flush()
+					out = append(out, mdInline{text: url, kind: mdLink, mods: mods})
+					i += end + 1
+					continue
+				}
+			}
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		text.WriteString(s[i : i+size])
+		i += size
+	}
+	flush()
+	return out
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < utf8.RuneSelf && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// closingDelimiter finds the run of n c's that closes the one at i.
+// Strikethrough takes exactly two tildes.
+func closingDelimiter(s string, i, n int, c byte) (int, bool) {
+	if c == '~' && n != 2 {
+		return 0, false
+	}
+	after := i + n
+	if after >= len(s) || s[after] == ' ' || s[after] == '\n' {
+		return 0, false
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return 0, false
+	}
+	for j := after; j < len(s); {
+		switch {
+		case s[j] == '\\':
+			j += 2
+			continue
+		case s[j] == '`':
+			m := runLength(s, j, '`')
+			if end := strings.Index(s[j+m:], strings.Repeat("`", m)); end >= 0 {
+				j += 2*m + end
+				continue
+			}
+		case s[j] == c:
+			m := runLength(s, j, c)
+			if (m == n || n >= 3 && m >= 3) && s[j-1] != ' ' && j > after &&
+				!(c == '_' && j+m < len(s) && isWordByte(s[j+m])) {
+				return j, true
+			}
+			j += m
+			continue
+		}
+		j++
+	}
+	return 0, false
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= utf8.RuneSelf
+}
+
+// parseLink reads [label](url "title") starting at the bracket at i and
+// returns the index after it.
+func parseLink(s string, i int) (label, url string, end int, ok bool) {
+	depth := 0
+	close := -1
+	for j := i; j < len(s) && close < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				close = j
+			}
+		}
+	}
+	if close < 0 || close+1 >= len(s) || s[close+1] != '(' {
+		return "", "", 0, false
+	}
+	depth = 0
+	for j := close + 1; j < len(s); j++ {
+		switch s[j] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				dest := strings.TrimSpace(s[close+2 : j])
+				if k := strings.IndexAny(dest, " \n"); k >= 0 {
+					dest = dest[:k]
+				}
+				dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+				return s[i+1 : close], dest, j + 1, true
+			}
+		}
+	}
+	return "", "", 0, false
+}
@@ -12,39 +12,47 @@ type Modal struct {
 	ui.Block
 	Text              string
 	TextStyle         ui.Style
+	WrapOptions       ui.WrapOptions
 	Buttons           []*Button
 	ActiveButtonIndex int
+	// Backdrop is blended over everything drawn before the modal, such as
+	// a translucent black to dim it. Shadow is blended over the rectangle
+	// of the modal moved by ShadowOffset. Both do nothing when clear.
+	Backdrop     ui.Style
+	Shadow       ui.Style
+	ShadowOffset image.Point
 }
 
 // NewModal returns a new Modal with the given text.
 func NewModal(text string) *Modal {
+	return NewModalWithContext(ui.DefaultContext, text)
+}
+
+// NewModalWithContext returns a new Modal bound to ctx.
+func NewModalWithContext(ctx *ui.Context, text string) *Modal {
 	return &Modal{
-		Block:             *ui.NewBlock(),
+		Block:             *ui.NewBlockWithContext(ctx),
 		Text:              text,
 		TextStyle:         ui.NewStyle(ui.ColorWhite),
 		Buttons:           make([]*Button, 0),
 		ActiveButtonIndex: 0,
+		ShadowOffset:      image.Pt(2, 1),
 	}
 }
 
 // CenterIn centers the modal in the given rectangle.
 func (m *Modal) CenterIn(x1, y1, x2, y2, width, height int) {
-	totalW := x2 - x1
-	totalH := y2 - y1
-	if width > totalW {
-		width = totalW
-	}
-	if height > totalH {
-		height = totalH
-	}
-	mx := x1 + (totalW-width)/2
-	my := y1 + (totalH-height)/2
-	m.SetRect(mx, my, mx+width, my+height)
+	r := ui.Place(image.Rect(x1, y1, x2, y2), ui.AnchorCenter, width, height, 0, 0)
+	m.SetRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
 }
 
 // AddButton adds a button to the modal.
 func (m *Modal) AddButton(text string, onClick func()) *Button {
-	b := NewButton(text)
+	ctx := m.Context
+	if ctx == nil {
+		ctx = ui.DefaultContext
+	}
+	b := NewButtonWithContext(ctx, text)
 	b.Border = true
 	b.OnClick = onClick
 	m.Buttons = append(m.Buttons, b)
@@ -85,75 +93,41 @@ func (m *Modal) layoutButtons() {
 	}
 }
 
+// Children returns the buttons, implementing ui.Container.
+func (m *Modal) Children() []ui.Drawable {
+	children := make([]ui.Drawable, len(m.Buttons))
+	for i, b := range m.Buttons {
+		children[i] = b
+	}
+	return children
+}
+
 // Draw draws the modal to the buffer.
 func (m *Modal) Draw(buf *ui.Buffer) {
+	if m.Backdrop != ui.StyleClear {
+		buf.Blend(m.Backdrop, buf.Clip())
+	}
+	if m.Shadow != ui.StyleClear {
+		buf.Blend(m.Shadow, m.Rectangle.Add(m.ShadowOffset))
+	}
 	for y := m.Min.Y; y < m.Max.Y; y++ {
 		for x := m.Min.X; x < m.Max.X; x++ {
 			buf.SetCell(ui.NewCell(' ', ui.NewStyle(ui.ColorWhite, m.BorderStyle.Bg)), image.Pt(x, y))
 		}
 	}
 	m.Block.Draw(buf)
-	words := splitBySpace(m.Text)
-	var lines []string
-	currentLine := ""
-	maxWidth := m.Inner.Dx() - 2
-	for _, word := range words {
-		if word == "\n" {
-			lines = append(lines, currentLine)
-			currentLine = ""
-			continue
-		}
-		candidate := currentLine
-		if len(candidate) > 0 {
-			candidate += " "
-		}
-		candidate += word
-		if rw.StringWidth(candidate) > maxWidth {
-			if len(currentLine) > 0 {
-				lines = append(lines, currentLine)
-			}
-			currentLine = word
-		} else {
-			currentLine = candidate
-		}
-	}
-	if len(currentLine) > 0 {
-		lines = append(lines, currentLine)
-	}
-	textHeight := len(lines)
-	startY := max(m.Inner.Min.Y+(m.Inner.Dy()-textHeight-4)/2, m.Inner.Min.Y)
+	cells := ui.StringToStyledCells(m.Text, m.TextStyle)
+	lines := ui.BreakLines(cells, m.Inner.Dx()-2, m.WrapOptions)
+	startY := max(m.Inner.Min.Y+(m.Inner.Dy()-len(lines)-4)/2, m.Inner.Min.Y)
 	for i, line := range lines {
-		w := rw.StringWidth(line)
-		x := m.Inner.Min.X + (m.Inner.Dx()-w)/2
-		buf.SetString(line, m.TextStyle, image.Pt(x, startY+i))
+		x := m.Inner.Min.X + (m.Inner.Dx()-ui.CellsWidth(line))/2
+		for _, c := range line {
+			buf.SetCell(c, image.Pt(x, startY+i))
+			x += c.ColumnWidth()
+		}
 	}
 	m.layoutButtons()
 	for _, b := range m.Buttons {
 		b.Draw(buf)
 	}
 }
-func splitBySpace(s string) []string {
-	var words []string
-	var currentWord []rune
-	for _, r := range s {
-		switch r {
-		case '\n':
-			if len(currentWord) > 0 {
-				words = append(words, string(currentWord))
-				currentWord = nil
-			}
-			words = append(words, "\n")
-		case ' ':
-			if len(currentWord) > 0 {
-				words = append(words, string(currentWord))
-				currentWord = nil
-			}
-		default:
-			currentWord = append(currentWord, r)
-		}
-	}
-	if len(currentWord) > 0 {
-		words = append(words, string(currentWord))
-	}
-	return words
-}
@@ -0,0 +1,245 @@
+package widgets
+
+import (
+	"image"
+
+	ui "github.com/metaspartan/gotui/v5"
+)
+
+// Page is a named screen of a Pages container. OnShow and OnHide are called
+// when the page becomes the current one and when it stops being it.
+type Page struct {
+	Name   string
+	Widget ui.Drawable
+	OnShow func()
+	OnHide func()
+}
+
+// Pages holds named widgets and shows one of them at a time in Inner.
+// When Tabs is set with BindTabs, the tab names follow the pages and
+// selecting a tab shows its page.
+type Pages struct {
+	ui.Block
+	Tabs     *TabPane
+	OnChange func(from, to *Page)
+	pages    []*Page
+	current  int
+	shown    int
+}
+
+// NewPages returns a new, empty Pages container.
+func NewPages() *Pages {
+	return NewPagesWithContext(ui.DefaultContext)
+}
+
+// NewPagesWithContext returns a new Pages bound to ctx.
+func NewPagesWithContext(ctx *ui.Context) *Pages {
+	p := &Pages{
+		Block: *ui.NewBlockWithContext(ctx),
+		shown: -1,
+	}
+	p.Border = false
+	return p
+}
+
+// AddPage appends a page. The first page added becomes the current one.
+// Adding a page with the name of an existing one replaces its widget.
+func (p *Pages) AddPage(name string, w ui.Drawable) *Page {
+	if i := p.index(name); i >= 0 {
+		p.pages[i].Widget = w
+		return p.pages[i]
+	}
+	page := &Page{Name: name, Widget: w}
+	p.pages = append(p.pages, page)
+	p.syncTabs()
+	return page
+}
+
+// RemovePage removes the named page. Removing the current page shows the
+// one before it.
+func (p *Pages) RemovePage(name string) {
+	i := p.index(name)
+	if i < 0 {
+		return
+	}
+	if i == p.shown {
+		p.hide(p.pages[i])
+		p.shown = -1
+	} else if i < p.shown {
+		p.shown--
+	}
+	p.pages = append(p.pages[:i], p.pages[i+1:]...)
+	if i < p.current || (i == p.current && i > 0) || p.current >= len(p.pages) {
+		p.current = max(p.current-1, 0)
+	}
+	p.syncTabs()
+	if p.Tabs != nil {
+		p.Tabs.ActiveTabIndex = p.current
+	}
+	p.sync()
+}
+
+// Page returns the named page, or nil.
+func (p *Pages) Page(name string) *Page {
+	if i := p.index(name); i >= 0 {
+		return p.pages[i]
+	}
+	return nil
+}
+
+// PageNames returns the names of the pages in order.
+func (p *Pages) PageNames() []string {
+	names := make([]string, len(p.pages))
+	for i, page := range p.pages {
+		names[i] = page.Name
+	}
+	return names
+}
+
+// Current returns the page being shown, or nil when there are no pages.
+func (p *Pages) Current() *Page {
+	if p.current < len(p.pages) {
+		return p.pages[p.current]
+	}
+	return nil
+}
+
+// ShowPage makes the named page current. It reports whether the page
+// exists.
+func (p *Pages) ShowPage(name string) bool {
+	i := p.index(name)
+	if i < 0 {
+		return false
+	}
+	p.ShowIndex(i)
+	return true
+}
+
+// ShowIndex makes the i-th page current.
+func (p *Pages) ShowIndex(i int) {
+	if i < 0 || i >= len(p.pages) {
+		return
+	}
+	p.current = i
+	if p.Tabs != nil {
+		p.Tabs.ActiveTabIndex = i
+	}
+	p.sync()
+}
+
+// NextPage shows the page after the current one, wrapping around.
+func (p *Pages) NextPage() {
+	if len(p.pages) > 0 {
+		p.ShowIndex((p.current + 1) % len(p.pages))
+	}
+}
+
+// PrevPage shows the page before the current one, wrapping around.
+func (p *Pages) PrevPage() {
+	if len(p.pages) > 0 {
+		p.ShowIndex((p.current - 1 + len(p.pages)) % len(p.pages))
+	}
+}
+
+// BindTabs makes tp list the pages and switch between them.
+func (p *Pages) BindTabs(tp *TabPane) {
+	p.Tabs = tp
+	p.syncTabs()
+	tp.ActiveTabIndex = p.current
+}
+
+func (p *Pages) syncTabs() {
+	if p.Tabs != nil {
+		p.Tabs.TabNames = p.PageNames()
+	}
+}
+
+func (p *Pages) index(name string) int {
+	for i, page := range p.pages {
+		if page.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// sync fires the lifecycle callbacks when the current page has changed
+// since they were last called.
+func (p *Pages) sync() {
+	if p.shown == p.current || p.current >= len(p.pages) {
+		return
+	}
+	var from *Page
+	if p.shown >= 0 && p.shown < len(p.pages) {
+		from = p.pages[p.shown]
+		p.hide(from)
+	}
+	to := p.pages[p.current]
+	p.shown = p.current
+	if to.OnShow != nil {
+		to.OnShow()
+	}
+	if p.OnChange != nil {
+		p.OnChange(from, to)
+	}
+}
+
+func (p *Pages) hide(page *Page) {
+	if page.OnHide != nil {
+		page.OnHide()
+	}
+}
+
+func (p *Pages) Draw(buf *ui.Buffer) {
+	p.Block.Draw(buf)
+	if p.Tabs != nil && p.Tabs.ActiveTabIndex != p.current {
+		p.ShowIndex(p.Tabs.ActiveTabIndex)
+	}
+	p.sync()
+	page := p.Current()
+	if page == nil || page.Widget == nil {
+		return
+	}
+	page.Widget.SetRect(p.Inner.Min.X, p.Inner.Min.Y, p.Inner.Max.X, p.Inner.Max.Y)
+	if ui.IsHidden(page.Widget) {
+		return
+	}
+	page.Widget.Lock()
+	buf.PushClip(p.Inner)
+	page.Widget.Draw(buf)
+	buf.PopClip()
+	page.Widget.Unlock()
+}
+
+// HandleEvent switches pages on clicks on the bound tabs and passes other
+// events to the current page.
+func (p *Pages) HandleEvent(e ui.Event) bool {
+	if m, ok := e.Payload.(ui.Mouse); ok && p.Tabs != nil && e.ID == "<MouseLeft>" {
+		if i := p.Tabs.ResolveClick(image.Pt(m.X, m.Y)); i >= 0 {
+			p.ShowIndex(i)
+			return true
+		}
+	}
+	if w := p.FocusedChild(); w != nil {
+		return w.HandleEvent(e)
+	}
+	return false
+}
+
+// Children returns the widgets of all pages, implementing ui.Container.
+func (p *Pages) Children() []ui.Drawable {
+	children := make([]ui.Drawable, len(p.pages))
+	for i, page := range p.pages {
+		children[i] = page.Widget
+	}
+	return children
+}
+
+// FocusedChild returns the current page, implementing ui.FocusContainer.
+func (p *Pages) FocusedChild() ui.Widget {
+	if page := p.Current(); page != nil {
+		w, _ := page.Widget.(ui.Widget)
+		return w
+	}
+	return nil
+}
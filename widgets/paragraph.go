@@ -2,8 +2,7 @@ package widgets
 
 import (
 	"image"
-
-	rw "github.com/mattn/go-runewidth"
+	"slices"
 
 	ui "github.com/metaspartan/gotui/v5"
 )
@@ -11,46 +10,79 @@ import (
 // Paragraph represents a widget that displays a paragraph of text.
 type Paragraph struct {
 	ui.Block
-	Text              string
-	TextStyle         ui.Style
-	WrapText          bool
+	Text      string
+	TextStyle ui.Style
+	TextMode  ui.TextMode
+	// Lexer, when set, highlights Text as source code in the styles of
+	// Syntax instead of reading it as TextMode says.
+	Lexer    ui.Lexer
+	Syntax   ui.SyntaxTheme
+	WrapText bool
+	// WrapOptions sets how lines are wrapped when WrapText is set. With
+	// Ellipsis and no MaxLines, text is cut at the height of the paragraph.
+	WrapOptions       ui.WrapOptions
 	VerticalAlignment ui.VerticalAlignment
 	TextAlignment     ui.Alignment
-	Gradient          ui.Gradient
+	// Direction is the direction of the text, which orders mixed
+	// right-to-left and left-to-right runs and mirrors TextAlignment when
+	// it is right to left. DirectionAuto takes it from the first letter.
+	Direction ui.Direction
+	Gradient  ui.Gradient
 }
 
 // NewParagraph returns a new Paragraph.
 func NewParagraph() *Paragraph {
+	return NewParagraphWithContext(ui.DefaultContext)
+}
+
+// NewParagraphWithContext returns a new Paragraph bound to ctx.
+func NewParagraphWithContext(ctx *ui.Context) *Paragraph {
+	theme := ctx.CurrentTheme()
 	return &Paragraph{
-		Block:             *ui.NewBlock(),
-		TextStyle:         ui.Theme.Paragraph.Text,
+		Block:             *ui.NewBlockWithContext(ctx),
+		TextStyle:         theme.Paragraph.Text,
+		Syntax:            theme.Syntax,
 		WrapText:          true,
 		VerticalAlignment: ui.AlignTop,
 		TextAlignment:     ui.AlignLeft,
 	}
 }
 
+func (p *Paragraph) restyle() {
+	old, cur, ok := p.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&p.TextStyle, old.Paragraph.Text, cur.Paragraph.Text)
+	ui.Restyle(&p.Syntax, old.Syntax, cur.Syntax)
+}
+
 // Draw draws the paragraph to the buffer.
 func (p *Paragraph) Draw(buf *ui.Buffer) {
+	p.restyle()
 	p.Block.Draw(buf)
 
-	cells := p.computeCells()
-	rows := ui.SplitCells(cells, '\n')
-	p.drawRows(buf, rows)
+	p.drawRows(buf, p.computeRows())
 }
 
-func (p *Paragraph) computeCells() []ui.Cell {
+func (p *Paragraph) computeRows() [][]ui.Cell {
 	var cells []ui.Cell
-	if p.Gradient.Enabled && p.Gradient.Direction == 0 {
-		cells = ui.ApplyGradientToText(p.Text, p.Gradient.Start, p.Gradient.End)
+	if p.Gradient.Enabled && p.Gradient.Direction == ui.GradientHorizontal {
+		cells = p.Gradient.ApplyToText(p.Text)
+	} else if p.Lexer != nil {
+		cells = ui.Highlight(p.Text, p.Lexer, p.Syntax)
 	} else {
-		cells = ui.ParseStyles(p.Text, p.TextStyle)
+		cells = ui.ParseText(p.Text, p.TextStyle, p.TextMode)
 	}
 
-	if p.WrapText {
-		cells = ui.WrapCells(cells, uint(p.Inner.Dx()))
+	if !p.WrapText {
+		return ui.SplitCells(cells, '\n')
 	}
-	return cells
+	opts := p.WrapOptions
+	if opts.Ellipsis && opts.MaxLines == 0 {
+		opts.MaxLines = p.Inner.Dy()
+	}
+	return ui.BreakLines(cells, p.Inner.Dx(), opts)
 }
 
 func (p *Paragraph) drawRows(buf *ui.Buffer, rows [][]ui.Cell) {
@@ -69,41 +101,55 @@ func (p *Paragraph) drawRows(buf *ui.Buffer, rows [][]ui.Cell) {
 	}
 
 	var gradientColors []ui.Color
-	if p.Gradient.Enabled && p.Gradient.Direction == 1 {
-		gradientColors = ui.GenerateGradient(p.Gradient.Start, p.Gradient.End, totalRows)
+	if p.Gradient.Enabled && p.Gradient.Direction == ui.GradientVertical {
+		gradientColors = p.Gradient.Colors(totalRows)
 	}
 
+	dir := p.Direction
+	if dir == ui.DirectionAuto {
+		dir = ui.TextDirection(slices.Concat(rows...))
+	}
 	for i, row := range rows {
 		y := i + topPadding
 		if y >= height {
 			break
 		}
 
-		row = ui.TrimCells(row, p.Inner.Dx())
+		row = ui.ReorderCells(ui.TrimCells(row, p.Inner.Dx()), dir)
 		cellWithX := ui.BuildCellWithXArray(row)
 
 		if len(cellWithX) == 0 {
 			continue
 		}
 
-		xOffset := p.calculateXOffset(cellWithX)
+		xOffset := p.calculateXOffset(cellWithX, dir)
 
 		for _, cx := range cellWithX {
 			x, cell := cx.X, cx.Cell
-			if p.Gradient.Enabled && p.Gradient.Direction == 1 && i < len(gradientColors) {
-				cell.Style.Fg = gradientColors[i]
+			pt := image.Pt(x+xOffset, y).Add(p.Inner.Min)
+			if p.Gradient.Enabled {
+				switch p.Gradient.Direction {
+				case ui.GradientHorizontal:
+					// Colored along the text in computeRows.
+				case ui.GradientVertical:
+					if i < len(gradientColors) {
+						cell.Style.Fg = gradientColors[i]
+					}
+				default:
+					cell.Style.Fg = p.Gradient.ColorAt(pt, p.Inner)
+				}
 			}
-			buf.SetCell(cell, image.Pt(x+xOffset, y).Add(p.Inner.Min))
+			buf.SetCell(cell, pt)
 		}
 	}
 }
 
-func (p *Paragraph) calculateXOffset(cellWithX []ui.CellWithX) int {
+func (p *Paragraph) calculateXOffset(cellWithX []ui.CellWithX, dir ui.Direction) int {
 	last := cellWithX[len(cellWithX)-1]
-	rowWidth := last.X + rw.RuneWidth(last.Cell.Rune)
+	rowWidth := last.X + last.Cell.ColumnWidth()
 
 	var offset int
-	switch p.TextAlignment {
+	switch ui.MirrorAlignment(p.TextAlignment, dir) {
 	case ui.AlignCenter:
 		offset = (p.Inner.Dx() - rowWidth) / 2
 	case ui.AlignRight:
@@ -29,16 +29,31 @@ type PieChart struct {
 
 // NewPieChart returns a new PieChart.
 func NewPieChart() *PieChart {
+	return NewPieChartWithContext(ui.DefaultContext)
+}
+
+// NewPieChartWithContext returns a new PieChart bound to ctx.
+func NewPieChartWithContext(ctx *ui.Context) *PieChart {
+	theme := ctx.CurrentTheme()
 	return &PieChart{
-		Block:       *ui.NewBlock(),
-		Colors:      ui.Theme.PieChart.Slices,
+		Block:       *ui.NewBlockWithContext(ctx),
+		Colors:      theme.PieChart.Slices,
 		AngleOffset: piechartOffsetUp,
 		InnerRadius: 0.0,
 	}
 }
 
+func (pc *PieChart) restyle() {
+	old, cur, ok := pc.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.RestyleSlice(&pc.Colors, old.PieChart.Slices, cur.PieChart.Slices)
+}
+
 // Draw draws the pie chart to the buffer.
 func (pc *PieChart) Draw(buf *ui.Buffer) {
+	pc.restyle()
 	pc.Block.Draw(buf)
 	center := pc.Inner.Min.Add(pc.Inner.Size().Div(2))
 	radius := ui.MinFloat64(float64(pc.Inner.Dx()/2/xStretch), float64(pc.Inner.Dy()/2))
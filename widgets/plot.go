@@ -57,10 +57,16 @@ const (
 
 // NewPlot returns a new Plot.
 func NewPlot() *Plot {
+	return NewPlotWithContext(ui.DefaultContext)
+}
+
+// NewPlotWithContext returns a new Plot bound to ctx.
+func NewPlotWithContext(ctx *ui.Context) *Plot {
+	theme := ctx.CurrentTheme()
 	return &Plot{
-		Block:           *ui.NewBlock(),
-		LineColors:      ui.Theme.Plot.Lines,
-		AxesColor:       ui.Theme.Plot.Axes,
+		Block:           *ui.NewBlockWithContext(ctx),
+		LineColors:      theme.Plot.Lines,
+		AxesColor:       theme.Plot.Axes,
 		Marker:          MarkerBraille,
 		DotMarkerRune:   ui.DOT,
 		Data:            [][]float64{},
@@ -206,8 +212,18 @@ func (plt *Plot) plotAxes(buf *ui.Buffer, maxVal float64) {
 	}
 }
 
+func (plt *Plot) restyle() {
+	old, cur, ok := plt.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.RestyleSlice(&plt.LineColors, old.Plot.Lines, cur.Plot.Lines)
+	ui.Restyle(&plt.AxesColor, old.Plot.Axes, cur.Plot.Axes)
+}
+
 // Draw draws the plot to the buffer.
 func (plt *Plot) Draw(buf *ui.Buffer) {
+	plt.restyle()
 	plt.Block.Draw(buf)
 	maxVal := plt.MaxVal
 	if maxVal == 0 {
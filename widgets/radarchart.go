@@ -21,17 +21,33 @@ type RadarChart struct {
 
 // NewRadarChart returns a new RadarChart.
 func NewRadarChart() *RadarChart {
+	return NewRadarChartWithContext(ui.DefaultContext)
+}
+
+// NewRadarChartWithContext returns a new RadarChart bound to ctx.
+func NewRadarChartWithContext(ctx *ui.Context) *RadarChart {
+	theme := ctx.CurrentTheme()
 	return &RadarChart{
-		Block:      *ui.NewBlock(),
-		LineColors: ui.Theme.Plot.Lines,
-		LabelStyle: ui.NewStyle(ui.Theme.Plot.Axes),
+		Block:      *ui.NewBlockWithContext(ctx),
+		LineColors: theme.Plot.Lines,
+		LabelStyle: ui.NewStyle(theme.Plot.Axes),
 		DotStyle:   ui.NewStyle(ui.ColorWhite),
 		Data:       [][]float64{},
 	}
 }
 
+func (rc *RadarChart) restyle() {
+	old, cur, ok := rc.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.RestyleSlice(&rc.LineColors, old.Plot.Lines, cur.Plot.Lines)
+	ui.Restyle(&rc.LabelStyle, ui.NewStyle(old.Plot.Axes), ui.NewStyle(cur.Plot.Axes))
+}
+
 // Draw draws the radar chart to the buffer.
 func (rc *RadarChart) Draw(buf *ui.Buffer) {
+	rc.restyle()
 	rc.Block.Draw(buf)
 	if len(rc.Data) == 0 || len(rc.Data[0]) == 0 {
 		return
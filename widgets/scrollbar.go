@@ -32,8 +32,13 @@ type Scrollbar struct {
 
 // NewScrollbar returns a new Scrollbar.
 func NewScrollbar() *Scrollbar {
+	return NewScrollbarWithContext(ui.DefaultContext)
+}
+
+// NewScrollbarWithContext returns a new Scrollbar bound to ctx.
+func NewScrollbarWithContext(ctx *ui.Context) *Scrollbar {
 	return &Scrollbar{
-		Block:       *ui.NewBlock(),
+		Block:       *ui.NewBlockWithContext(ctx),
 		Orientation: ScrollbarVertical,
 		Max:         100,
 		Current:     0,
@@ -0,0 +1,281 @@
+package widgets
+
+import (
+	"image"
+
+	ui "github.com/metaspartan/gotui/v5"
+)
+
+// ScrollView shows a window onto a child that is larger than the screen.
+// The child is laid out at ContentWidth x ContentHeight in its own
+// coordinates, starting at (0, 0), and the part at OffsetX, OffsetY is
+// copied into Inner. A zero content size follows the viewport on that axis.
+type ScrollView struct {
+	ui.Block
+	Child         ui.Drawable
+	ContentWidth  int
+	ContentHeight int
+	OffsetX       int
+	OffsetY       int
+	ScrollStep    int
+	ShowScrollbar bool
+	VScrollbar    *Scrollbar
+	HScrollbar    *Scrollbar
+	viewport      image.Rectangle
+}
+
+// NewScrollView returns a new ScrollView showing child.
+func NewScrollView(child ui.Drawable) *ScrollView {
+	return NewScrollViewWithContext(ui.DefaultContext, child)
+}
+
+// NewScrollViewWithContext returns a new ScrollView bound to ctx.
+func NewScrollViewWithContext(ctx *ui.Context, child ui.Drawable) *ScrollView {
+	v := NewScrollbarWithContext(ctx)
+	v.Border = false
+	h := NewScrollbarWithContext(ctx)
+	h.Border = false
+	h.Orientation = ScrollbarHorizontal
+	h.TrackRune = '═'
+	h.BeginRune = '◀'
+	h.EndRune = '▶'
+	return &ScrollView{
+		Block:         *ui.NewBlockWithContext(ctx),
+		Child:         child,
+		ScrollStep:    1,
+		ShowScrollbar: true,
+		VScrollbar:    v,
+		HScrollbar:    h,
+	}
+}
+
+// contentSize returns the virtual size of the child for a viewport of the
+// given size.
+func (s *ScrollView) contentSize(view image.Point) image.Point {
+	size := image.Pt(s.ContentWidth, s.ContentHeight)
+	if size.X <= 0 {
+		size.X = view.X
+	}
+	if size.Y <= 0 {
+		size.Y = view.Y
+	}
+	return size
+}
+
+// layout works out the viewport and which scrollbars are needed. Showing
+// one scrollbar shrinks the viewport, which may make the other necessary.
+func (s *ScrollView) layout() (view image.Rectangle, content image.Point, vbar, hbar bool) {
+	view = s.Inner
+	for range 2 {
+		content = s.contentSize(view.Size())
+		vbar = s.ShowScrollbar && content.Y > view.Dy()
+		hbar = s.ShowScrollbar && content.X > view.Dx()
+		view = s.Inner
+		if vbar {
+			view.Max.X--
+		}
+		if hbar {
+			view.Max.Y--
+		}
+	}
+	return view.Canon(), s.contentSize(view.Size()), vbar, hbar
+}
+
+// Viewport returns the area of the screen the child is shown in.
+func (s *ScrollView) Viewport() image.Rectangle {
+	view, _, _, _ := s.layout()
+	return view
+}
+
+// ContentSize returns the virtual size the child is laid out at.
+func (s *ScrollView) ContentSize() image.Point {
+	_, content, _, _ := s.layout()
+	return content
+}
+
+func (s *ScrollView) clampOffset(view image.Rectangle, content image.Point) {
+	s.OffsetX = max(min(s.OffsetX, content.X-view.Dx()), 0)
+	s.OffsetY = max(min(s.OffsetY, content.Y-view.Dy()), 0)
+}
+
+func (s *ScrollView) Draw(buf *ui.Buffer) {
+	s.Block.Draw(buf)
+	view, content, vbar, hbar := s.layout()
+	s.viewport = view
+	s.clampOffset(view, content)
+	if s.Child != nil && !view.Empty() {
+		s.Child.SetRect(0, 0, content.X, content.Y)
+		if !ui.IsHidden(s.Child) {
+			s.drawChild(buf, view, content)
+		}
+	}
+	if vbar {
+		s.VScrollbar.Max = content.Y
+		s.VScrollbar.PageSize = view.Dy()
+		s.VScrollbar.Current = s.OffsetY
+		s.VScrollbar.SetRect(view.Max.X-1, view.Min.Y-1, view.Max.X+2, view.Max.Y+1)
+		s.VScrollbar.Draw(buf)
+	}
+	if hbar {
+		s.HScrollbar.Max = content.X
+		s.HScrollbar.PageSize = view.Dx()
+		s.HScrollbar.Current = s.OffsetX
+		s.HScrollbar.SetRect(view.Min.X-1, view.Max.Y-1, view.Max.X+1, view.Max.Y+2)
+		s.HScrollbar.Draw(buf)
+	}
+}
+
+// drawChild draws the child offscreen and copies the visible part into view.
+func (s *ScrollView) drawChild(buf *ui.Buffer, view image.Rectangle, content image.Point) {
+	off := ui.NewBuffer(image.Rect(0, 0, content.X, content.Y))
+	s.Child.Lock()
+	s.Child.Draw(off)
+	s.Child.Unlock()
+	origin := image.Pt(s.OffsetX, s.OffsetY)
+	for y := view.Min.Y; y < view.Max.Y; y++ {
+		for x := view.Min.X; x < view.Max.X; x++ {
+			p := image.Pt(x, y)
+			buf.SetCell(off.GetCell(p.Sub(view.Min).Add(origin)), p)
+		}
+	}
+}
+
+// ScrollBy moves the window by dx columns and dy rows.
+func (s *ScrollView) ScrollBy(dx, dy int) {
+	s.OffsetX += dx
+	s.OffsetY += dy
+	view, content, _, _ := s.layout()
+	s.clampOffset(view, content)
+}
+
+// ScrollUp scrolls up by ScrollStep rows.
+func (s *ScrollView) ScrollUp() {
+	s.ScrollBy(0, -s.ScrollStep)
+}
+
+// ScrollDown scrolls down by ScrollStep rows.
+func (s *ScrollView) ScrollDown() {
+	s.ScrollBy(0, s.ScrollStep)
+}
+
+// ScrollLeft scrolls left by ScrollStep columns.
+func (s *ScrollView) ScrollLeft() {
+	s.ScrollBy(-s.ScrollStep, 0)
+}
+
+// ScrollRight scrolls right by ScrollStep columns.
+func (s *ScrollView) ScrollRight() {
+	s.ScrollBy(s.ScrollStep, 0)
+}
+
+// ScrollPageUp scrolls up by one page.
+func (s *ScrollView) ScrollPageUp() {
+	s.ScrollBy(0, -s.Viewport().Dy())
+}
+
+// ScrollPageDown scrolls down by one page.
+func (s *ScrollView) ScrollPageDown() {
+	s.ScrollBy(0, s.Viewport().Dy())
+}
+
+// ScrollTop scrolls to the top left corner.
+func (s *ScrollView) ScrollTop() {
+	s.OffsetX, s.OffsetY = 0, 0
+}
+
+// ScrollBottom scrolls to the bottom.
+func (s *ScrollView) ScrollBottom() {
+	s.ScrollBy(0, s.ContentSize().Y)
+}
+
+// ScrollTo scrolls as little as possible to bring w into view. w must be
+// the child or a widget drawn by it, so that its rectangle is in content
+// coordinates; call it after the child has been drawn at least once.
+func (s *ScrollView) ScrollTo(w ui.Drawable) {
+	view := s.Viewport()
+	r := w.GetRect()
+	s.OffsetX = reveal(s.OffsetX, view.Dx(), r.Min.X, r.Max.X)
+	s.OffsetY = reveal(s.OffsetY, view.Dy(), r.Min.Y, r.Max.Y)
+	s.ScrollBy(0, 0)
+}
+
+// reveal returns the offset that shows [lo, hi) in a window of size n,
+// preferring the start when the range does not fit.
+func reveal(offset, n, lo, hi int) int {
+	if hi > offset+n {
+		offset = hi - n
+	}
+	if lo < offset {
+		offset = lo
+	}
+	return offset
+}
+
+// HandleEvent passes events to the child, translating mouse positions into
+// content coordinates, and scrolls on unhandled wheel and navigation keys.
+func (s *ScrollView) HandleEvent(e ui.Event) bool {
+	switch e.Type {
+	case ui.MouseEvent:
+		m, ok := e.Payload.(ui.Mouse)
+		if !ok || !image.Pt(m.X, m.Y).In(s.Rectangle) {
+			return false
+		}
+		if w, ok := s.Child.(ui.Widget); ok && image.Pt(m.X, m.Y).In(s.viewport) {
+			m.X += s.OffsetX - s.viewport.Min.X
+			m.Y += s.OffsetY - s.viewport.Min.Y
+			inner := e
+			inner.Payload = m
+			if w.HandleEvent(inner) {
+				return true
+			}
+		}
+	case ui.KeyboardEvent:
+		if w, ok := s.Child.(ui.Widget); ok && w.HandleEvent(e) {
+			return true
+		}
+	}
+	return s.scrollKey(e.ID)
+}
+
+func (s *ScrollView) scrollKey(id string) bool {
+	switch id {
+	case "<Up>", "<MouseWheelUp>":
+		s.ScrollUp()
+	case "<Down>", "<MouseWheelDown>":
+		s.ScrollDown()
+	case "<Left>":
+		s.ScrollLeft()
+	case "<Right>":
+		s.ScrollRight()
+	case "<PageUp>":
+		s.ScrollPageUp()
+	case "<PageDown>":
+		s.ScrollPageDown()
+	case "<Home>":
+		s.ScrollTop()
+	case "<End>":
+		s.ScrollBottom()
+	default:
+		return false
+	}
+	return true
+}
+
+// Children returns the child and the scrollbars, implementing
+// ui.Container.
+func (s *ScrollView) Children() []ui.Drawable {
+	children := []ui.Drawable{s.Child}
+	if s.VScrollbar != nil {
+		children = append(children, s.VScrollbar)
+	}
+	if s.HScrollbar != nil {
+		children = append(children, s.HScrollbar)
+	}
+	return children
+}
+
+// FocusedChild returns the child, implementing ui.FocusContainer.
+func (s *ScrollView) FocusedChild() ui.Widget {
+	w, _ := s.Child.(ui.Widget)
+	return w
+}
@@ -25,23 +25,48 @@ type SparklineGroup struct {
 
 // NewSparkline returns a new Sparkline.
 func NewSparkline() *Sparkline {
+	return NewSparklineWithContext(ui.DefaultContext)
+}
+
+// NewSparklineWithContext returns a new Sparkline bound to ctx.
+func NewSparklineWithContext(ctx *ui.Context) *Sparkline {
+	theme := ctx.CurrentTheme()
 	return &Sparkline{
-		TitleStyle:      ui.Theme.Sparkline.Title,
-		LineColor:       ui.Theme.Sparkline.Line,
+		TitleStyle:      theme.Sparkline.Title,
+		LineColor:       theme.Sparkline.Line,
 		BackgroundColor: ui.ColorClear,
 	}
 }
 
 // NewSparklineGroup returns a new SparklineGroup.
 func NewSparklineGroup(sls ...*Sparkline) *SparklineGroup {
+	return NewSparklineGroupWithContext(ui.DefaultContext, sls...)
+}
+
+// NewSparklineGroupWithContext returns a new SparklineGroup bound to ctx.
+func NewSparklineGroupWithContext(ctx *ui.Context, sls ...*Sparkline) *SparklineGroup {
 	return &SparklineGroup{
-		Block:      *ui.NewBlock(),
+		Block:      *ui.NewBlockWithContext(ctx),
 		Sparklines: sls,
 	}
 }
 
+// restyle restyles the sparklines of the group, which have no block of
+// their own to notice theme changes.
+func (sg *SparklineGroup) restyle() {
+	old, cur, ok := sg.ThemeChanged()
+	if !ok {
+		return
+	}
+	for _, sl := range sg.Sparklines {
+		ui.Restyle(&sl.TitleStyle, old.Sparkline.Title, cur.Sparkline.Title)
+		ui.Restyle(&sl.LineColor, old.Sparkline.Line, cur.Sparkline.Line)
+	}
+}
+
 // Draw draws the sparkline group to the buffer.
 func (sg *SparklineGroup) Draw(buf *ui.Buffer) {
+	sg.restyle()
 	sg.Block.Draw(buf)
 	sparklineHeight := sg.Inner.Dy() / len(sg.Sparklines)
 	for i, sl := range sg.Sparklines {
@@ -40,8 +40,13 @@ type Spinner struct {
 
 // NewSpinner returns a new Spinner.
 func NewSpinner() *Spinner {
+	return NewSpinnerWithContext(ui.DefaultContext)
+}
+
+// NewSpinnerWithContext returns a new Spinner bound to ctx.
+func NewSpinnerWithContext(ctx *ui.Context) *Spinner {
 	return &Spinner{
-		Block:        *ui.NewBlock(),
+		Block:        *ui.NewBlockWithContext(ctx),
 		Frames:       SpinnerLine,
 		Index:        0,
 		FormatString: "%s [%s]",
@@ -0,0 +1,401 @@
+package widgets
+
+import (
+	"image"
+	"math"
+
+	ui "github.com/metaspartan/gotui/v5"
+)
+
+// SplitPaneItem is a pane of a SplitPane. Ratio is its share of the space
+// left after the dividers; a collapsed pane keeps its ratio so that it can
+// be expanded to the same size again.
+type SplitPaneItem struct {
+	Widget    ui.Drawable
+	Ratio     float64
+	MinSize   int
+	Collapsed bool
+}
+
+// SplitPaneState is the part of a SplitPane worth saving between runs.
+type SplitPaneState struct {
+	Ratios    []float64 `json:"ratios"`
+	Collapsed []bool    `json:"collapsed"`
+}
+
+// SplitPane lays out panes side by side (LayoutHorizontal) or stacked
+// (LayoutVertical) with a one cell divider between neighbours. Dividers can
+// be dragged with the mouse or moved with ShrinkKey and GrowKey, which act
+// on SelectedDivider; NextDividerKey selects the next one.
+type SplitPane struct {
+	ui.Block
+	Panes              []*SplitPaneItem
+	Direction          ui.LayoutDirection
+	DividerStyle       ui.Style
+	ActiveDividerStyle ui.Style
+	SelectedDivider    int
+	Step               int
+	ShrinkKey          string
+	GrowKey            string
+	NextDividerKey     string
+	OnResize           func(SplitPaneState)
+	focus              int
+	dragging           int
+	sizes              []int
+}
+
+// NewSplitPane returns a new SplitPane with the given panes sharing the
+// space equally.
+func NewSplitPane(dir ui.LayoutDirection, panes ...ui.Drawable) *SplitPane {
+	return NewSplitPaneWithContext(ui.DefaultContext, dir, panes...)
+}
+
+// NewSplitPaneWithContext returns a new SplitPane bound to ctx.
+func NewSplitPaneWithContext(ctx *ui.Context, dir ui.LayoutDirection, panes ...ui.Drawable) *SplitPane {
+	theme := ctx.CurrentTheme()
+	s := &SplitPane{
+		Block:              *ui.NewBlockWithContext(ctx),
+		Direction:          dir,
+		DividerStyle:       theme.Block.Border,
+		ActiveDividerStyle: ui.NewStyle(ui.ColorYellow),
+		Step:               1,
+		ShrinkKey:          "[",
+		GrowKey:            "]",
+		NextDividerKey:     "\\",
+		dragging:           -1,
+	}
+	for _, p := range panes {
+		s.AddPane(p, 1)
+	}
+	return s
+}
+
+// AddPane appends a pane with the given ratio.
+func (s *SplitPane) AddPane(w ui.Drawable, ratio float64) *SplitPaneItem {
+	item := &SplitPaneItem{Widget: w, Ratio: ratio}
+	s.Panes = append(s.Panes, item)
+	return item
+}
+
+// State returns the ratios and collapsed panes.
+func (s *SplitPane) State() SplitPaneState {
+	st := SplitPaneState{
+		Ratios:    make([]float64, len(s.Panes)),
+		Collapsed: make([]bool, len(s.Panes)),
+	}
+	for i, p := range s.Panes {
+		st.Ratios[i] = p.Ratio
+		st.Collapsed[i] = p.Collapsed
+	}
+	return st
+}
+
+// SetState restores ratios and collapsed panes saved with State. Entries
+// beyond the number of panes are ignored.
+func (s *SplitPane) SetState(st SplitPaneState) {
+	for i, p := range s.Panes {
+		if i < len(st.Ratios) && st.Ratios[i] >= 0 {
+			p.Ratio = st.Ratios[i]
+		}
+		if i < len(st.Collapsed) {
+			p.Collapsed = st.Collapsed[i]
+		}
+	}
+}
+
+// Collapse shrinks the i-th pane to nothing.
+func (s *SplitPane) Collapse(i int) {
+	s.setCollapsed(i, true)
+}
+
+// Expand restores the i-th pane after Collapse.
+func (s *SplitPane) Expand(i int) {
+	s.setCollapsed(i, false)
+}
+
+// ToggleCollapse collapses or expands the i-th pane.
+func (s *SplitPane) ToggleCollapse(i int) {
+	if i >= 0 && i < len(s.Panes) {
+		s.setCollapsed(i, !s.Panes[i].Collapsed)
+	}
+}
+
+func (s *SplitPane) setCollapsed(i int, v bool) {
+	if i < 0 || i >= len(s.Panes) || s.Panes[i].Collapsed == v {
+		return
+	}
+	s.Panes[i].Collapsed = v
+	s.layout()
+	s.changed()
+}
+
+func (s *SplitPane) changed() {
+	if s.OnResize != nil {
+		s.OnResize(s.State())
+	}
+}
+
+// available returns the space shared by the panes and where it starts.
+func (s *SplitPane) available() (size, start int) {
+	dividers := max(len(s.Panes)-1, 0)
+	if s.Direction == ui.LayoutVertical {
+		return max(s.Inner.Dy()-dividers, 0), s.Inner.Min.Y
+	}
+	return max(s.Inner.Dx()-dividers, 0), s.Inner.Min.X
+}
+
+// layout solves the pane sizes from their ratios. Panes that would end up
+// below MinSize are pinned to it and the rest is shared out again.
+func (s *SplitPane) layout() []int {
+	total, _ := s.available()
+	// Fill takes whole weights. Scaling the ratios by a thousand per cell
+	// keeps every pane within a thousandth of a cell of its ratio, however
+	// small the ratio or wide the pane.
+	scale := float64(max(total, 1) * 1000)
+	cs := make([]ui.Constraint, len(s.Panes))
+	for i, p := range s.Panes {
+		cs[i] = ui.Fill(int(math.Round(p.Ratio * scale)))
+		if p.Collapsed {
+			cs[i] = ui.Length(0)
+		}
+	}
+	sizes := ui.SolveLayout(total, cs...)
+	for range s.Panes {
+		changed := false
+		for i, p := range s.Panes {
+			if !p.Collapsed && sizes[i] < p.MinSize && cs[i] != ui.Length(p.MinSize) {
+				cs[i] = ui.Length(p.MinSize)
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		sizes = ui.SolveLayout(total, cs...)
+	}
+	s.sizes = sizes
+	return sizes
+}
+
+// paneRect returns the rectangle of a pane starting at pos with size cells.
+func (s *SplitPane) paneRect(pos, size int) image.Rectangle {
+	if s.Direction == ui.LayoutVertical {
+		return image.Rect(s.Inner.Min.X, pos, s.Inner.Max.X, pos+size)
+	}
+	return image.Rect(pos, s.Inner.Min.Y, pos+size, s.Inner.Max.Y)
+}
+
+// dividerPos returns the position of every divider on the main axis.
+func (s *SplitPane) dividerPos() []int {
+	_, pos := s.available()
+	out := make([]int, 0, len(s.sizes))
+	for i, size := range s.sizes {
+		pos += size
+		if i < len(s.sizes)-1 {
+			out = append(out, pos)
+			pos++
+		}
+	}
+	return out
+}
+
+func (s *SplitPane) restyle() {
+	old, cur, ok := s.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&s.DividerStyle, old.Block.Border, cur.Block.Border)
+}
+
+func (s *SplitPane) Draw(buf *ui.Buffer) {
+	s.restyle()
+	s.Block.Draw(buf)
+	sizes := s.layout()
+	_, pos := s.available()
+	for i, p := range s.Panes {
+		if sizes[i] > 0 {
+			r := s.paneRect(pos, sizes[i])
+			p.Widget.SetRect(r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+			if !ui.IsHidden(p.Widget) {
+				buf.PushClip(r)
+				p.Widget.Draw(buf)
+				buf.PopClip()
+			}
+		}
+		pos += sizes[i]
+		if i < len(s.Panes)-1 {
+			s.drawDivider(buf, i, pos)
+			pos++
+		}
+	}
+}
+
+func (s *SplitPane) drawDivider(buf *ui.Buffer, i, pos int) {
+	style := s.DividerStyle
+	if i == s.dragging || i == s.SelectedDivider {
+		style = s.ActiveDividerStyle
+	}
+	r := s.paneRect(pos, 1)
+	char := ui.VERTICAL_LINE
+	if s.Direction == ui.LayoutVertical {
+		char = ui.HORIZONTAL_LINE
+	}
+	buf.Fill(ui.NewCell(char, style), r)
+}
+
+// MoveDivider moves the i-th divider by delta cells, resizing the panes on
+// either side within their MinSize. Moving a divider out of a collapsed
+// pane expands it.
+func (s *SplitPane) MoveDivider(i, delta int) {
+	if i < 0 || i >= len(s.Panes)-1 || len(s.sizes) != len(s.Panes) {
+		return
+	}
+	a, b := s.Panes[i], s.Panes[i+1]
+	if (a.Collapsed && delta > 0) || (b.Collapsed && delta < 0) {
+		a.Collapsed, b.Collapsed = false, false
+	}
+	pair := s.sizes[i] + s.sizes[i+1]
+	lo, hi := a.MinSize, pair-b.MinSize
+	if a.Collapsed || b.Collapsed || lo > hi {
+		return
+	}
+	size := max(min(s.sizes[i]+delta, hi), lo)
+	s.sizes[i], s.sizes[i+1] = size, pair-size
+	s.updateRatios()
+	s.layout()
+	s.changed()
+}
+
+// updateRatios turns the current sizes back into ratios.
+func (s *SplitPane) updateRatios() {
+	total := 0
+	for i, p := range s.Panes {
+		if !p.Collapsed {
+			total += s.sizes[i]
+		}
+	}
+	if total == 0 {
+		return
+	}
+	for i, p := range s.Panes {
+		if !p.Collapsed {
+			p.Ratio = float64(s.sizes[i]) / float64(total)
+		}
+	}
+}
+
+// HandleEvent drags dividers with the left mouse button, resizes with the
+// configured keys and passes other events to the pane under the pointer or
+// the focused pane.
+func (s *SplitPane) HandleEvent(e ui.Event) bool {
+	switch e.Type {
+	case ui.MouseEvent:
+		m, ok := e.Payload.(ui.Mouse)
+		if !ok {
+			return false
+		}
+		return s.handleMouse(e, m)
+	case ui.KeyboardEvent:
+		if w := s.FocusedChild(); w != nil && w.HandleEvent(e) {
+			return true
+		}
+		return s.handleKey(e.ID)
+	}
+	return false
+}
+
+func (s *SplitPane) handleMouse(e ui.Event, m ui.Mouse) bool {
+	p := image.Pt(m.X, m.Y)
+	switch {
+	case e.ID == "<MouseRelease>" && s.dragging >= 0:
+		s.dragging = -1
+		return true
+	case e.ID == "<MouseLeft>" && m.Drag && s.dragging >= 0:
+		s.dragTo(p)
+		return true
+	case e.ID == "<MouseLeft>" && !m.Drag:
+		if i := s.dividerAt(p); i >= 0 {
+			s.dragging = i
+			s.SelectedDivider = i
+			return true
+		}
+	}
+	for i, pane := range s.Panes {
+		w, ok := pane.Widget.(ui.Widget)
+		if !ok || pane.Collapsed || !p.In(w.GetRect()) {
+			continue
+		}
+		if e.ID == "<MouseLeft>" {
+			s.focus = i
+		}
+		return w.HandleEvent(e)
+	}
+	return false
+}
+
+func (s *SplitPane) dividerAt(p image.Point) int {
+	if !p.In(s.Inner) {
+		return -1
+	}
+	at := p.X
+	if s.Direction == ui.LayoutVertical {
+		at = p.Y
+	}
+	for i, pos := range s.dividerPos() {
+		if at == pos {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *SplitPane) dragTo(p image.Point) {
+	at := p.X
+	if s.Direction == ui.LayoutVertical {
+		at = p.Y
+	}
+	if i := s.dragging; i < len(s.dividerPos()) {
+		s.MoveDivider(i, at-s.dividerPos()[i])
+	}
+}
+
+func (s *SplitPane) handleKey(id string) bool {
+	switch id {
+	case s.ShrinkKey:
+		s.MoveDivider(s.SelectedDivider, -s.Step)
+	case s.GrowKey:
+		s.MoveDivider(s.SelectedDivider, s.Step)
+	case s.NextDividerKey:
+		if len(s.Panes) > 1 {
+			s.SelectedDivider = (s.SelectedDivider + 1) % (len(s.Panes) - 1)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// Children returns the widgets of the panes, implementing ui.Container.
+func (s *SplitPane) Children() []ui.Drawable {
+	children := make([]ui.Drawable, len(s.Panes))
+	for i, pane := range s.Panes {
+		children[i] = pane.Widget
+	}
+	return children
+}
+
+// FocusedChild returns the focused pane, implementing ui.FocusContainer.
+func (s *SplitPane) FocusedChild() ui.Widget {
+	if s.focus < 0 || s.focus >= len(s.Panes) || s.Panes[s.focus].Collapsed {
+		return nil
+	}
+	w, _ := s.Panes[s.focus].Widget.(ui.Widget)
+	return w
+}
+
+// SetFocusedPane focuses the i-th pane.
+func (s *SplitPane) SetFocusedPane(i int) {
+	if i >= 0 && i < len(s.Panes) {
+		s.focus = i
+	}
+}
@@ -24,19 +24,36 @@ type StackedBarChart struct {
 
 // NewStackedBarChart returns a new StackedBarChart.
 func NewStackedBarChart() *StackedBarChart {
+	return NewStackedBarChartWithContext(ui.DefaultContext)
+}
+
+// NewStackedBarChartWithContext returns a new StackedBarChart bound to ctx.
+func NewStackedBarChartWithContext(ctx *ui.Context) *StackedBarChart {
+	theme := ctx.CurrentTheme()
 	return &StackedBarChart{
-		Block:        *ui.NewBlock(),
-		BarColors:    ui.Theme.StackedBarChart.Bars,
-		LabelStyles:  ui.Theme.StackedBarChart.Labels,
-		NumStyles:    ui.Theme.StackedBarChart.Nums,
+		Block:        *ui.NewBlockWithContext(ctx),
+		BarColors:    theme.StackedBarChart.Bars,
+		LabelStyles:  theme.StackedBarChart.Labels,
+		NumStyles:    theme.StackedBarChart.Nums,
 		NumFormatter: func(n float64) string { return fmt.Sprint(n) },
 		BarGap:       1,
 		BarWidth:     3,
 	}
 }
 
+func (sbc *StackedBarChart) restyle() {
+	old, cur, ok := sbc.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.RestyleSlice(&sbc.BarColors, old.StackedBarChart.Bars, cur.StackedBarChart.Bars)
+	ui.RestyleSlice(&sbc.LabelStyles, old.StackedBarChart.Labels, cur.StackedBarChart.Labels)
+	ui.RestyleSlice(&sbc.NumStyles, old.StackedBarChart.Nums, cur.StackedBarChart.Nums)
+}
+
 // Draw draws the stacked bar chart to the buffer.
 func (sbc *StackedBarChart) Draw(buf *ui.Buffer) {
+	sbc.restyle()
 	sbc.Block.Draw(buf)
 	maxVal := sbc.MaxVal
 	if maxVal == 0 {
@@ -17,6 +17,11 @@ type StepChart struct {
 // NewStepChart returns a new StepChart initialized with default settings
 // for a solid-line step graph.
 func NewStepChart() *StepChart {
+	return NewStepChartWithContext(ui.DefaultContext)
+}
+
+// NewStepChartWithContext returns a new StepChart bound to ctx.
+func NewStepChartWithContext(ctx *ui.Context) *StepChart {
 	p := NewPlot()
 	p.AxesColor = ui.ColorWhite
 	p.LineColors = []ui.Color{ui.ColorGreen}
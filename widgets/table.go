@@ -3,6 +3,7 @@ package widgets
 import (
 	"fmt"
 	"image"
+	"slices"
 
 	ui "github.com/metaspartan/gotui/v5"
 )
@@ -13,16 +14,24 @@ type Table struct {
 	Rows          [][]string
 	ColumnWidths  []int
 	TextStyle     ui.Style
+	TextMode      ui.TextMode
 	RowSeparator  bool
 	TextAlignment ui.Alignment
-	RowStyles     map[int]ui.Style
-	FillRow       bool
-	// TextWrap wraps the text in each cell.
+	// Direction is the direction of the text in each cell, which orders
+	// mixed runs and mirrors TextAlignment when it is right to left.
+	// DirectionAuto takes it from the first letter of each cell.
+	Direction ui.Direction
+	RowStyles map[int]ui.Style
+	FillRow   bool
+	// TextWrap wraps the text in each cell as WrapOptions says.
 	TextWrap      bool
+	WrapOptions   ui.WrapOptions
 	ColumnResizer func()
 
 	// Selection and Styling
-	SelectedRow      int
+	SelectedRow int
+	// SelectedRowStyle replaces the style of the selected row, or tints it
+	// when translucent.
 	SelectedRowStyle ui.Style
 	CursorColor      ui.Color
 	ShowCursor       bool
@@ -32,18 +41,57 @@ type Table struct {
 
 // NewTable returns a new Table.
 func NewTable() *Table {
+	return NewTableWithContext(ui.DefaultContext)
+}
+
+// NewTableWithContext returns a new Table bound to ctx.
+func NewTableWithContext(ctx *ui.Context) *Table {
+	theme := ctx.CurrentTheme()
 	return &Table{
-		Block:            *ui.NewBlock(),
-		TextStyle:        ui.Theme.Table.Text,
-		SelectedRowStyle: ui.Theme.Table.Text, // Default to text style, user should override
+		Block:            *ui.NewBlockWithContext(ctx),
+		TextStyle:        theme.Table.Text,
+		SelectedRowStyle: theme.Table.Text, // Default to text style, user should override
 		RowSeparator:     true,
 		RowStyles:        make(map[int]ui.Style),
 		ColumnResizer:    func() {},
 	}
 }
 
+func (tb *Table) restyle() {
+	old, cur, ok := tb.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&tb.TextStyle, old.Table.Text, cur.Table.Text)
+	ui.Restyle(&tb.SelectedRowStyle, old.Table.Text, cur.Table.Text)
+}
+
+// PartStyle returns the style of the "header" part, the first row,
+// implementing ui.PartStyler.
+func (tb *Table) PartStyle(part string) (ui.Style, bool) {
+	if part != "header" {
+		return ui.Style{}, false
+	}
+	if s, ok := tb.RowStyles[0]; ok {
+		return s, true
+	}
+	return tb.TextStyle, true
+}
+
+// SetPartStyle sets the style of the "header" part.
+func (tb *Table) SetPartStyle(part string, s ui.Style) {
+	if part != "header" {
+		return
+	}
+	if tb.RowStyles == nil {
+		tb.RowStyles = make(map[int]ui.Style)
+	}
+	tb.RowStyles[0] = s
+}
+
 // Draw draws the table to the buffer.
 func (tb *Table) Draw(buf *ui.Buffer) {
+	tb.restyle()
 	tb.Block.Draw(buf)
 	tb.ColumnResizer()
 
@@ -66,26 +114,18 @@ func (tb *Table) Draw(buf *ui.Buffer) {
 			rowStyle = style
 		}
 
-		// Apply Selection Style overrides
-		if i == tb.SelectedRow {
+		// Apply Selection Style overrides. A translucent one tints the row
+		// once it is drawn.
+		tint := i == tb.SelectedRow && tb.SelectedRowStyle.Translucent()
+		if i == tb.SelectedRow && !tint {
 			rowStyle = tb.SelectedRowStyle
 		}
 
-		rowHeight := 1
-		if tb.TextWrap {
-			for j, cellText := range row {
-				if j < len(columnWidths) {
-					width := columnWidths[j]
-					cells := ui.ParseStyles(cellText, rowStyle)
-					wrapped := ui.WrapCells(cells, uint(width))
-					lines := ui.SplitCells(wrapped, '\n')
-					if len(lines) > rowHeight {
-						rowHeight = len(lines)
-					}
-				}
-			}
-		}
+		rowHeight := tb.rowHeight(row, rowStyle, columnWidths)
 		tb.drawTableRow(buf, row, rowStyle, i, yCoordinate, rowHeight, columnWidths)
+		if tint {
+			buf.Blend(tb.SelectedRowStyle, image.Rect(tb.Inner.Min.X, yCoordinate, tb.Inner.Max.X, min(yCoordinate+rowHeight, tb.Inner.Max.Y)))
+		}
 
 		if tb.ShowCursor && i == tb.SelectedRow {
 			tb.drawCursor(buf, yCoordinate, rowHeight)
@@ -112,6 +152,23 @@ func (tb *Table) drawCursor(buf *ui.Buffer, yCoordinate, rowHeight int) {
 	}
 }
 
+// rowHeight returns the number of lines that row takes, which is more than
+// one only when TextWrap is set.
+func (tb *Table) rowHeight(row []string, rowStyle ui.Style, columnWidths []int) int {
+	rowHeight := 1
+	if !tb.TextWrap {
+		return rowHeight
+	}
+	for j, cellText := range row {
+		if j < len(columnWidths) {
+			cells := ui.ParseText(cellText, rowStyle, tb.TextMode)
+			lines := ui.BreakLines(cells, columnWidths[j], tb.WrapOptions)
+			rowHeight = max(rowHeight, len(lines))
+		}
+	}
+	return rowHeight
+}
+
 func (tb *Table) updateScrolling() {
 	if tb.SelectedRow >= tb.Inner.Dy()+tb.topRow {
 		tb.topRow = tb.SelectedRow - tb.Inner.Dy() + 1
@@ -168,11 +225,10 @@ func (tb *Table) drawTableRow(buf *ui.Buffer, row []string, rowStyle ui.Style, r
 		if j >= len(columnWidths) {
 			break
 		}
-		col := ui.ParseStyles(row[j], rowStyle)
+		col := ui.ParseText(row[j], rowStyle, tb.TextMode)
 		var lines [][]ui.Cell
 		if tb.TextWrap {
-			wrapped := ui.WrapCells(col, uint(columnWidths[j]))
-			lines = ui.SplitCells(wrapped, '\n')
+			lines = ui.BreakLines(col, columnWidths[j], tb.WrapOptions)
 		} else {
 			lines = [][]ui.Cell{col}
 		}
@@ -212,6 +268,8 @@ func (tb *Table) drawTableRow(buf *ui.Buffer, row []string, rowStyle ui.Style, r
 	}
 }
 func (tb *Table) drawTableCell(buf *ui.Buffer, lines [][]ui.Cell, rowIndex, colIndex, yCoordinate, rowHeight, colXCoordinate, colWidth int) {
+	dir := ui.ResolveDirection(tb.Direction, slices.Concat(lines...))
+	align := ui.MirrorAlignment(tb.TextAlignment, dir)
 	for lineIdx := range rowHeight {
 		currentY := yCoordinate + lineIdx
 		if currentY >= tb.Inner.Max.Y {
@@ -219,13 +277,16 @@ func (tb *Table) drawTableCell(buf *ui.Buffer, lines [][]ui.Cell, rowIndex, colI
 		}
 		if lineIdx < len(lines) {
 			line := lines[lineIdx]
-			tb.drawTableLine(buf, line, currentY, colXCoordinate, colWidth)
+			if dir == ui.DirectionRTL || ui.HasRightToLeft(line) {
+				line = ui.ReorderCells(ui.TrimCells(line, colWidth), dir)
+			}
+			tb.drawTableLine(buf, line, currentY, colXCoordinate, colWidth, align)
 		}
 	}
 }
-func (tb *Table) drawTableLine(buf *ui.Buffer, line []ui.Cell, currentY, colXCoordinate, colWidth int) {
+func (tb *Table) drawTableLine(buf *ui.Buffer, line []ui.Cell, currentY, colXCoordinate, colWidth int, align ui.Alignment) {
 	if tb.TextWrap {
-		switch tb.TextAlignment {
+		switch align {
 		case ui.AlignCenter:
 			tb.drawCenterAligned(buf, line, currentY, colXCoordinate, colWidth)
 		case ui.AlignRight:
@@ -235,11 +296,11 @@ func (tb *Table) drawTableLine(buf *ui.Buffer, line []ui.Cell, currentY, colXCoo
 		}
 		return
 	}
-	if len(line) > colWidth || tb.TextAlignment == ui.AlignLeft {
+	if len(line) > colWidth || align == ui.AlignLeft {
 		tb.drawLeftAligned(buf, line, currentY, colXCoordinate, colWidth)
-	} else if tb.TextAlignment == ui.AlignCenter {
+	} else if align == ui.AlignCenter {
 		tb.drawCenterAligned(buf, line, currentY, colXCoordinate, colWidth)
-	} else if tb.TextAlignment == ui.AlignRight {
+	} else if align == ui.AlignRight {
 		tb.drawRightAligned(buf, line, currentY, colXCoordinate, colWidth)
 	}
 }
@@ -248,7 +309,7 @@ func (tb *Table) drawLeftAligned(buf *ui.Buffer, line []ui.Cell, currentY, colXC
 		for _, cx := range ui.BuildCellWithXArray(line) {
 			k, cell := cx.X, cx.Cell
 			if k == colWidth || colXCoordinate+k == tb.Inner.Max.X {
-				cell.Rune = ui.ELLIPSES
+				cell = ui.NewCell(ui.ELLIPSES, cell.Style)
 				buf.SetCell(cell, image.Pt(colXCoordinate+k-1, currentY))
 				break
 			} else {
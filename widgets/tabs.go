@@ -22,11 +22,17 @@ type TabPane struct {
 
 // NewTabPane returns a new TabPane.
 func NewTabPane(names ...string) *TabPane {
+	return NewTabPaneWithContext(ui.DefaultContext, names...)
+}
+
+// NewTabPaneWithContext returns a new TabPane bound to ctx.
+func NewTabPaneWithContext(ctx *ui.Context, names ...string) *TabPane {
+	theme := ctx.CurrentTheme()
 	return &TabPane{
-		Block:            *ui.NewBlock(),
+		Block:            *ui.NewBlockWithContext(ctx),
 		TabNames:         names,
-		ActiveTabStyle:   ui.Theme.Tab.Active,
-		InactiveTabStyle: ui.Theme.Tab.Inactive,
+		ActiveTabStyle:   theme.Tab.Active,
+		InactiveTabStyle: theme.Tab.Inactive,
 		PadLeft:          1,
 		PadRight:         1,
 		TabGap:           0,
@@ -48,8 +54,18 @@ func (tp *TabPane) FocusRight() {
 	}
 }
 
+func (tp *TabPane) restyle() {
+	old, cur, ok := tp.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&tp.ActiveTabStyle, old.Tab.Active, cur.Tab.Active)
+	ui.Restyle(&tp.InactiveTabStyle, old.Tab.Inactive, cur.Tab.Inactive)
+}
+
 // Draw draws the tab pane to the buffer.
 func (tp *TabPane) Draw(buf *ui.Buffer) {
+	tp.restyle()
 	tp.Block.Draw(buf)
 
 	xCoordinate := tp.Inner.Min.X
@@ -5,7 +5,6 @@ import (
 	"strings"
 	"sync"
 
-	rw "github.com/mattn/go-runewidth"
 	ui "github.com/metaspartan/gotui/v5"
 )
 
@@ -17,6 +16,17 @@ type TextArea struct {
 	CursorStyle ui.Style
 	Cursor      image.Point
 	ShowCursor  bool
+	// Direction is the direction of each line. Right-to-left lines are
+	// aligned to the right, and the cursor moves across runs of either
+	// direction as they are shown. DirectionAuto takes it from the first
+	// letter of each line.
+	Direction ui.Direction
+	// Lexer, when set, highlights the text as source code in the styles of
+	// Syntax. Only the lines that are shown are lexed, and after an edit
+	// only the lines from the edit on.
+	Lexer       ui.Lexer
+	Syntax      ui.SyntaxTheme
+	highlighter *ui.Highlighter
 	topLine     int
 	leftCol     int
 	sync.Mutex
@@ -24,17 +34,34 @@ type TextArea struct {
 
 // NewTextArea returns a new TextArea.
 func NewTextArea() *TextArea {
+	return NewTextAreaWithContext(ui.DefaultContext)
+}
+
+// NewTextAreaWithContext returns a new TextArea bound to ctx.
+func NewTextAreaWithContext(ctx *ui.Context) *TextArea {
+	theme := ctx.CurrentTheme()
 	return &TextArea{
-		Block:       *ui.NewBlock(),
-		TextStyle:   ui.Theme.Paragraph.Text,
+		Block:       *ui.NewBlockWithContext(ctx),
+		TextStyle:   theme.Paragraph.Text,
 		CursorStyle: ui.NewStyle(ui.ColorBlack, ui.ColorWhite),
+		Syntax:      theme.Syntax,
 		ShowCursor:  true,
 		Cursor:      image.Point{0, 0},
 	}
 }
 
+func (ta *TextArea) restyle() {
+	old, cur, ok := ta.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&ta.TextStyle, old.Paragraph.Text, cur.Paragraph.Text)
+	ui.Restyle(&ta.Syntax, old.Syntax, cur.Syntax)
+}
+
 // Draw draws the text area to the buffer.
 func (ta *TextArea) Draw(buf *ui.Buffer) {
+	ta.restyle()
 	ta.Block.Draw(buf)
 	lines := strings.Split(ta.Text, "\n")
 	innerRect := ta.Inner
@@ -54,38 +81,94 @@ func (ta *TextArea) adjustScroll(lines []string, height int) {
 }
 func (ta *TextArea) drawText(buf *ui.Buffer, lines []string, width, height int) {
 	innerRect := ta.Inner
+	if ta.Lexer != nil {
+		if ta.highlighter == nil {
+			ta.highlighter = ui.NewHighlighter(ta.Lexer)
+		}
+		ta.highlighter.SetLexer(ta.Lexer)
+		ta.highlighter.SetLines(lines)
+	}
 	for y := range height {
 		lineIdx := ta.topLine + y
 		if lineIdx >= len(lines) {
 			break
 		}
-		line := lines[lineIdx]
-		runes := []rune(line)
-		x := 0
-		for _, r := range runes {
-			if x >= width {
-				break
-			}
-			w := rw.RuneWidth(r)
+		cells := ta.lineCells(lineIdx, lines[lineIdx])
+		dir := ui.ResolveDirection(ta.Direction, cells)
+		x := ta.lineStart(cells, dir, width)
+		for _, c := range ui.ReorderCells(cells, dir) {
+			w := c.ColumnWidth()
 			if x+w > width {
 				break
 			}
-			buf.SetCell(
-				ui.NewCell(r, ta.TextStyle),
-				image.Pt(innerRect.Min.X+x, innerRect.Min.Y+y),
-			)
+			if x >= 0 {
+				buf.SetCell(c, image.Pt(innerRect.Min.X+x, innerRect.Min.Y+y))
+			}
 			x += w
 		}
 	}
 }
+
+// lineCells returns the cells of a line in logical order.
+func (ta *TextArea) lineCells(lineIdx int, line string) []ui.Cell {
+	cells := runeCells(line, ta.TextStyle)
+	for i, style := range ta.lineStyles(lineIdx, len(cells)) {
+		cells[i].Style = style
+	}
+	return cells
+}
+
+// runeCells returns one cell per rune of s, as the cursor counts them.
+func runeCells(s string, style ui.Style) []ui.Cell {
+	runes := []rune(s)
+	cells := make([]ui.Cell, len(runes))
+	for i, r := range runes {
+		cells[i] = ui.NewCell(r, style)
+	}
+	return cells
+}
+
+// lineStart returns the column a line starts at: 0, or for right-to-left
+// text where it ends at the right edge with room for the cursor after it.
+func (ta *TextArea) lineStart(cells []ui.Cell, dir ui.Direction, width int) int {
+	if dir != ui.DirectionRTL {
+		return 0
+	}
+	w := 0
+	for _, c := range cells {
+		w += c.ColumnWidth()
+	}
+	return width - w
+}
+
+// lineStyles returns the style of each of the n runes of a line.
+func (ta *TextArea) lineStyles(lineIdx, n int) []ui.Style {
+	styles := make([]ui.Style, 0, n)
+	if ta.Lexer != nil {
+		for _, tok := range ta.highlighter.Line(lineIdx) {
+			style := ta.Syntax.Style(tok.Kind)
+			for range tok.Text {
+				styles = append(styles, style)
+			}
+		}
+	}
+	for len(styles) < n {
+		styles = append(styles, ta.TextStyle)
+	}
+	return styles
+}
+
 func (ta *TextArea) drawCursor(buf *ui.Buffer, lines []string, width, height int) {
 	if ta.ShowCursor {
 		cursorY := ta.Cursor.Y - ta.topLine
 		cursorX := 0
 		if ta.Cursor.Y < len(lines) {
-			line := []rune(lines[ta.Cursor.Y])
-			for i := 0; i < ta.Cursor.X && i < len(line); i++ {
-				cursorX += rw.RuneWidth(line[i])
+			cells := ta.lineCells(ta.Cursor.Y, lines[ta.Cursor.Y])
+			dir := ui.ResolveDirection(ta.Direction, cells)
+			caret := ui.CaretPosition(cells, dir, ta.Cursor.X)
+			cursorX = ta.lineStart(cells, dir, width) + min(caret, 0)
+			for _, c := range ui.ReorderCells(cells, dir)[:max(caret, 0)] {
+				cursorX += c.ColumnWidth()
 			}
 		}
 		innerRect := ta.Inner
@@ -121,6 +204,11 @@ func (ta *TextArea) MoveCursor(dx, dy int) {
 	if newX > lineLen {
 		newX = lineLen
 	}
+	if dx != 0 && dy == 0 {
+		// Move across the line as it is shown, which for right-to-left
+		// runs is against the order of the text.
+		newX = ui.MoveCaret(runeCells(lines[newY], ui.StyleClear), ta.Direction, min(ta.Cursor.X, lineLen), dx)
+	}
 	ta.Cursor = image.Point{newX, newY}
 }
 
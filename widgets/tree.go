@@ -5,7 +5,6 @@ import (
 	"image"
 	"strings"
 
-	rw "github.com/mattn/go-runewidth"
 	ui "github.com/metaspartan/gotui/v5"
 )
 
@@ -22,16 +21,16 @@ type TreeNode struct {
 // TreeWalkFn is a function that is called for each node in the tree during a walk.
 type TreeWalkFn func(*TreeNode) bool
 
-func (tn *TreeNode) parseStyles(style ui.Style) []ui.Cell {
+func (tn *TreeNode) parseStyles(style ui.Style, theme *ui.RootTheme) []ui.Cell {
 	var sb strings.Builder
 	if len(tn.Nodes) == 0 {
 		sb.WriteString(strings.Repeat(treeIndent, tn.level+1))
 	} else {
 		sb.WriteString(strings.Repeat(treeIndent, tn.level))
 		if tn.Expanded {
-			sb.WriteRune(ui.Theme.Tree.Expanded)
+			sb.WriteRune(theme.Tree.Expanded)
 		} else {
-			sb.WriteRune(ui.Theme.Tree.Collapsed)
+			sb.WriteRune(theme.Tree.Collapsed)
 		}
 		sb.WriteByte(' ')
 	}
@@ -53,10 +52,16 @@ type Tree struct {
 
 // NewTree returns a new Tree.
 func NewTree() *Tree {
+	return NewTreeWithContext(ui.DefaultContext)
+}
+
+// NewTreeWithContext returns a new Tree bound to ctx.
+func NewTreeWithContext(ctx *ui.Context) *Tree {
+	theme := ctx.CurrentTheme()
 	return &Tree{
-		Block:            *ui.NewBlock(),
-		TextStyle:        ui.Theme.Tree.Text,
-		SelectedRowStyle: ui.Theme.Tree.Text,
+		Block:            *ui.NewBlockWithContext(ctx),
+		TextStyle:        theme.Tree.Text,
+		SelectedRowStyle: theme.Tree.Text,
 		WrapText:         true,
 	}
 }
@@ -102,8 +107,18 @@ func (t *Tree) walk(n *TreeNode, fn TreeWalkFn) bool {
 	return true
 }
 
+func (t *Tree) restyle() {
+	old, cur, ok := t.ThemeChanged()
+	if !ok {
+		return
+	}
+	ui.Restyle(&t.TextStyle, old.Tree.Text, cur.Tree.Text)
+	ui.Restyle(&t.SelectedRowStyle, old.Tree.Text, cur.Tree.Text)
+}
+
 // Draw draws the tree to the buffer.
 func (t *Tree) Draw(buf *ui.Buffer) {
+	t.restyle()
 	t.Block.Draw(buf)
 	point := t.Inner.Min
 	if t.SelectedRow >= t.Inner.Dy()+t.topRow {
@@ -112,7 +127,7 @@ func (t *Tree) Draw(buf *ui.Buffer) {
 		t.topRow = t.SelectedRow
 	}
 	for row := t.topRow; row < len(t.rows) && point.Y < t.Inner.Max.Y; row++ {
-		cells := t.rows[row].parseStyles(t.TextStyle)
+		cells := t.rows[row].parseStyles(t.TextStyle, t.Theme())
 		if t.WrapText {
 			cells = ui.WrapCells(cells, uint(t.Inner.Dx()))
 		}
@@ -124,8 +139,10 @@ func (t *Tree) Draw(buf *ui.Buffer) {
 			if point.X+1 == t.Inner.Max.X+1 && len(cells) > t.Inner.Dx() {
 				buf.SetCell(ui.NewCell(ui.ELLIPSES, style), point.Add(image.Pt(-1, 0)))
 			} else {
-				buf.SetCell(ui.NewCell(cells[j].Rune, style), point)
-				point = point.Add(image.Pt(rw.RuneWidth(cells[j].Rune), 0))
+				cell := cells[j]
+				cell.Style = style
+				buf.SetCell(cell, point)
+				point = point.Add(image.Pt(cell.ColumnWidth(), 0))
 			}
 		}
 		point = image.Pt(t.Inner.Min.X, point.Y+1)
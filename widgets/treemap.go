@@ -24,8 +24,13 @@ type TreeMap struct {
 
 // NewTreeMap returns a new TreeMap.
 func NewTreeMap() *TreeMap {
+	return NewTreeMapWithContext(ui.DefaultContext)
+}
+
+// NewTreeMapWithContext returns a new TreeMap bound to ctx.
+func NewTreeMapWithContext(ctx *ui.Context) *TreeMap {
 	return &TreeMap{
-		Block:     *ui.NewBlock(),
+		Block:     *ui.NewBlockWithContext(ctx),
 		TextColor: ui.ColorWhite,
 	}
 }
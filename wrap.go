@@ -0,0 +1,196 @@
+package gotui
+
+import "github.com/rivo/uniseg"
+
+// WrapCells wraps a slice of cells to a given width, with a '\n' cell
+// between lines. See BreakLines.
+func WrapCells(cells []Cell, width uint) []Cell {
+	var wrapped []Cell
+	for i, line := range BreakLines(cells, int(width), WrapOptions{}) {
+		if i > 0 {
+			wrapped = append(wrapped, Cell{Rune: '\n', Style: StyleClear})
+		}
+		wrapped = append(wrapped, line...)
+	}
+	return wrapped
+}
+
+// BreakLines splits cells into lines at most width columns wide. Lines
+// break where the Unicode line breaking algorithm (UAX #14) allows, such
+// as after spaces and hyphens and between ideographs, and always after a
+// line feed, which is dropped; like SplitCells, a line feed at the end
+// does not start another line. Spaces at the end of a wrapped line are
+// dropped too, and every other cell keeps its style.
+func BreakLines(cells []Cell, width int, opts WrapOptions) [][]Cell {
+	w := wrapper{width: max(width, 1), opts: opts}
+	for _, seg := range lineSegments(cells) {
+		hard := mandatoryBreak(seg[len(seg)-1])
+		if hard {
+			seg = seg[:len(seg)-1]
+		}
+		w.add(seg)
+		if hard {
+			w.flush(false)
+		}
+	}
+	if len(w.line) > 0 {
+		w.flush(false)
+	}
+	lines := w.lines
+	if opts.MaxLines > 0 && len(lines) > opts.MaxLines {
+		lines = lines[:opts.MaxLines]
+		if opts.Ellipsis {
+			last := lines[len(lines)-1]
+			lines[len(lines)-1] = ellipsize(last, max(width, 1))
+		}
+	}
+	return lines
+}
+
+type wrapper struct {
+	width     int
+	opts      WrapOptions
+	lines     [][]Cell
+	line      []Cell
+	lineWidth int
+}
+
+// add adds a run of cells that must not be broken, other than when it is a
+// word too long for any line.
+func (w *wrapper) add(seg []Cell) {
+	word := trimTrailingSpace(seg)
+	wordWidth := CellsWidth(word)
+	if w.lineWidth > 0 && w.lineWidth+wordWidth > w.width {
+		w.flush(true)
+	}
+	if w.opts.BreakLongWords {
+		for w.lineWidth+CellsWidth(word) > w.width {
+			n := w.fit(word)
+			w.line = append(w.line, word[:n]...)
+			if w.opts.Hyphen && w.width > 1 && n < len(word) {
+				w.line = append(w.line, Cell{Rune: HYPHEN, Style: word[n-1].Style})
+			}
+			w.flush(false)
+			word, seg = word[n:], seg[n:]
+			if len(word) == 0 {
+				// Only the spaces after the word are left, and they would
+				// start the next line.
+				return
+			}
+		}
+	}
+	w.line = append(w.line, seg...)
+	w.lineWidth += CellsWidth(seg)
+}
+
+// fit returns how many cells of word fill the rest of the line, leaving
+// room for a hyphen. It is at least one, so that a wide cell on a narrow
+// line still moves on.
+func (w *wrapper) fit(word []Cell) int {
+	room := w.width - w.lineWidth
+	if w.opts.Hyphen && w.width > 1 {
+		room--
+	}
+	n, used := 0, 0
+	for n < len(word) && used+word[n].ColumnWidth() <= room {
+		used += word[n].ColumnWidth()
+		n++
+	}
+	return max(n, 1)
+}
+
+func (w *wrapper) flush(soft bool) {
+	line := w.line
+	if soft {
+		line = trimTrailingSpace(line)
+	}
+	w.lines = append(w.lines, line)
+	w.line, w.lineWidth = nil, 0
+}
+
+// lineSegments splits cells after each place where UAX #14 allows a line
+// break.
+func lineSegments(cells []Cell) [][]Cell {
+	var segs [][]Cell
+	text := CellsToString(cells)
+	start, next, end, offset := 0, 0, 0, 0
+	state := -1
+	for text != "" {
+		var cluster string
+		var boundaries int
+		cluster, text, boundaries, state = uniseg.StepString(text, state)
+		offset += len(cluster)
+		for next < len(cells) && end < offset {
+			end += len(cells[next].Text())
+			next++
+		}
+		// A break inside a cell, which holds more than one cluster, is
+		// passed over.
+		if end != offset || boundaries&uniseg.MaskLine == uniseg.LineDontBreak {
+			continue
+		}
+		segs = append(segs, cells[start:next])
+		start = next
+	}
+	if start < len(cells) {
+		segs = append(segs, cells[start:])
+	}
+	return segs
+}
+
+// mandatoryBreak reports whether c is one of the characters that UAX #14
+// always breaks a line after.
+func mandatoryBreak(c Cell) bool {
+	switch c.Rune {
+	case '\n', '\r', '\v', '\f', '\u0085', '\u2028', '\u2029':
+		return true
+	}
+	return false
+}
+
+func trimTrailingSpace(cells []Cell) []Cell {
+	for len(cells) > 0 && cells[len(cells)-1].Rune == ' ' && cells[len(cells)-1].Grapheme == "" {
+		cells = cells[:len(cells)-1]
+	}
+	return cells
+}
+
+// CellsWidth returns the number of terminal columns the cells take up.
+func CellsWidth(cells []Cell) int {
+	w := 0
+	for _, c := range cells {
+		w += c.ColumnWidth()
+	}
+	return w
+}
+
+// TrimCells trims a slice of cells to a given width, ending it with an
+// ellipsis when it is cut.
+func TrimCells(cells []Cell, w int) []Cell {
+	if w <= 0 {
+		return []Cell{}
+	}
+	if CellsWidth(cells) <= w {
+		return cells
+	}
+	return ellipsize(cells, w)
+}
+
+// ellipsize cuts cells to leave room for an ELLIPSES within w columns and
+// ends them with it, in the style of the first cell cut or else the last.
+func ellipsize(cells []Cell, w int) []Cell {
+	width, n := 0, 0
+	for n < len(cells) && width+cells[n].ColumnWidth() <= w-1 {
+		width += cells[n].ColumnWidth()
+		n++
+	}
+	style := StyleClear
+	switch {
+	case n < len(cells):
+		style = cells[n].Style
+	case n > 0:
+		style = cells[n-1].Style
+	}
+	out := append(make([]Cell, 0, n+1), trimTrailingSpace(cells[:n])...)
+	return append(out, Cell{Rune: ELLIPSES, Style: style})
+}